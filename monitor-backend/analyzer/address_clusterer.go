@@ -0,0 +1,327 @@
+package analyzer
+
+import (
+	"errors"
+	"ethereum-monitor/config"
+	"ethereum-monitor/database"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AddressClusterer 地址聚类：把同一交易所 / 机构控制的多个地址归并为实体
+// 以已有成员为起点运行链上启发式：
+//  1. 共同资金来源：与成员的第一笔 ETH 来自同一个非公共地址
+//  2. 充值地址归集：交易所热钱包的转入方，其转出几乎全部归集到该热钱包
+//  3. 共同支出：交易流水中多次与成员在同一区块向同一地址转账
+//
+// 交易所、混币器、路由等公共地址会资助 / 接收大量无关地址，不作为关联依据
+type AddressClusterer struct {
+	explorer     *utils.ExplorerClient
+	repo         *database.AddressClusterRepository
+	transferRepo *database.TransferRecordRepository
+}
+
+// NewAddressClusterer 创建地址聚类器
+func NewAddressClusterer(explorerURL, apiKey string) *AddressClusterer {
+	return &AddressClusterer{
+		explorer:     utils.NewExplorerClient(explorerURL, apiKey),
+		repo:         database.NewAddressClusterRepository(),
+		transferRepo: database.NewTransferRecordRepository(),
+	}
+}
+
+// Run 执行一轮聚类：扩展一批成员地址，再按交易流水关联共同支出，最后刷新注册表
+func (c *AddressClusterer) Run() {
+	candidates, err := c.repo.GetExpandCandidates(time.Now().Add(-config.ClusterExpandInterval), config.ClusterExpandBatchSize)
+	if err != nil {
+		logger.Log.Error("获取待扩展成员地址失败", zap.Error(err))
+		return
+	}
+
+	linked := 0
+	for _, member := range candidates {
+		linked += c.expandMember(member)
+		if err := c.repo.MarkExpanded(member.Address, time.Now()); err != nil {
+			logger.Log.Warn("记录成员扩展时间失败", zap.String("address", member.Address), zap.Error(err))
+		}
+	}
+
+	coSpenders, err := c.linkCoSpenders()
+	if err != nil {
+		logger.Log.Warn("共同支出关联失败", zap.Error(err))
+	}
+	linked += coSpenders
+
+	if err := database.GetAddressClusterRegistry().Reload(); err != nil {
+		logger.Log.Error("刷新地址实体注册表失败", zap.Error(err))
+	}
+
+	logger.Log.Info("地址聚类完成",
+		zap.Int("expanded", len(candidates)),
+		zap.Int("linked", linked))
+}
+
+// expandMember 以一个成员地址为起点运行启发式，返回新关联的地址数
+func (c *AddressClusterer) expandMember(member *model.AddressClusterMember) int {
+	cluster, err := c.repo.GetByID(member.ClusterID)
+	if err != nil {
+		logger.Log.Warn("读取成员所属实体失败", zap.String("address", member.Address), zap.Error(err))
+		return 0
+	}
+
+	linked := 0
+	count, err := c.linkCommonFunder(member)
+	if err != nil {
+		logger.Log.Warn("共同资金来源关联失败", zap.String("address", member.Address), zap.Error(err))
+	}
+	linked += count
+
+	// 只有交易所热钱包（种子或手动添加）才有充值地址归集
+	if cluster.Category == model.AddressCategoryExchange &&
+		(member.Heuristic == model.ClusterHeuristicSeed || member.Heuristic == model.ClusterHeuristicManual) {
+		count, err := c.linkDepositSweeps(member)
+		if err != nil {
+			logger.Log.Warn("充值地址归集关联失败", zap.String("address", member.Address), zap.Error(err))
+		}
+		linked += count
+	}
+	return linked
+}
+
+// linkCommonFunder 找出与成员第一笔资金来源相同的地址
+func (c *AddressClusterer) linkCommonFunder(member *model.AddressClusterMember) (int, error) {
+	funder, err := firstFunder(c.explorer, member.Address)
+	if err != nil {
+		return 0, err
+	}
+	if funder == "" || isPublicAddress(funder) {
+		return 0, nil
+	}
+
+	txs, err := c.explorer.GetTransactions(funder, config.ClusterFunderScanTxs)
+	if err != nil {
+		return 0, err
+	}
+
+	linked, checked := 0, 0
+	seen := map[string]bool{member.Address: true}
+	for _, tx := range txs {
+		if checked >= config.ClusterMaxFundedChecks {
+			break
+		}
+		recipient := strings.ToLower(tx.To)
+		if !strings.EqualFold(tx.From, funder) || tx.IsError == "1" || recipient == "" || seen[recipient] {
+			continue
+		}
+		value, ok := new(big.Int).SetString(tx.Value, 10)
+		if !ok || value.Sign() == 0 {
+			continue
+		}
+		seen[recipient] = true
+		if isPublicAddress(recipient) || c.inCluster(recipient, member.ClusterID) {
+			continue
+		}
+
+		checked++
+		recipientFunder, err := firstFunder(c.explorer, recipient)
+		if err != nil {
+			logger.Log.Debug("查询资金来源失败", zap.String("address", recipient), zap.Error(err))
+			continue
+		}
+		if recipientFunder != funder {
+			continue
+		}
+		if c.link(member.ClusterID, recipient, model.ClusterHeuristicCommonFunder, "共同资金来源 "+funder) {
+			linked++
+		}
+	}
+	return linked, nil
+}
+
+// linkDepositSweeps 找出把资金归集到热钱包的充值地址
+func (c *AddressClusterer) linkDepositSweeps(hotWallet *model.AddressClusterMember) (int, error) {
+	incoming, err := c.recentTransfers(hotWallet.Address)
+	if err != nil {
+		return 0, err
+	}
+
+	linked, checked := 0, 0
+	seen := make(map[string]bool)
+	for _, tx := range incoming {
+		if checked >= config.ClusterMaxSweepChecks {
+			break
+		}
+		sender := strings.ToLower(tx.From)
+		if !strings.EqualFold(tx.To, hotWallet.Address) || sender == hotWallet.Address || seen[sender] {
+			continue
+		}
+		seen[sender] = true
+		if isPublicAddress(sender) || c.inCluster(sender, hotWallet.ClusterID) {
+			continue
+		}
+
+		checked++
+		sweeps, outgoing, err := c.sweepRatio(sender, hotWallet.Address)
+		if err != nil {
+			logger.Log.Debug("查询候选充值地址失败", zap.String("address", sender), zap.Error(err))
+			continue
+		}
+		if outgoing < config.ClusterSweepMinTxs || float64(sweeps)/float64(outgoing) < config.ClusterSweepMinRatio {
+			continue
+		}
+		evidence := fmt.Sprintf("归集到 %s（%d/%d 笔转出）", hotWallet.Address, sweeps, outgoing)
+		if c.link(hotWallet.ClusterID, sender, model.ClusterHeuristicDepositSweep, evidence) {
+			linked++
+		}
+	}
+	return linked, nil
+}
+
+// sweepRatio 统计地址最近的转出中归集到目标地址的笔数
+func (c *AddressClusterer) sweepRatio(address, target string) (sweeps, outgoing int, err error) {
+	txs, err := c.recentTransfers(address)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, tx := range txs {
+		if !strings.EqualFold(tx.From, address) || tx.IsError == "1" {
+			continue
+		}
+		outgoing++
+		if strings.EqualFold(tx.To, target) {
+			sweeps++
+		}
+	}
+	return sweeps, outgoing, nil
+}
+
+// recentTransfers 地址最近的普通交易与代币转账
+func (c *AddressClusterer) recentTransfers(address string) ([]utils.ExplorerTx, error) {
+	txs, err := c.explorer.GetRecentTransactions(address, config.ClusterSweepScanTxs)
+	if err != nil {
+		return nil, err
+	}
+	tokenTxs, err := c.explorer.GetRecentTokenTransfers(address, config.ClusterSweepScanTxs)
+	if err != nil {
+		return nil, err
+	}
+	return append(txs, tokenTxs...), nil
+}
+
+// linkCoSpenders 关联交易流水中多次在同一区块向同一私有地址转账的发送方
+// 两个地址都不属于任何实体时新建自动实体
+func (c *AddressClusterer) linkCoSpenders() (int, error) {
+	pairs, err := c.transferRepo.GetCoSpendPairs(time.Now().Add(-config.ClusterCoSpendLookback), config.ClusterCoSpendMinBlocks)
+	if err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	for _, pair := range pairs {
+		if isPublicAddress(pair.AddressA) || isPublicAddress(pair.AddressB) {
+			continue
+		}
+		// 交易流水都涉及监控的热钱包，共同收款方往往就是交易所本身；向交易所、其充值地址或其他公共地址转账的
+		// 是互不相关的用户，不能作为关联依据
+		if isPublicAddress(pair.Recipient) || isEntityAddress(pair.Recipient) {
+			continue
+		}
+		evidence := fmt.Sprintf("%d 个区块内与 %s 向 %s 转账", pair.Blocks, pair.AddressA, pair.Recipient)
+
+		memberA, errA := c.repo.GetMemberByAddress(pair.AddressA)
+		memberB, errB := c.repo.GetMemberByAddress(pair.AddressB)
+		if errA != nil && !errors.Is(errA, gorm.ErrRecordNotFound) {
+			return linked, errA
+		}
+		if errB != nil && !errors.Is(errB, gorm.ErrRecordNotFound) {
+			return linked, errB
+		}
+
+		switch {
+		case errA == nil && errB == nil && memberA.ClusterID == memberB.ClusterID:
+			continue
+		case errA == nil:
+			if c.link(memberA.ClusterID, pair.AddressB, model.ClusterHeuristicCoSpending, evidence) {
+				linked++
+			}
+		case errB == nil:
+			evidence = fmt.Sprintf("%d 个区块内与 %s 向 %s 转账", pair.Blocks, pair.AddressB, pair.Recipient)
+			if c.link(memberB.ClusterID, pair.AddressA, model.ClusterHeuristicCoSpending, evidence) {
+				linked++
+			}
+		default:
+			cluster := &model.AddressCluster{
+				Name:     database.NewAutoClusterName(pair.AddressA),
+				Category: model.AddressCategoryOther,
+				Auto:     true,
+			}
+			members := []*model.AddressClusterMember{
+				{Address: pair.AddressA, Heuristic: model.ClusterHeuristicCoSpending, Evidence: fmt.Sprintf("%d 个区块内与 %s 向 %s 转账", pair.Blocks, pair.AddressB, pair.Recipient)},
+				{Address: pair.AddressB, Heuristic: model.ClusterHeuristicCoSpending, Evidence: evidence},
+			}
+			if err := c.repo.Create(cluster, members); err != nil {
+				logger.Log.Warn("创建自动实体失败", zap.String("a", pair.AddressA), zap.String("b", pair.AddressB), zap.Error(err))
+				continue
+			}
+			linked += 2
+		}
+	}
+	return linked, nil
+}
+
+// link 关联地址到实体，成功（含合并）时返回 true；两个已命名实体之间的关联只记录日志
+func (c *AddressClusterer) link(clusterID uint, address, heuristic, evidence string) bool {
+	survivor, err := c.repo.Link(clusterID, &model.AddressClusterMember{
+		Address:   address,
+		Heuristic: heuristic,
+		Evidence:  evidence,
+	})
+	if errors.Is(err, database.ErrClusterConflict) {
+		logger.Log.Info("地址已属于其他已命名实体，跳过合并",
+			zap.String("address", address),
+			zap.Uint("cluster", clusterID),
+			zap.String("heuristic", heuristic))
+		return false
+	}
+	if err != nil {
+		logger.Log.Warn("关联地址到实体失败", zap.String("address", address), zap.Error(err))
+		return false
+	}
+
+	logger.Log.Info("🔗 地址关联到实体",
+		zap.String("address", address),
+		zap.Uint("cluster", survivor),
+		zap.String("heuristic", heuristic),
+		zap.String("evidence", evidence))
+	return true
+}
+
+// inCluster 地址是否已属于该实体
+func (c *AddressClusterer) inCluster(address string, clusterID uint) bool {
+	member, err := c.repo.GetMemberByAddress(address)
+	return err == nil && member.ClusterID == clusterID
+}
+
+// isPublicAddress 交易所、混币器、路由等公共地址（地址标签注册表中有标签）
+func isPublicAddress(address string) bool {
+	_, ok := database.GetAddressLabelRegistry().Lookup(address)
+	return ok
+}
+
+// isEntityAddress 地址是否为钱包监控地址或已属于某个实体（如交易所的充值地址）
+func isEntityAddress(address string) bool {
+	address = strings.ToLower(address)
+	if _, ok := config.WalletEntities[address]; ok {
+		return true
+	}
+	_, ok := database.GetAddressClusterRegistry().Lookup(address)
+	return ok
+}
@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"ethereum-monitor/config"
+	"ethereum-monitor/database"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// 回测结局标签
+const (
+	OutcomeRugged = "rugged"
+	OutcomeMoon   = "10x"
+	OutcomeDead   = "dead"
+	OutcomeAlive  = "alive"
+)
+
+// BacktestSample 回测样本：决策时的代币状态与之后的结局
+type BacktestSample struct {
+	Analysis model.TokenAnalysis // 首个快照记录的决策时分析记录
+	Outcome  string
+}
+
+// BacktestResult 某个规则集 × 阈值组合的回测结果
+// 通过 = 非蜜罐、非 critical 且风险分低于阈值（与 SafetyScanner + IsLowRisk 一致）
+type BacktestResult struct {
+	Version   string
+	Threshold float64
+
+	Passed       int // 通过筛选的数量
+	PassedMoon   int // 通过且 10x
+	PassedRugged int // 通过且 Rug
+	Moons        int // 样本中 10x 总数
+	Rugged       int // 样本中 Rug 总数
+
+	Precision    float64 // PassedMoon / Passed
+	Recall       float64 // PassedMoon / Moons
+	RugRate      float64 // PassedRugged / Passed
+	RugCatchRate float64 // 被拦截的 Rug 占全部 Rug 的比例
+}
+
+// LoadBacktestSamples 加载回测样本
+// 决策时状态取首个快照记录的分析记录（之后更新的蜜罐、权限、部署者等字段不参与评分）；
+// 只取已结束跟踪（EXPIRED / RUGGED）的代币，仍在跟踪窗口内的结局未定；没有决策时记录的代币跳过
+func LoadBacktestSamples() ([]BacktestSample, error) {
+	tokenRepo := database.NewTokenAnalysisRepository()
+	snapshotRepo := database.NewTokenSnapshotRepository()
+
+	var samples []BacktestSample
+	err := tokenRepo.FindInBatches(200, func(batch []model.TokenAnalysis) error {
+		for _, analysis := range batch {
+			if analysis.Status != model.TokenStatusExpired && analysis.Status != model.TokenStatusRugged {
+				continue
+			}
+			snapshots, err := snapshotRepo.GetByToken(analysis.TokenAddress)
+			if err != nil {
+				return err
+			}
+			if len(snapshots) == 0 || snapshots[0].DecisionInputs == "" {
+				continue
+			}
+
+			var decision model.TokenAnalysis
+			if err := json.Unmarshal([]byte(snapshots[0].DecisionInputs), &decision); err != nil {
+				logger.Log.Warn("解析决策时分析记录失败", zap.String("token", analysis.TokenAddress), zap.Error(err))
+				continue
+			}
+
+			samples = append(samples, BacktestSample{
+				Analysis: decision,
+				Outcome:  labelOutcome(&analysis, snapshots),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load backtest samples: %w", err)
+	}
+	return samples, nil
+}
+
+// labelOutcome 根据后续快照标注结局
+// 按时间顺序，先到达 10x 还是先 Rug 以先发生者为准（先 10x 后 Rug 期间可止盈）
+func labelOutcome(analysis *model.TokenAnalysis, snapshots []model.TokenSnapshot) string {
+	startPrice := snapshots[0].PriceETH
+	for _, snapshot := range snapshots {
+		if snapshot.Status == model.TokenStatusRugged {
+			return OutcomeRugged
+		}
+		if startPrice > 0 && snapshot.PriceETH >= startPrice*config.BacktestMoonMultiple {
+			return OutcomeMoon
+		}
+	}
+	if analysis.Status == model.TokenStatusRugged {
+		return OutcomeRugged
+	}
+	if snapshots[len(snapshots)-1].LiquidityUSD < config.BacktestDeadLiquidityUSD {
+		return OutcomeDead
+	}
+	return OutcomeAlive
+}
+
+// RunBacktest 对每个规则集 × 阈值组合计算筛选效果
+func RunBacktest(samples []BacktestSample, ruleSets []*RiskRuleSet, thresholds []float64) []BacktestResult {
+	var results []BacktestResult
+	for _, ruleSet := range ruleSets {
+		// 同一规则集下评分与阈值无关，只算一次
+		scores := make([]float64, len(samples))
+		levels := make([]string, len(samples))
+		for i := range samples {
+			scores[i], levels[i], _ = ruleSet.Evaluate(&samples[i].Analysis)
+		}
+
+		for _, threshold := range thresholds {
+			result := BacktestResult{Version: ruleSet.Version, Threshold: threshold}
+			for i, sample := range samples {
+				passed := !sample.Analysis.IsHoneypot && levels[i] != "critical" && scores[i] < threshold
+				switch sample.Outcome {
+				case OutcomeMoon:
+					result.Moons++
+				case OutcomeRugged:
+					result.Rugged++
+				}
+				if !passed {
+					continue
+				}
+				result.Passed++
+				switch sample.Outcome {
+				case OutcomeMoon:
+					result.PassedMoon++
+				case OutcomeRugged:
+					result.PassedRugged++
+				}
+			}
+
+			result.Precision = ratio(result.PassedMoon, result.Passed)
+			result.Recall = ratio(result.PassedMoon, result.Moons)
+			result.RugRate = ratio(result.PassedRugged, result.Passed)
+			result.RugCatchRate = ratio(result.Rugged-result.PassedRugged, result.Rugged)
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// ratio 计算比例，分母为 0 时返回 0
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	opPush1  = 0x60
+	opPush4  = 0x63
+	opPush32 = 0x7f
+)
+
+// EIP-1167 最小代理运行时字节码：前缀 + 20 字节实现地址 + 后缀
+var (
+	eip1167Prefix = common.FromHex("0x363d3d373d3d3d363d73")
+	eip1167Suffix = common.FromHex("0x5af43d82803e903d91602b57fd5bf3")
+)
+
+// selectorOf 计算函数签名的 4 字节选择器，如 "mint(address,uint256)" -> "40c10f19"
+func selectorOf(signature string) string {
+	return hex.EncodeToString(crypto.Keccak256([]byte(signature))[:4])
+}
+
+// extractSelectors 从运行时字节码中提取 PUSH4 常量
+// Solidity/Vyper 的函数分发器通过 PUSH4 <selector> EQ 匹配调用，
+// 因此合约对外暴露的函数选择器都会以 PUSH4 常量出现在字节码中
+func extractSelectors(code []byte) map[string]struct{} {
+	selectors := make(map[string]struct{})
+	for i := 0; i < len(code); i++ {
+		op := code[i]
+		if op < opPush1 || op > opPush32 {
+			continue
+		}
+
+		size := int(op-opPush1) + 1
+		if op == opPush4 && i+4 < len(code) {
+			selectors[hex.EncodeToString(code[i+1:i+5])] = struct{}{}
+		}
+		// 跳过 PUSH 的立即数，避免把数据误当作操作码
+		i += size
+	}
+	return selectors
+}
+
+// hasAnySelector 判断字节码选择器集合中是否包含任一函数签名
+func hasAnySelector(selectors map[string]struct{}, signatures []string) bool {
+	for _, sig := range signatures {
+		if _, ok := selectors[selectorOf(sig)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAllSelectors 判断字节码选择器集合中是否包含全部函数签名
+func hasAllSelectors(selectors map[string]struct{}, signatures []string) bool {
+	for _, sig := range signatures {
+		if _, ok := selectors[selectorOf(sig)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// minimalProxyTarget 若字节码是 EIP-1167 最小代理，返回其实现合约地址
+func minimalProxyTarget(code []byte) (common.Address, bool) {
+	size := len(eip1167Prefix) + common.AddressLength + len(eip1167Suffix)
+	if len(code) != size || !bytes.HasPrefix(code, eip1167Prefix) || !bytes.HasSuffix(code, eip1167Suffix) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(code[len(eip1167Prefix) : len(eip1167Prefix)+common.AddressLength]), true
+}
@@ -0,0 +1,223 @@
+package analyzer
+
+import (
+	"context"
+	"ethereum-monitor/config"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ERC165 接口 ID
+var (
+	erc165InterfaceID  = common.FromHex("0x01ffc9a7")
+	erc721InterfaceID  = common.FromHex("0x80ac58cd")
+	erc1155InterfaceID = common.FromHex("0xd9b67a26")
+	invalidInterfaceID = common.FromHex("0xffffffff")
+)
+
+// masterCopy() 选择器，Gnosis Safe 代理的字节码中会出现
+const safeMasterCopySelector = "a619486e"
+
+// erc20Signatures ERC20 标准要求的全部外部函数
+var erc20Signatures = []string{
+	"totalSupply()",
+	"balanceOf(address)",
+	"transfer(address,uint256)",
+	"transferFrom(address,address,uint256)",
+	"approve(address,uint256)",
+	"allowance(address,address)",
+}
+
+// contractFingerprint 合约类型的字节码指纹：需包含全部函数签名
+type contractFingerprint struct {
+	contractType string
+	signatures   []string
+}
+
+// contractFingerprints 按优先级排列（ERC721 也有 balanceOf/approve 等，需先于 ERC20 判断）
+var contractFingerprints = []contractFingerprint{
+	{model.ContractTypeERC1155, []string{
+		"balanceOfBatch(address[],uint256[])",
+		"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)",
+		"setApprovalForAll(address,bool)",
+	}},
+	{model.ContractTypeERC721, []string{
+		"ownerOf(uint256)",
+		"safeTransferFrom(address,address,uint256)",
+		"getApproved(uint256)",
+		"setApprovalForAll(address,bool)",
+	}},
+	{model.ContractTypeERC20, erc20Signatures},
+	{model.ContractTypeGnosisSafe, []string{
+		"getOwners()",
+		"getThreshold()",
+		"execTransaction(address,uint256,bytes,uint8,uint256,uint256,uint256,address,address,bytes)",
+	}},
+	{model.ContractTypeFactory, []string{
+		"createPair(address,address)",
+		"getPair(address,address)",
+		"allPairsLength()",
+	}},
+	{model.ContractTypeRouter, []string{
+		"factory()",
+		"WETH()",
+		"addLiquidityETH(address,uint256,uint256,uint256,address,uint256)",
+		"swapExactETHForTokens(uint256,address[],address,uint256)",
+	}},
+}
+
+// ContractClassification 合约分类结果
+type ContractClassification struct {
+	ContractType          string
+	ProxyType             string
+	ImplementationAddress string
+}
+
+// ContractClassifier 合约分类器
+// 先解析代理（EIP-1167、EIP-1967、Gnosis Safe 代理）得到实现合约字节码，再按选择器指纹分类，
+// 指纹无法识别时回退到 ERC165 supportsInterface 探测
+type ContractClassifier struct {
+	client *ethclient.Client
+
+	// fingerprintCache 按字节码哈希缓存指纹分类结果
+	cacheMu          sync.Mutex
+	fingerprintCache map[common.Hash]string
+}
+
+// NewContractClassifier 创建合约分类器
+func NewContractClassifier(rpcURL string) (*ContractClassifier, error) {
+	client, err := utils.DialRateLimitedEthClient(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial rpc: %w", err)
+	}
+	return &ContractClassifier{client: client, fingerprintCache: make(map[common.Hash]string)}, nil
+}
+
+// Classify 识别合约类型
+func (c *ContractClassifier) Classify(contractAddress string) (*ContractClassification, error) {
+	ctx := context.Background()
+	addr := common.HexToAddress(contractAddress)
+
+	code, err := c.client.CodeAt(ctx, addr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code: %w", err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("not a contract address")
+	}
+
+	result := &ContractClassification{ContractType: model.ContractTypeUnknown}
+
+	// 1. 代理解析
+	impl, proxyType := c.resolveProxy(ctx, addr, code)
+	if proxyType != "" {
+		result.ProxyType = proxyType
+		result.ImplementationAddress = strings.ToLower(impl.Hex())
+		if implCode, err := c.client.CodeAt(ctx, impl, nil); err == nil && len(implCode) > 0 {
+			code = implCode
+		}
+	}
+
+	// 2. 字节码指纹
+	result.ContractType = c.fingerprint(code)
+
+	// 3. ERC165 探测（调用发往代理地址，由代理转发到实现合约）
+	if result.ContractType == model.ContractTypeUnknown {
+		if c.supportsERC165(ctx, addr) {
+			if c.supportsInterface(ctx, addr, erc1155InterfaceID) {
+				result.ContractType = model.ContractTypeERC1155
+			} else if c.supportsInterface(ctx, addr, erc721InterfaceID) {
+				result.ContractType = model.ContractTypeERC721
+			}
+		}
+	}
+
+	if result.ContractType == model.ContractTypeUnknown && result.ProxyType != "" {
+		result.ContractType = model.ContractTypeProxy
+	}
+
+	return result, nil
+}
+
+// resolveProxy 识别代理并返回实现合约地址，非代理返回空类型
+func (c *ContractClassifier) resolveProxy(ctx context.Context, addr common.Address, code []byte) (common.Address, string) {
+	if impl, ok := minimalProxyTarget(code); ok {
+		return impl, model.ProxyTypeEIP1167
+	}
+
+	if slot, err := c.client.StorageAt(ctx, addr, eip1967ImplementationSlot, nil); err == nil {
+		if impl := common.BytesToAddress(slot); impl != (common.Address{}) {
+			return impl, model.ProxyTypeEIP1967
+		}
+	}
+
+	// Gnosis Safe 代理字节码很短，只实现 masterCopy() 并转发其余调用，实现地址存于 slot 0
+	if _, ok := extractSelectors(code)[safeMasterCopySelector]; ok && len(code) < 1024 {
+		if slot, err := c.client.StorageAt(ctx, addr, common.Hash{}, nil); err == nil {
+			if impl := common.BytesToAddress(slot); impl != (common.Address{}) {
+				return impl, model.ProxyTypeSafeProxy
+			}
+		}
+	}
+
+	return common.Address{}, ""
+}
+
+// fingerprint 按选择器指纹识别合约类型（带缓存）
+func (c *ContractClassifier) fingerprint(code []byte) string {
+	hash := crypto.Keccak256Hash(code)
+
+	c.cacheMu.Lock()
+	contractType, ok := c.fingerprintCache[hash]
+	c.cacheMu.Unlock()
+	if ok {
+		return contractType
+	}
+
+	contractType = model.ContractTypeUnknown
+	selectors := extractSelectors(code)
+	for _, fp := range contractFingerprints {
+		if hasAllSelectors(selectors, fp.signatures) {
+			contractType = fp.contractType
+			break
+		}
+	}
+
+	c.cacheMu.Lock()
+	if len(c.fingerprintCache) >= config.ContractClassCacheSize {
+		c.fingerprintCache = make(map[common.Hash]string)
+	}
+	c.fingerprintCache[hash] = contractType
+	c.cacheMu.Unlock()
+
+	return contractType
+}
+
+// supportsERC165 按 EIP-165 规范确认合约实现了 supportsInterface：
+// 对 0x01ffc9a7 返回 true，且对 0xffffffff 返回 false
+func (c *ContractClassifier) supportsERC165(ctx context.Context, addr common.Address) bool {
+	return c.supportsInterface(ctx, addr, erc165InterfaceID) && !c.supportsInterface(ctx, addr, invalidInterfaceID)
+}
+
+// supportsInterface 调用 supportsInterface(bytes4)
+func (c *ContractClassifier) supportsInterface(ctx context.Context, addr common.Address, interfaceID []byte) bool {
+	data := append(common.Hex2Bytes(selectorOf("supportsInterface(bytes4)")), common.RightPadBytes(interfaceID, 32)...)
+	result, err := c.client.CallContract(ctx, ethereum.CallMsg{To: &addr, Data: data}, nil)
+	if err != nil || len(result) < 32 {
+		return false
+	}
+	return result[31] == 1
+}
+
+// Close 关闭
+func (c *ContractClassifier) Close() {
+	c.client.Close()
+}
@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// 源码可疑模式
+const (
+	FindingUnboundedFeeSetter = "unbounded_fee_setter" // 税率设置函数没有上限校验
+	FindingHiddenFeeSetter    = "hidden_fee_setter"    // 函数名不含 fee/tax 却修改税率变量
+	FindingOwnerTransferGate  = "owner_transfer_gate"  // 转账逻辑受 owner / 交易开关 / 白名单控制
+	FindingTransferBlacklist  = "transfer_blacklist"   // 转账逻辑检查黑名单
+	FindingSelfdestruct       = "selfdestruct"
+	FindingDelegatecall       = "delegatecall"
+)
+
+// sourceFindingOrder 可疑模式的输出顺序
+var sourceFindingOrder = []string{
+	FindingUnboundedFeeSetter,
+	FindingHiddenFeeSetter,
+	FindingOwnerTransferGate,
+	FindingTransferBlacklist,
+	FindingSelfdestruct,
+	FindingDelegatecall,
+}
+
+var (
+	solidityCommentPattern  = regexp.MustCompile(`(?s)//[^\n]*|/\*.*?\*/`)
+	solidityFunctionPattern = regexp.MustCompile(`function\s+(\w+)\s*\([^)]*\)([^{;]*)\{`)
+	feeNamePattern          = regexp.MustCompile(`(?i)(fee|tax)`)
+	feeAssignPattern        = regexp.MustCompile(`(?i)\b\w*(fee|tax)\w*\s*=[^=]`)
+	ownerGatePattern        = regexp.MustCompile(`require\s*\([^;]*(owner\(\)|_owner|tradingOpen|tradingEnabled|tradingActive|whitelist|_isExcluded)`)
+	blacklistCheckPattern   = regexp.MustCompile(`(?i)require\s*\(\s*!\s*\w*(bot|black)\w*\s*\[`)
+	selfdestructPattern     = regexp.MustCompile(`\bselfdestruct\s*\(`)
+	delegatecallPattern     = regexp.MustCompile(`\.delegatecall\s*\(`)
+)
+
+// transferFunctionNames 转账相关的内部/外部函数
+var transferFunctionNames = map[string]bool{
+	"transfer":             true,
+	"transferFrom":         true,
+	"_transfer":            true,
+	"_update":              true,
+	"_beforeTokenTransfer": true,
+}
+
+// ContractVerifier 合约源码验证检查器
+// 通过区块浏览器获取验证状态、编译器版本、许可证与 ABI，并扫描已验证源码中的可疑模式
+type ContractVerifier struct {
+	explorer *utils.ExplorerClient
+}
+
+// NewContractVerifier 创建合约源码验证检查器
+func NewContractVerifier(explorerURL, apiKey string) *ContractVerifier {
+	return &ContractVerifier{
+		explorer: utils.NewExplorerClient(explorerURL, apiKey),
+	}
+}
+
+// Analyze 检查源码验证状态并填充 analysis 的对应字段
+func (v *ContractVerifier) Analyze(analysis *model.TokenAnalysis) error {
+	source, err := v.explorer.GetContractSource(analysis.TokenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to get contract source: %w", err)
+	}
+
+	analysis.IsVerified = source.IsVerified
+	if !source.IsVerified {
+		return nil
+	}
+	analysis.CompilerVersion = source.CompilerVersion
+	analysis.LicenseType = source.LicenseType
+
+	code := source.SourceCode
+	// 代理合约的业务逻辑在实现合约中，一并扫描
+	if source.IsProxy && source.Implementation != "" {
+		if impl, err := v.explorer.GetContractSource(source.Implementation); err == nil && impl.IsVerified {
+			code += "\n" + impl.SourceCode
+		}
+	}
+
+	applySocialLinks(analysis, extractSourceSocials(code))
+
+	findings := scanSourceCode(code)
+	findingsJSON, _ := json.Marshal(findings)
+	analysis.SourceFindings = string(findingsJSON)
+
+	logger.Log.Info("源码验证检查完成",
+		zap.String("token", analysis.TokenAddress),
+		zap.String("contract", source.ContractName),
+		zap.String("compiler", source.CompilerVersion),
+		zap.Strings("findings", findings))
+
+	return nil
+}
+
+// scanSourceCode 扫描 Solidity 源码中的可疑模式
+func scanSourceCode(source string) []string {
+	source = solidityCommentPattern.ReplaceAllString(source, "")
+	hits := make(map[string]bool)
+
+	for _, loc := range solidityFunctionPattern.FindAllStringSubmatchIndex(source, -1) {
+		name := source[loc[2]:loc[3]]
+		modifiers := source[loc[4]:loc[5]]
+		body := functionBody(source, loc[1]-1)
+		ownerOnly := strings.Contains(modifiers, "onlyOwner")
+
+		if ownerOnly && feeAssignPattern.MatchString(body) {
+			if !feeNamePattern.MatchString(name) {
+				hits[FindingHiddenFeeSetter] = true
+			} else if !strings.Contains(body, "require") {
+				hits[FindingUnboundedFeeSetter] = true
+			}
+		}
+
+		if transferFunctionNames[name] {
+			if ownerGatePattern.MatchString(body) {
+				hits[FindingOwnerTransferGate] = true
+			}
+			if blacklistCheckPattern.MatchString(body) {
+				hits[FindingTransferBlacklist] = true
+			}
+		}
+	}
+
+	if selfdestructPattern.MatchString(source) {
+		hits[FindingSelfdestruct] = true
+	}
+	if delegatecallPattern.MatchString(source) {
+		hits[FindingDelegatecall] = true
+	}
+
+	findings := []string{}
+	for _, finding := range sourceFindingOrder {
+		if hits[finding] {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// functionBody 从左花括号位置开始按括号配对截取函数体
+func functionBody(source string, open int) string {
+	depth := 0
+	for i := open; i < len(source); i++ {
+		switch source[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return source[open : i+1]
+			}
+		}
+	}
+	return source[open:]
+}
@@ -0,0 +1,214 @@
+package analyzer
+
+import (
+	"errors"
+	"ethereum-monitor/config"
+	"ethereum-monitor/database"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DeployerProfiler 部署者画像分析器
+// 汇总部署者的发币记录与结局，追溯资金来源，计算信誉风险分
+type DeployerProfiler struct {
+	explorer *utils.ExplorerClient
+	repo     *database.DeployerProfileRepository
+}
+
+// NewDeployerProfiler 创建部署者画像分析器
+func NewDeployerProfiler(explorerURL, apiKey string) *DeployerProfiler {
+	return &DeployerProfiler{
+		explorer: utils.NewExplorerClient(explorerURL, apiKey),
+		repo:     database.NewDeployerProfileRepository(),
+	}
+}
+
+// Analyze 更新部署者画像，并把信誉风险分写入 analysis
+// 需要在持有者分析之后调用（由其确定 DeployerAddress）
+func (p *DeployerProfiler) Analyze(analysis *model.TokenAnalysis) error {
+	deployer := strings.ToLower(analysis.DeployerAddress)
+	if deployer == "" {
+		return nil
+	}
+
+	profile, err := p.repo.GetByAddress(deployer)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to load deployer profile: %w", err)
+		}
+		profile = &model.DeployerProfile{DeployerAddress: deployer}
+	}
+
+	// 1. 发币记录与结局（不含当前代币）
+	survivedBefore := time.Now().Add(-config.DeployerSurvivalHours * time.Hour)
+	stats, err := p.repo.GetOutcomeStats(deployer, analysis.TokenAddress, survivedBefore)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate deployer outcomes: %w", err)
+	}
+	profile.TokenCount = stats.TokenCount
+	profile.RuggedCount = stats.RuggedCount
+	profile.HoneypotCount = stats.HoneypotCount
+	profile.SurvivedCount = stats.SurvivedCount
+
+	// 2. GoPlus 同创建者蜜罐标记（一旦命中不再清除）
+	if analysis.CreatorHoneypotFlag {
+		profile.SameCreatorHoneypot = true
+	} else if !profile.SameCreatorHoneypot {
+		if flagged, err := p.repo.HasSameCreatorHoneypot(deployer); err == nil {
+			profile.SameCreatorHoneypot = flagged
+		}
+	}
+
+	// 3. 资金来源（历史不可变，只追溯一次）
+	if profile.FundingTracedAt == nil {
+		if err := p.traceFunding(profile); err != nil {
+			logger.Log.Warn("部署者资金来源追溯失败", zap.String("deployer", deployer), zap.Error(err))
+		} else {
+			now := time.Now()
+			profile.FundingTracedAt = &now
+		}
+	}
+
+	profile.ReputationScore = calculateReputationScore(profile)
+	profile.Summary = summarizeDeployer(profile)
+
+	if err := p.repo.Save(profile); err != nil {
+		return fmt.Errorf("failed to save deployer profile: %w", err)
+	}
+
+	analysis.DeployerReputationScore = profile.ReputationScore
+	analysis.DeployerSummary = profile.Summary
+
+	logger.Log.Info("部署者画像更新完成",
+		zap.String("deployer", deployer),
+		zap.Int("tokens", profile.TokenCount),
+		zap.Int("rugged", profile.RuggedCount),
+		zap.Int("honeypots", profile.HoneypotCount),
+		zap.String("funding", profile.FundingLabel),
+		zap.Float64("reputation", profile.ReputationScore))
+
+	return nil
+}
+
+// traceFunding 向上追溯资金来源，最多 DeployerFundingMaxHops 跳
+// 命中地址标签注册表中的混币器或交易所即停止；未命中时记录第一跳的资金来源地址
+func (p *DeployerProfiler) traceFunding(profile *model.DeployerProfile) error {
+	current := profile.DeployerAddress
+	for hop := 1; hop <= config.DeployerFundingMaxHops; hop++ {
+		funder, err := firstFunder(p.explorer, current)
+		if err != nil {
+			return err
+		}
+		if funder == "" {
+			return nil
+		}
+		if hop == 1 {
+			profile.FundingSource = funder
+		}
+		if label, category, ok := knownFundingSource(funder); ok {
+			profile.FundingSource = funder
+			profile.FundingLabel = label
+			profile.FundingCategory = category
+			profile.FundingHops = hop
+			return nil
+		}
+		current = funder
+	}
+	return nil
+}
+
+// knownFundingSource 按地址标签注册表识别资金来源：混币器或中心化交易所
+func knownFundingSource(address string) (label, category string, ok bool) {
+	entry, found := database.GetAddressLabelRegistry().Lookup(address)
+	if !found {
+		return "", "", false
+	}
+	switch entry.Category {
+	case model.AddressCategoryMixer:
+		return entry.Name, config.FundingCategoryMixer, true
+	case model.AddressCategoryExchange:
+		return entry.Name, config.FundingCategoryCEX, true
+	}
+	return "", "", false
+}
+
+// firstFunder 查找地址收到的第一笔 ETH 的来源（同时查普通交易与内部交易）
+func firstFunder(explorer *utils.ExplorerClient, address string) (string, error) {
+	txs, err := explorer.GetTransactions(address, config.DeployerFundingTxLookup)
+	if err != nil {
+		return "", err
+	}
+	internalTxs, err := explorer.GetInternalTransactions(address, config.DeployerFundingTxLookup)
+	if err != nil {
+		return "", err
+	}
+
+	var funder string
+	var funderBlock uint64
+	for _, tx := range append(txs, internalTxs...) {
+		if !strings.EqualFold(tx.To, address) || tx.IsError == "1" {
+			continue
+		}
+		value, ok := new(big.Int).SetString(tx.Value, 10)
+		if !ok || value.Sign() == 0 {
+			continue
+		}
+		block, _ := strconv.ParseUint(tx.BlockNumber, 10, 64)
+		if funder == "" || block < funderBlock {
+			funder = strings.ToLower(tx.From)
+			funderBlock = block
+		}
+	}
+	return funder, nil
+}
+
+// calculateReputationScore 计算部署者信誉风险分（0-100，越高越可疑）
+func calculateReputationScore(profile *model.DeployerProfile) float64 {
+	score := float64(profile.RuggedCount)*config.DeployerRepRugged +
+		float64(profile.HoneypotCount)*config.DeployerRepHoneypot -
+		float64(profile.SurvivedCount)*config.DeployerRepSurvivedBonus
+
+	if profile.SameCreatorHoneypot {
+		score += config.DeployerRepSameCreatorHoneypot
+	}
+	if profile.FundingCategory == config.FundingCategoryMixer {
+		score += config.DeployerRepMixerFunded
+	}
+	if profile.TokenCount >= config.DeployerSerialLaunchThreshold {
+		score += config.DeployerRepSerialLauncher
+	}
+
+	if score < 0 {
+		score = 0
+	}
+	if score > 100 {
+		score = 100
+	}
+	return score
+}
+
+// summarizeDeployer 生成部署者画像摘要
+func summarizeDeployer(profile *model.DeployerProfile) string {
+	parts := []string{
+		fmt.Sprintf("发币 %d", profile.TokenCount),
+		fmt.Sprintf("Rug %d", profile.RuggedCount),
+		fmt.Sprintf("蜜罐 %d", profile.HoneypotCount),
+		fmt.Sprintf("存活 %d", profile.SurvivedCount),
+	}
+	if profile.SameCreatorHoneypot {
+		parts = append(parts, "GoPlus 标记发过蜜罐")
+	}
+	if profile.FundingLabel != "" {
+		parts = append(parts, fmt.Sprintf("资金来自 %s (%d 跳)", profile.FundingLabel, profile.FundingHops))
+	}
+	return strings.Join(parts, " / ")
+}
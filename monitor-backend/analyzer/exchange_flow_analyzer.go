@@ -0,0 +1,261 @@
+package analyzer
+
+import (
+	"ethereum-monitor/config"
+	"ethereum-monitor/database"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ExchangeFlowAnalyzer 交易所净流量统计
+// 把钱包监控的交易流水按实体、币种汇总为小时桶（转入为流入交易所，转出为流出），
+// 按 1h / 24h / 7d 滚动窗口计算净流量，与历史同长度窗口比较的 z-score 超过阈值时告警。
+// 只统计超过通知阈值的转账（go-ethereum 监控也记录小额转账），净流量反映的是大额资金动向
+type ExchangeFlowAnalyzer struct {
+	repo         *database.ExchangeFlowRepository
+	transferRepo *database.TransferRecordRepository
+	pushPlus     *utils.PushPlusNotifier
+	wechat       *utils.WechatNotifier
+}
+
+// NewExchangeFlowAnalyzer 创建交易所净流量统计器
+func NewExchangeFlowAnalyzer() *ExchangeFlowAnalyzer {
+	var pushPlus *utils.PushPlusNotifier
+	if token := os.Getenv("PUSHPLUS_TOKEN"); token != "" {
+		pushPlus = utils.NewPushPlusNotifier(token)
+	}
+	var wechat *utils.WechatNotifier
+	if webhook := os.Getenv("WECHAT_WEBHOOK_URL"); webhook != "" {
+		wechat = utils.NewWechatNotifier(webhook)
+	}
+
+	return &ExchangeFlowAnalyzer{
+		repo:         database.NewExchangeFlowRepository(),
+		transferRepo: database.NewTransferRecordRepository(),
+		pushPlus:     pushPlus,
+		wechat:       wechat,
+	}
+}
+
+// Run 汇总新增流水并检查各窗口净流量
+func (a *ExchangeFlowAnalyzer) Run() {
+	if err := a.aggregate(); err != nil {
+		logger.Log.Error("汇总交易所净流量失败", zap.Error(err))
+		return
+	}
+	a.checkAlerts(time.Now())
+}
+
+// aggregate 重新汇总最近几个小时桶；还没有任何桶时汇总全部历史流水
+func (a *ExchangeFlowAnalyzer) aggregate() error {
+	latest, err := a.repo.GetLatestBucketStart()
+	if err != nil {
+		return fmt.Errorf("failed to load latest flow bucket: %w", err)
+	}
+	var since time.Time
+	if !latest.IsZero() {
+		since = latest.Add(-config.FlowBucketSize * (config.FlowRecomputeBuckets - 1))
+	}
+
+	records, err := a.transferRepo.GetSinceBlockTime(since)
+	if err != nil {
+		return fmt.Errorf("failed to load transfer records: %w", err)
+	}
+	return a.repo.SaveBuckets(aggregateFlows(records, since))
+}
+
+// checkAlerts 检查所有实体、币种与窗口，|z| 达到阈值且该窗口长度内未告警过时告警
+func (a *ExchangeFlowAnalyzer) checkAlerts(now time.Time) {
+	end := database.FlowWindowEnd(now)
+	var longest time.Duration
+	for _, window := range config.FlowWindows {
+		if window.Duration > longest {
+			longest = window.Duration
+		}
+	}
+
+	pairs, err := a.repo.GetPairs(end.Add(-longest))
+	if err != nil {
+		logger.Log.Error("获取净流量实体失败", zap.Error(err))
+		return
+	}
+
+	thresholds := config.GetFlowZScoreThresholds()
+	for _, pair := range pairs {
+		for _, window := range config.FlowWindows {
+			stat, err := a.repo.GetWindowStat(pair.Entity, pair.Currency, window, end)
+			if err != nil {
+				logger.Log.Warn("计算净流量窗口失败",
+					zap.String("entity", pair.Entity),
+					zap.String("currency", pair.Currency),
+					zap.String("window", window.Name),
+					zap.Error(err))
+				continue
+			}
+			threshold := thresholds[window.Name]
+			if !stat.HasBaseline || math.Abs(stat.ZScore) < threshold {
+				continue
+			}
+			if a.repo.HasRecentAlert(pair.Entity, pair.Currency, window.Name, now.Add(-window.Duration)) {
+				continue
+			}
+			if err := a.alert(stat, threshold); err != nil {
+				logger.Log.Error("保存净流量告警失败", zap.String("entity", pair.Entity), zap.Error(err))
+			}
+		}
+	}
+}
+
+// alert 推送并记录一次净流量告警
+func (a *ExchangeFlowAnalyzer) alert(stat *database.FlowWindowStat, threshold float64) error {
+	emoji, direction := "📈", "净流入"
+	if stat.NetFlow < 0 {
+		emoji, direction = "📉", "净流出"
+	}
+
+	title := fmt.Sprintf("%s %s %s %s %s异常", emoji, stat.Entity, stat.Currency, stat.Window, direction)
+	content := fmt.Sprintf(`## 交易所净流量异常
+
+**实体**: %s
+**币种**: %s
+**窗口**: %s（%s ~ %s UTC）
+**%s**: %.2f %s
+**流入 / 流出**: %.2f (%d 笔) / %.2f (%d 笔)
+**历史均值**: %.2f，标准差 %.2f（%d 个窗口）
+**z-score**: %.2f（阈值 %.1f）`,
+		stat.Entity,
+		stat.Currency,
+		stat.Window,
+		stat.WindowStart.Format("01-02 15:04"),
+		stat.WindowEnd.Format("01-02 15:04"),
+		direction,
+		math.Abs(stat.NetFlow),
+		stat.Currency,
+		stat.Inflow, stat.InflowCount,
+		stat.Outflow, stat.OutflowCount,
+		stat.Mean,
+		stat.StdDev,
+		stat.Samples,
+		stat.ZScore,
+		threshold)
+
+	logger.Log.Warn("🚨 交易所净流量异常",
+		zap.String("entity", stat.Entity),
+		zap.String("currency", stat.Currency),
+		zap.String("window", stat.Window),
+		zap.Float64("netFlow", stat.NetFlow),
+		zap.Float64("zScore", stat.ZScore))
+
+	status := "skipped"
+	var errs []string
+	if a.pushPlus != nil {
+		status = "success"
+		if err := a.pushPlus.Send(title, content); err != nil {
+			errs = append(errs, "pushplus: "+err.Error())
+		}
+	}
+	if a.wechat != nil {
+		status = "success"
+		if err := a.wechat.SendMarkdown(content); err != nil {
+			errs = append(errs, "wechat: "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		status = "failed"
+		logger.Log.Error("发送净流量告警失败", zap.Strings("errors", errs))
+	}
+
+	return a.repo.CreateAlert(&model.ExchangeFlowAlert{
+		Entity:      stat.Entity,
+		Currency:    stat.Currency,
+		Window:      stat.Window,
+		WindowStart: stat.WindowStart,
+		WindowEnd:   stat.WindowEnd,
+		NetFlow:     stat.NetFlow,
+		Mean:        stat.Mean,
+		StdDev:      stat.StdDev,
+		ZScore:      stat.ZScore,
+		Threshold:   threshold,
+		Samples:     stat.Samples,
+		Content:     content,
+		Status:      status,
+		ErrorMsg:    strings.Join(errs, "; "),
+	})
+}
+
+// aggregateFlows 把交易流水按出块时间汇总为小时桶，小额转账与实体内部调拨不计入
+// 早于 since 的桶不在本次重算范围内（只含迟到的部分流水，覆盖会丢掉其余流水），直接跳过
+func aggregateFlows(records []*model.TransferRecord, since time.Time) []*model.ExchangeFlow {
+	type bucketKey struct {
+		entity, currency string
+		start            time.Time
+	}
+	buckets := make(map[bucketKey]*model.ExchangeFlow)
+
+	for _, record := range records {
+		if !record.AboveThreshold || record.IntraCluster {
+			continue
+		}
+		start := flowTime(record).UTC().Truncate(config.FlowBucketSize)
+		if start.Before(since) {
+			continue
+		}
+		amount, err := strconv.ParseFloat(record.Amount, 64)
+		if err != nil {
+			continue
+		}
+
+		key := bucketKey{
+			entity:   flowEntity(record),
+			currency: record.Currency,
+			start:    start,
+		}
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &model.ExchangeFlow{Entity: key.entity, Currency: key.currency, BucketStart: key.start}
+			buckets[key] = bucket
+		}
+		switch record.Direction {
+		case "转入":
+			bucket.Inflow += amount
+			bucket.InflowCount++
+		case "转出":
+			bucket.Outflow += amount
+			bucket.OutflowCount++
+		}
+		bucket.NetFlow = bucket.Inflow - bucket.Outflow
+	}
+
+	flows := make([]*model.ExchangeFlow, 0, len(buckets))
+	for _, bucket := range buckets {
+		flows = append(flows, bucket)
+	}
+	sort.Slice(flows, func(i, j int) bool { return flows[i].BucketStart.Before(flows[j].BucketStart) })
+	return flows
+}
+
+// flowTime 流水的出块时间，未能获取时退回入库时间
+func flowTime(record *model.TransferRecord) time.Time {
+	if !record.BlockTime.IsZero() {
+		return record.BlockTime
+	}
+	return record.CreatedAt
+}
+
+// flowEntity 流水所属实体：地址实体名称，不属于任何实体时为监控地址标签
+func flowEntity(record *model.TransferRecord) string {
+	if record.ClusterName != "" {
+		return record.ClusterName
+	}
+	return record.MonitorLabel
+}
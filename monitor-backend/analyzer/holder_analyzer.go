@@ -0,0 +1,377 @@
+package analyzer
+
+import (
+	"context"
+	"ethereum-monitor/config"
+	"ethereum-monitor/database"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// HolderAnalyzer 持有者分析器
+// 从代币创建区块开始回放 Transfer 日志，维护每个地址的余额，
+// 扫描进度保存在 TokenAnalysis.HolderScannedBlock，之后的分析只回放新增区块
+type HolderAnalyzer struct {
+	client         *ethclient.Client
+	holderRepo     *database.TokenHolderRepository
+	deploymentRepo *database.ContractDeploymentRepository
+}
+
+// NewHolderAnalyzer 创建持有者分析器
+func NewHolderAnalyzer(rpcURL string) (*HolderAnalyzer, error) {
+	client, err := utils.DialRateLimitedEthClient(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial rpc: %w", err)
+	}
+	return &HolderAnalyzer{
+		client:         client,
+		holderRepo:     database.NewTokenHolderRepository(),
+		deploymentRepo: database.NewContractDeploymentRepository(),
+	}, nil
+}
+
+// Analyze 增量回放 Transfer 日志，并填充 analysis 的持有者数量、前10占比和部署者占比
+func (h *HolderAnalyzer) Analyze(analysis *model.TokenAnalysis) error {
+	ctx := context.Background()
+	token := common.HexToAddress(analysis.TokenAddress)
+
+	latest, err := h.client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	// 1. 首次分析：定位创建区块和部署者
+	// 游标为 0 时从创建区块完整回放，先清掉残留的余额行（游标未能落库的上次扫描），避免重复累加
+	start := analysis.HolderScannedBlock + 1
+	if analysis.HolderScannedBlock == 0 {
+		if analysis.CreationBlock == 0 && !h.locateCreation(ctx, analysis, token, latest) {
+			// 从中途回放会出现负余额、漏掉早期持有者，宁可不给出持有者统计
+			analysis.HolderDataUnavailable = true
+			logger.Log.Warn("无法定位代币创建区块，跳过持有者分析", zap.String("token", analysis.TokenAddress))
+			return nil
+		}
+		start = analysis.CreationBlock
+		if err := h.holderRepo.DeleteByToken(analysis.TokenAddress); err != nil {
+			return fmt.Errorf("failed to reset holder balances: %w", err)
+		}
+	}
+
+	// 2. 载入已索引的余额
+	balances, err := h.loadBalances(analysis.TokenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to load holder balances: %w", err)
+	}
+
+	// 3. 分段回放新区块的 Transfer 日志，每段完成后立即落库
+	transferTopic := common.HexToHash(config.ERC20TransferTopic)
+	for from := start; from <= latest; from += config.HolderScanChunkBlocks {
+		to := from + config.HolderScanChunkBlocks - 1
+		if to > latest {
+			to = latest
+		}
+
+		logs, err := h.client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{token},
+			Topics:    [][]common.Hash{{transferTopic}},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to filter transfer logs [%d, %d]: %w", from, to, err)
+		}
+
+		// 无法从创建交易确定部署者时，以首笔铸币的接收方作为部署者
+		if analysis.DeployerAddress == "" {
+			if minter := firstMintRecipient(logs); minter != "" {
+				analysis.DeployerAddress = minter
+			}
+		}
+
+		changed := applyTransferLogs(balances, logs)
+		if err := h.holderRepo.SaveProgress(analysis.TokenAddress, toHolderRecords(balances, changed), to); err != nil {
+			return fmt.Errorf("failed to save holder progress: %w", err)
+		}
+		analysis.HolderScannedBlock = to
+	}
+
+	// 4. 计算持有者统计
+	h.fillStats(analysis, balances)
+	analysis.HolderDataUnavailable = false
+
+	logger.Log.Info("持有者分析完成",
+		zap.String("token", analysis.TokenAddress),
+		zap.Int("holders", analysis.HolderCount),
+		zap.Float64("top10Pct", analysis.Top10HoldingPct),
+		zap.Float64("deployerPct", analysis.DeployerHoldingPct),
+		zap.Uint64("scannedBlock", analysis.HolderScannedBlock))
+
+	return nil
+}
+
+// locateCreation 定位代币创建区块与部署者，无法定位时返回 false
+func (h *HolderAnalyzer) locateCreation(ctx context.Context, analysis *model.TokenAnalysis, token common.Address, latest uint64) bool {
+	// 优先使用合约部署记录
+	if deployment, err := h.deploymentRepo.GetByAddress(analysis.TokenAddress); err == nil && deployment.BlockNumber > 0 {
+		analysis.CreationBlock = deployment.BlockNumber
+		if analysis.DeployerAddress == "" {
+			analysis.DeployerAddress = strings.ToLower(deployment.DeployerAddress)
+		}
+		return true
+	}
+
+	block, err := h.findCreationBlock(ctx, token, latest)
+	if err != nil {
+		// 非归档节点无法查询较早的历史状态
+		logger.Log.Warn("查找代币创建区块失败",
+			zap.String("token", analysis.TokenAddress),
+			zap.Error(err))
+		return false
+	}
+
+	analysis.CreationBlock = block
+	if analysis.DeployerAddress == "" {
+		analysis.DeployerAddress = h.findDeployer(ctx, token, block)
+	}
+	return true
+}
+
+// findCreationBlock 查找合约代码首次出现的区块
+// 先从最新区块指数回退找到无代码的区块，再二分查找，新币只需要查询最近的少量区块
+func (h *HolderAnalyzer) findCreationBlock(ctx context.Context, token common.Address, latest uint64) (uint64, error) {
+	code, err := h.client.CodeAt(ctx, token, new(big.Int).SetUint64(latest))
+	if err != nil {
+		return 0, err
+	}
+	if len(code) == 0 {
+		return 0, fmt.Errorf("no contract code at %s", token.Hex())
+	}
+
+	// 不变式：hi 处有代码，lo 处无代码
+	hi, lo := latest, uint64(0)
+	for step := uint64(1); step <= hi; step *= 2 {
+		probe := hi - step
+		code, err := h.client.CodeAt(ctx, token, new(big.Int).SetUint64(probe))
+		if err != nil {
+			return 0, err
+		}
+		if len(code) == 0 {
+			lo = probe
+			break
+		}
+		hi = probe
+	}
+
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		code, err := h.client.CodeAt(ctx, token, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, err
+		}
+		if len(code) > 0 {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	return hi, nil
+}
+
+// findDeployer 从创建区块的回执中找到部署交易的发送方
+// 由工厂合约创建的代币在回执中没有 ContractAddress，返回空字符串
+func (h *HolderAnalyzer) findDeployer(ctx context.Context, token common.Address, blockNumber uint64) string {
+	receipts, err := h.client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(blockNumber)))
+	if err != nil {
+		return ""
+	}
+
+	for _, receipt := range receipts {
+		if receipt.ContractAddress != token {
+			continue
+		}
+		tx, _, err := h.client.TransactionByHash(ctx, receipt.TxHash)
+		if err != nil {
+			return ""
+		}
+		from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			return ""
+		}
+		return strings.ToLower(from.Hex())
+	}
+	return ""
+}
+
+// loadBalances 从数据库载入已索引的余额
+func (h *HolderAnalyzer) loadBalances(tokenAddress string) (map[common.Address]*big.Int, error) {
+	holders, err := h.holderRepo.GetByToken(tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[common.Address]*big.Int, len(holders))
+	for _, holder := range holders {
+		balance, ok := new(big.Int).SetString(holder.Balance, 10)
+		if !ok {
+			continue
+		}
+		balances[common.HexToAddress(holder.HolderAddress)] = balance
+	}
+	return balances, nil
+}
+
+// fillStats 根据余额计算持有者统计
+func (h *HolderAnalyzer) fillStats(analysis *model.TokenAnalysis, balances map[common.Address]*big.Int) {
+	excluded := concentrationExcludedAddresses(analysis)
+
+	supply, ok := new(big.Int).SetString(analysis.TotalSupply, 10)
+	if !ok || supply.Sign() == 0 {
+		// 未读取到 totalSupply 时，以已铸造的余额总和代替
+		supply = new(big.Int)
+		for _, balance := range balances {
+			if balance.Sign() > 0 {
+				supply.Add(supply, balance)
+			}
+		}
+	}
+
+	holderCount := 0
+	var ranked []*big.Int
+	for addr, balance := range balances {
+		if balance.Sign() <= 0 || isBurnAddress(addr) {
+			continue
+		}
+		holderCount++
+		if _, skip := excluded[addr]; skip {
+			continue
+		}
+		ranked = append(ranked, balance)
+	}
+
+	sort.Slice(ranked, func(i, j int) bool { return ranked[i].Cmp(ranked[j]) > 0 })
+	top10 := new(big.Int)
+	for i := 0; i < len(ranked) && i < 10; i++ {
+		top10.Add(top10, ranked[i])
+	}
+
+	analysis.HolderCount = holderCount
+	analysis.Top10HoldingPct = percentOf(top10, supply)
+
+	if analysis.DeployerAddress != "" {
+		if balance, ok := balances[common.HexToAddress(analysis.DeployerAddress)]; ok {
+			analysis.DeployerHoldingPct = percentOf(balance, supply)
+		} else {
+			analysis.DeployerHoldingPct = 0
+		}
+	}
+}
+
+// Close 关闭
+func (h *HolderAnalyzer) Close() {
+	h.client.Close()
+}
+
+// applyTransferLogs 将 Transfer 日志应用到余额表，返回发生变化的地址
+func applyTransferLogs(balances map[common.Address]*big.Int, logs []types.Log) map[common.Address]struct{} {
+	changed := make(map[common.Address]struct{})
+	for _, vLog := range logs {
+		// ERC721 的 Transfer 有 4 个 topic，这里只处理 ERC20
+		if len(vLog.Topics) != 3 || vLog.Removed {
+			continue
+		}
+
+		from := common.BytesToAddress(vLog.Topics[1].Bytes())
+		to := common.BytesToAddress(vLog.Topics[2].Bytes())
+		value := new(big.Int).SetBytes(vLog.Data)
+
+		// 铸币（from 为零地址）不扣减发送方
+		if from != (common.Address{}) {
+			addBalance(balances, from, new(big.Int).Neg(value))
+			changed[from] = struct{}{}
+		}
+		addBalance(balances, to, value)
+		changed[to] = struct{}{}
+	}
+	return changed
+}
+
+// addBalance 累加余额
+func addBalance(balances map[common.Address]*big.Int, addr common.Address, delta *big.Int) {
+	if balance, ok := balances[addr]; ok {
+		balance.Add(balance, delta)
+		return
+	}
+	balances[addr] = new(big.Int).Set(delta)
+}
+
+// toHolderRecords 将变化的余额转换为数据库记录
+func toHolderRecords(balances map[common.Address]*big.Int, changed map[common.Address]struct{}) []model.TokenHolder {
+	records := make([]model.TokenHolder, 0, len(changed))
+	for addr := range changed {
+		records = append(records, model.TokenHolder{
+			HolderAddress: strings.ToLower(addr.Hex()),
+			Balance:       balances[addr].String(),
+		})
+	}
+	return records
+}
+
+// firstMintRecipient 返回日志中首笔铸币的接收方（跳过被重组移除的日志）
+func firstMintRecipient(logs []types.Log) string {
+	for _, vLog := range logs {
+		if len(vLog.Topics) != 3 || vLog.Removed {
+			continue
+		}
+		if common.BytesToAddress(vLog.Topics[1].Bytes()) == (common.Address{}) {
+			return strings.ToLower(common.BytesToAddress(vLog.Topics[2].Bytes()).Hex())
+		}
+	}
+	return ""
+}
+
+// concentrationExcludedAddresses 计算集中度时排除的地址：交易对、销毁地址、锁仓合约
+func concentrationExcludedAddresses(analysis *model.TokenAnalysis) map[common.Address]struct{} {
+	excluded := make(map[common.Address]struct{})
+	if analysis.PairAddress != "" {
+		excluded[common.HexToAddress(analysis.PairAddress)] = struct{}{}
+	}
+	for _, addr := range config.BurnAddresses {
+		excluded[common.HexToAddress(addr)] = struct{}{}
+	}
+	for addr := range config.LiquidityLockerAddresses {
+		excluded[common.HexToAddress(addr)] = struct{}{}
+	}
+	return excluded
+}
+
+// isBurnAddress 判断是否是销毁地址
+func isBurnAddress(addr common.Address) bool {
+	for _, burn := range config.BurnAddresses {
+		if common.HexToAddress(burn) == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// percentOf 计算 part / total 的百分比
+func percentOf(part, total *big.Int) float64 {
+	if total == nil || total.Sign() == 0 {
+		return 0
+	}
+	ratio := new(big.Float).Quo(new(big.Float).SetInt(part), new(big.Float).SetInt(total))
+	pct, _ := ratio.Mul(ratio, big.NewFloat(100)).Float64()
+	return pct
+}
@@ -3,16 +3,51 @@ package analyzer
 import (
 	"encoding/json"
 	"ethereum-monitor/config"
+	"ethereum-monitor/database"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
+
+	"go.uber.org/zap"
+)
+
+// 蜜罐检测结果来源
+const (
+	HoneypotSourceGoPlus     = "goplus"
+	HoneypotSourceHoneypotIs = "honeypot.is"
+	HoneypotSourceSimulation = "simulation"
+	HoneypotSourceConsensus  = "consensus" // 多个服务有数据，按裁决策略合并
 )
 
+// SecurityProvider 代币安全检测服务
+type SecurityProvider interface {
+	// Name 服务名，与 HoneypotSource* 一致
+	Name() string
+	// Check 检测代币；服务未收录时返回 DataNotFound 的结果而不是错误
+	Check(tokenAddress string) (*HoneypotResult, error)
+}
+
 // HoneypotDetector 蜜罐检测器
+// 依次请求各安全检测服务，保存原始报告，按裁决策略合并结论，并按代币缓存结论
 type HoneypotDetector struct {
-	httpClient *http.Client
-	apiKey     string // GoPlus API Key（可选）
+	providers  []SecurityProvider // 按优先级排列
+	policy     string
+	reportRepo *database.TokenSecurityReportRepository
+
+	cacheMu sync.Mutex
+	cache   map[string]honeypotCacheEntry
+}
+
+// honeypotCacheEntry 缓存的检测结论
+type honeypotCacheEntry struct {
+	result    *HoneypotResult
+	expiresAt time.Time
 }
 
 // HoneypotResult 蜜罐检测结果
@@ -23,36 +58,237 @@ type HoneypotResult struct {
 	SellTax    float64
 	CanBuy     bool
 	CanSell    bool
+	Source     string // 结果来源
+
+	DataNotFound bool // 服务尚未收录该代币（通常是太新）
+
+	SameCreatorHoneypot bool // 创建者发过其他蜜罐（GoPlus honeypot_with_same_creator）
+
+	// GoPlus 额外风险项
+	OwnerChangeBalance   bool // owner 可修改持有者余额
+	HiddenOwner          bool // 存在隐藏的 owner
+	CanTakeBackOwnership bool // 放弃后可收回所有权
+	TradingCooldown      bool // 交易冷却限制
+
+	Votes     string // 各服务结论，如 "goplus=safe,simulation=honeypot"
+	Disagreed bool   // 有数据的服务结论不一致
+
+	RawPayload string // 服务原始返回
 }
 
 // NewHoneypotDetector 创建蜜罐检测器
-func NewHoneypotDetector(apiKey string) *HoneypotDetector {
+// 服务优先级：GoPlus（需 API Key）-> Honeypot.is -> 本地模拟（可选）
+func NewHoneypotDetector(apiKey string, simulator *HoneypotSimulator) *HoneypotDetector {
+	var providers []SecurityProvider
+	if apiKey != "" {
+		providers = append(providers, NewGoPlusProvider(apiKey))
+	}
+	providers = append(providers, NewHoneypotIsProvider())
+	if simulator != nil {
+		providers = append(providers, simulator)
+	}
+
 	return &HoneypotDetector{
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-		apiKey:     apiKey,
+		providers:  providers,
+		policy:     config.GetSecurityConsensusPolicy(),
+		reportRepo: database.NewTokenSecurityReportRepository(),
+		cache:      make(map[string]honeypotCacheEntry),
 	}
 }
 
 // CheckHoneypot 检测代币是否是蜜罐
+// 所有服务都没有数据时返回 DataNotFound 的结果，所有服务都失败时返回最后一个错误
 func (h *HoneypotDetector) CheckHoneypot(tokenAddress string) (*HoneypotResult, error) {
-	// 优先使用 GoPlus API（更详细）
-	if h.apiKey != "" {
-		result, err := h.checkWithGoPlus(tokenAddress)
-		if err == nil {
-			return result, nil
+	key := strings.ToLower(tokenAddress)
+	if cached, ok := h.getCached(key); ok {
+		return cached, nil
+	}
+
+	var results []*HoneypotResult
+	var notFound *HoneypotResult
+	var lastErr error
+	for _, provider := range h.providers {
+		result, err := provider.Check(tokenAddress)
+		h.saveReport(key, provider.Name(), result, err)
+		if err != nil {
+			logger.Log.Debug("安全检测服务请求失败",
+				zap.String("provider", provider.Name()),
+				zap.String("token", tokenAddress),
+				zap.Error(err))
+			lastErr = err
+			continue
+		}
+		if result.DataNotFound {
+			if notFound == nil {
+				notFound = result
+			}
+			continue
+		}
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		if notFound != nil {
+			return notFound, nil
+		}
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no security provider available")
+		}
+		return nil, lastErr
+	}
+
+	verdict := decideHoneypotConsensus(h.policy, results)
+	if verdict.Disagreed {
+		logger.Log.Info("安全检测服务结论不一致",
+			zap.String("token", tokenAddress),
+			zap.String("policy", h.policy),
+			zap.String("votes", verdict.Votes),
+			zap.Bool("isHoneypot", verdict.IsHoneypot))
+	}
+
+	h.setCached(key, verdict)
+	return verdict, nil
+}
+
+// getCached 读取未过期的缓存结论
+func (h *HoneypotDetector) getCached(key string) (*HoneypotResult, bool) {
+	h.cacheMu.Lock()
+	defer h.cacheMu.Unlock()
+
+	entry, ok := h.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.result, true
+}
+
+// setCached 缓存结论，并顺带清理过期条目
+func (h *HoneypotDetector) setCached(key string, result *HoneypotResult) {
+	h.cacheMu.Lock()
+	defer h.cacheMu.Unlock()
+
+	now := time.Now()
+	for k, entry := range h.cache {
+		if now.After(entry.expiresAt) {
+			delete(h.cache, k)
+		}
+	}
+	h.cache[key] = honeypotCacheEntry{result: result, expiresAt: now.Add(config.SecurityCheckCacheTTL)}
+}
+
+// saveReport 保存服务原始报告
+func (h *HoneypotDetector) saveReport(tokenAddress, provider string, result *HoneypotResult, checkErr error) {
+	report := &model.TokenSecurityReport{
+		TokenAddress: tokenAddress,
+		Provider:     provider,
+	}
+	if checkErr != nil {
+		report.Error = checkErr.Error()
+	} else {
+		report.HasData = !result.DataNotFound
+		report.IsHoneypot = result.IsHoneypot
+		report.BuyTax = result.BuyTax
+		report.SellTax = result.SellTax
+		report.RawPayload = result.RawPayload
+		if report.RawPayload == "" {
+			// 本地模拟没有外部返回，保存结果本身
+			payload, _ := json.Marshal(result)
+			report.RawPayload = string(payload)
+		}
+	}
+
+	if err := h.reportRepo.Create(report); err != nil {
+		logger.Log.Warn("保存安全检测报告失败", zap.String("token", tokenAddress), zap.Error(err))
+	}
+}
+
+// decideHoneypotConsensus 按裁决策略合并有数据的服务结论（results 按优先级排列且非空）
+// 税率取各服务最大值，风险项取并集
+func decideHoneypotConsensus(policy string, results []*HoneypotResult) *HoneypotResult {
+	if len(results) == 1 {
+		return results[0]
+	}
+
+	verdict := &HoneypotResult{
+		Source:  HoneypotSourceConsensus,
+		CanBuy:  true,
+		CanSell: true,
+	}
+
+	var votes []string
+	var honeypotWeight, totalWeight float64
+	var honeypotReasons []string
+	for _, r := range results {
+		weight := config.SecurityProviderWeights[r.Source]
+		if weight == 0 {
+			weight = 1
+		}
+		totalWeight += weight
+
+		if r.IsHoneypot {
+			honeypotWeight += weight
+			votes = append(votes, r.Source+"=honeypot")
+			if r.Reason != "" {
+				honeypotReasons = append(honeypotReasons, r.Reason)
+			}
+		} else {
+			votes = append(votes, r.Source+"=safe")
 		}
-		// GoPlus 失败，降级到 Honeypot.is
+		if r.IsHoneypot != results[0].IsHoneypot {
+			verdict.Disagreed = true
+		}
+
+		verdict.BuyTax = max(verdict.BuyTax, r.BuyTax)
+		verdict.SellTax = max(verdict.SellTax, r.SellTax)
+		verdict.CanBuy = verdict.CanBuy && r.CanBuy
+		verdict.CanSell = verdict.CanSell && r.CanSell
+		verdict.SameCreatorHoneypot = verdict.SameCreatorHoneypot || r.SameCreatorHoneypot
+		verdict.OwnerChangeBalance = verdict.OwnerChangeBalance || r.OwnerChangeBalance
+		verdict.HiddenOwner = verdict.HiddenOwner || r.HiddenOwner
+		verdict.CanTakeBackOwnership = verdict.CanTakeBackOwnership || r.CanTakeBackOwnership
+		verdict.TradingCooldown = verdict.TradingCooldown || r.TradingCooldown
+	}
+	verdict.Votes = strings.Join(votes, ",")
+
+	switch policy {
+	case config.SecurityConsensusAny:
+		verdict.IsHoneypot = honeypotWeight > 0
+	case config.SecurityConsensusPriority:
+		verdict.IsHoneypot = results[0].IsHoneypot
+	default:
+		verdict.IsHoneypot = honeypotWeight > 0 && honeypotWeight*2 >= totalWeight
+	}
+
+	if verdict.IsHoneypot {
+		verdict.Reason = strings.Join(honeypotReasons, "; ")
 	}
+	return verdict
+}
+
+// honeypotIsNotFoundReason Honeypot.is 尚无该代币数据时的原因
+const honeypotIsNotFoundReason = "Honeypot API data not found (too new)"
+
+// HoneypotIsProvider Honeypot.is 检测服务
+type HoneypotIsProvider struct {
+	httpClient *http.Client
+}
+
+// NewHoneypotIsProvider 创建 Honeypot.is 检测服务
+func NewHoneypotIsProvider() *HoneypotIsProvider {
+	return &HoneypotIsProvider{httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
 
-	// 使用 Honeypot.is API
-	return h.checkWithHoneypotIs(tokenAddress)
+// Name 服务名
+func (p *HoneypotIsProvider) Name() string {
+	return HoneypotSourceHoneypotIs
 }
 
-// checkWithHoneypotIs 使用 Honeypot.is API
-func (h *HoneypotDetector) checkWithHoneypotIs(tokenAddress string) (*HoneypotResult, error) {
+// Check 使用 Honeypot.is API
+func (p *HoneypotIsProvider) Check(tokenAddress string) (*HoneypotResult, error) {
 	url := fmt.Sprintf("%s?address=%s", config.HoneypotAPIURL, tokenAddress)
 
-	resp, err := h.httpClient.Get(url)
+	utils.GetRateLimiter(config.RateLimiterHoneypotIs).Wait()
+	resp, err := p.httpClient.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call honeypot API: %w", err)
 	}
@@ -61,15 +297,14 @@ func (h *HoneypotDetector) checkWithHoneypotIs(tokenAddress string) (*HoneypotRe
 	if resp.StatusCode != http.StatusOK {
 		// 如果返回 404，说明 API 还没有该代币数据（通常是因为太新）
 		// 这种情况下，我们不能判定为蜜罐，也不能完全判定安全
-		// 暂时返回非蜜罐，但标记原因
 		if resp.StatusCode == http.StatusNotFound {
 			return &HoneypotResult{
-				IsHoneypot: false,
-				Reason:     "Honeypot API data not found (too new)",
-				CanBuy:     true, // 假设可买
-				CanSell:    true, // 假设可卖
-				BuyTax:     0,    // 假设0税
-				SellTax:    0,
+				IsHoneypot:   false,
+				Reason:       honeypotIsNotFoundReason,
+				CanBuy:       true, // 假设可买
+				CanSell:      true, // 假设可卖
+				Source:       HoneypotSourceHoneypotIs,
+				DataNotFound: true,
 			}, nil
 		}
 		return nil, fmt.Errorf("honeypot API returned status %d", resp.StatusCode)
@@ -101,6 +336,8 @@ func (h *HoneypotDetector) checkWithHoneypotIs(tokenAddress string) (*HoneypotRe
 		SellTax:    apiResp.SimulationResult.SellTax,
 		CanBuy:     true,
 		CanSell:    !apiResp.IsHoneypot,
+		Source:     HoneypotSourceHoneypotIs,
+		RawPayload: string(body),
 	}
 
 	if apiResp.IsHoneypot {
@@ -110,8 +347,27 @@ func (h *HoneypotDetector) checkWithHoneypotIs(tokenAddress string) (*HoneypotRe
 	return result, nil
 }
 
-// checkWithGoPlus 使用 GoPlus Security API
-func (h *HoneypotDetector) checkWithGoPlus(tokenAddress string) (*HoneypotResult, error) {
+// GoPlusProvider GoPlus Security 检测服务
+type GoPlusProvider struct {
+	httpClient *http.Client
+	apiKey     string
+}
+
+// NewGoPlusProvider 创建 GoPlus 检测服务
+func NewGoPlusProvider(apiKey string) *GoPlusProvider {
+	return &GoPlusProvider{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		apiKey:     apiKey,
+	}
+}
+
+// Name 服务名
+func (p *GoPlusProvider) Name() string {
+	return HoneypotSourceGoPlus
+}
+
+// Check 使用 GoPlus Security API
+func (p *GoPlusProvider) Check(tokenAddress string) (*HoneypotResult, error) {
 	url := fmt.Sprintf("%s?contract_addresses=%s", config.GoPlusAPIURL, tokenAddress)
 
 	req, err := http.NewRequest("GET", url, nil)
@@ -119,11 +375,12 @@ func (h *HoneypotDetector) checkWithGoPlus(tokenAddress string) (*HoneypotResult
 		return nil, err
 	}
 
-	if h.apiKey != "" {
-		req.Header.Set("Authorization", h.apiKey)
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", p.apiKey)
 	}
 
-	resp, err := h.httpClient.Do(req)
+	utils.GetRateLimiter(config.RateLimiterGoPlus).Wait()
+	resp, err := p.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call GoPlus API: %w", err)
 	}
@@ -147,6 +404,10 @@ func (h *HoneypotDetector) checkWithGoPlus(tokenAddress string) (*HoneypotResult
 			CannotBuy               string `json:"cannot_buy"`
 			CannotSellAll           string `json:"cannot_sell_all"`
 			HoneypotWithSameCreator string `json:"honeypot_with_same_creator"`
+			OwnerChangeBalance      string `json:"owner_change_balance"`
+			HiddenOwner             string `json:"hidden_owner"`
+			CanTakeBackOwnership    string `json:"can_take_back_ownership"`
+			TradingCooldown         string `json:"trading_cooldown"`
 		} `json:"result"`
 	}
 
@@ -158,15 +419,32 @@ func (h *HoneypotDetector) checkWithGoPlus(tokenAddress string) (*HoneypotResult
 		return nil, fmt.Errorf("GoPlus API returned error code %d", apiResp.Code)
 	}
 
-	tokenData, ok := apiResp.Result[tokenAddress]
+	// GoPlus 返回的 key 为小写地址
+	tokenData, ok := apiResp.Result[strings.ToLower(tokenAddress)]
 	if !ok {
-		return nil, fmt.Errorf("token not found in response")
+		return &HoneypotResult{
+			Reason:       "GoPlus data not found (too new)",
+			CanBuy:       true,
+			CanSell:      true,
+			Source:       HoneypotSourceGoPlus,
+			DataNotFound: true,
+			RawPayload:   string(body),
+		}, nil
 	}
 
 	result := &HoneypotResult{
 		IsHoneypot: tokenData.IsHoneypot == "1" || tokenData.CannotSellAll == "1",
 		CanBuy:     tokenData.CannotBuy != "1",
 		CanSell:    tokenData.CannotSellAll != "1",
+		Source:     HoneypotSourceGoPlus,
+
+		SameCreatorHoneypot:  tokenData.HoneypotWithSameCreator == "1",
+		OwnerChangeBalance:   tokenData.OwnerChangeBalance == "1",
+		HiddenOwner:          tokenData.HiddenOwner == "1",
+		CanTakeBackOwnership: tokenData.CanTakeBackOwnership == "1",
+		TradingCooldown:      tokenData.TradingCooldown == "1",
+
+		RawPayload: string(body),
 	}
 
 	// 解析税率
@@ -0,0 +1,350 @@
+package analyzer
+
+import (
+	"context"
+	"ethereum-monitor/config"
+	"ethereum-monitor/utils"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+)
+
+const honeypotSimABIJSON = `[
+	{"name":"getAmountsOut","type":"function","stateMutability":"view",
+	 "inputs":[{"name":"amountIn","type":"uint256"},{"name":"path","type":"address[]"}],
+	 "outputs":[{"name":"amounts","type":"uint256[]"}]},
+	{"name":"swapExactETHForTokensSupportingFeeOnTransferTokens","type":"function","stateMutability":"payable",
+	 "inputs":[{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"address[]"},{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],
+	 "outputs":[]},
+	{"name":"swapExactTokensForETHSupportingFeeOnTransferTokens","type":"function","stateMutability":"nonpayable",
+	 "inputs":[{"name":"amountIn","type":"uint256"},{"name":"amountOutMin","type":"uint256"},{"name":"path","type":"address[]"},{"name":"to","type":"address"},{"name":"deadline","type":"uint256"}],
+	 "outputs":[]},
+	{"name":"balanceOf","type":"function","stateMutability":"view",
+	 "inputs":[{"name":"account","type":"address"}],
+	 "outputs":[{"name":"","type":"uint256"}]},
+	{"name":"approve","type":"function","stateMutability":"nonpayable",
+	 "inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],
+	 "outputs":[{"name":"","type":"bool"}]},
+	{"name":"getEthBalance","type":"function","stateMutability":"view",
+	 "inputs":[{"name":"addr","type":"address"}],
+	 "outputs":[{"name":"balance","type":"uint256"}]},
+	{"name":"aggregate3Value","type":"function","stateMutability":"payable",
+	 "inputs":[{"name":"calls","type":"tuple[]","components":[
+		{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},
+		{"name":"value","type":"uint256"},{"name":"callData","type":"bytes"}]}],
+	 "outputs":[{"name":"returnData","type":"tuple[]","components":[
+		{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}]}]}
+]`
+
+// multicallCall Multicall3.Call3Value
+type multicallCall struct {
+	Target       common.Address
+	AllowFailure bool
+	Value        *big.Int
+	CallData     []byte
+}
+
+// multicallResult Multicall3.Result
+type multicallResult struct {
+	Success    bool   `json:"success"`
+	ReturnData []byte `json:"returnData"`
+}
+
+// 模拟调用序列中各步骤的下标
+const (
+	simStepBuyQuote = iota
+	simStepBuy
+	simStepBalance
+	simStepApprove
+	simStepSellQuote
+	simStepEthBefore
+	simStepSell
+	simStepEthAfter
+)
+
+// HoneypotSimulator 本地蜜罐模拟器
+// 把 Multicall3 字节码注入到模拟地址，并给发起地址注入 ETH，
+// 在一次 eth_call 中依次执行 买入 -> 查询余额 -> 授权 -> 卖出，测量真实买卖税与卖出是否回滚
+type HoneypotSimulator struct {
+	client     *ethclient.Client
+	gethClient *gethclient.Client
+	abi        abi.ABI
+
+	codeOnce      sync.Once
+	multicallCode []byte
+	codeErr       error
+}
+
+// NewHoneypotSimulator 创建本地蜜罐模拟器
+func NewHoneypotSimulator(rpcURL string) (*HoneypotSimulator, error) {
+	rpcClient, err := utils.DialRateLimitedRPC(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial rpc: %w", err)
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(honeypotSimABIJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse simulator abi: %w", err)
+	}
+
+	return &HoneypotSimulator{
+		client:     ethclient.NewClient(rpcClient),
+		gethClient: gethclient.New(rpcClient),
+		abi:        parsed,
+	}, nil
+}
+
+// Simulate 模拟买入后立即卖出
+// 买入失败（例如尚未开放交易）时返回错误，由调用方按"数据不足"处理
+func (s *HoneypotSimulator) Simulate(tokenAddress string) (*HoneypotResult, error) {
+	ctx := context.Background()
+	token := common.HexToAddress(tokenAddress)
+	buyAmount := ethToWei(config.HoneypotSimBuyETH)
+
+	// 第一次调用：只买入，拿到实际到账数量，用于构造卖出调用
+	buyCalls, err := s.buyCalls(token, buyAmount)
+	if err != nil {
+		return nil, err
+	}
+	buyResults, err := s.execute(ctx, buyCalls, buyAmount)
+	if err != nil {
+		return nil, err
+	}
+	if !buyResults[simStepBuyQuote].Success || !buyResults[simStepBuy].Success {
+		return nil, fmt.Errorf("simulated buy reverted")
+	}
+
+	expectedTokens, err := s.unpackLastAmount(buyResults[simStepBuyQuote].ReturnData)
+	if err != nil {
+		return nil, err
+	}
+	receivedTokens, err := s.unpackUint(buyResults[simStepBalance].ReturnData)
+	if err != nil {
+		return nil, err
+	}
+	if receivedTokens.Sign() == 0 {
+		return nil, fmt.Errorf("simulated buy received no tokens")
+	}
+
+	result := &HoneypotResult{
+		CanBuy:  true,
+		BuyTax:  lossPercent(expectedTokens, receivedTokens),
+		Source:  HoneypotSourceSimulation,
+		CanSell: true,
+	}
+
+	// 第二次调用：同一区块状态下重放买入，再卖出全部到账代币
+	sellCalls, err := s.sellCalls(token, receivedTokens)
+	if err != nil {
+		return nil, err
+	}
+	sellResults, err := s.execute(ctx, append(buyCalls, sellCalls...), buyAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sellResults[simStepApprove].Success || !sellResults[simStepSell].Success {
+		result.CanSell = false
+		result.IsHoneypot = true
+		result.SellTax = 100
+		result.Reason = "Local simulation: sell reverted"
+		return result, nil
+	}
+
+	expectedEth, err := s.unpackLastAmount(sellResults[simStepSellQuote].ReturnData)
+	if err != nil {
+		return nil, err
+	}
+	ethBefore, err := s.unpackUint(sellResults[simStepEthBefore].ReturnData)
+	if err != nil {
+		return nil, err
+	}
+	ethAfter, err := s.unpackUint(sellResults[simStepEthAfter].ReturnData)
+	if err != nil {
+		return nil, err
+	}
+
+	result.SellTax = lossPercent(expectedEth, new(big.Int).Sub(ethAfter, ethBefore))
+	if result.SellTax >= config.HoneypotSellTaxThreshold {
+		result.IsHoneypot = true
+		result.Reason = fmt.Sprintf("Local simulation: sell tax %.1f%%", result.SellTax)
+	}
+
+	return result, nil
+}
+
+// buyCalls 构造买入步骤：报价 -> 买入 -> 查询到账余额
+func (s *HoneypotSimulator) buyCalls(token common.Address, buyAmount *big.Int) ([]multicallCall, error) {
+	router := common.HexToAddress(config.UniswapV2RouterAddress)
+	weth := common.HexToAddress(config.WETHAddress)
+	sim := common.HexToAddress(config.HoneypotSimContractAddress)
+	path := []common.Address{weth, token}
+
+	return s.packCalls([]simCallSpec{
+		simStepBuyQuote: {router, nil, "getAmountsOut", []interface{}{buyAmount, path}},
+		simStepBuy: {router, buyAmount, "swapExactETHForTokensSupportingFeeOnTransferTokens",
+			[]interface{}{big.NewInt(0), path, sim, simDeadline()}},
+		simStepBalance: {token, nil, "balanceOf", []interface{}{sim}},
+	})
+}
+
+// sellCalls 构造卖出步骤：授权 -> 报价 -> 卖出前余额 -> 卖出 -> 卖出后余额
+func (s *HoneypotSimulator) sellCalls(token common.Address, amount *big.Int) ([]multicallCall, error) {
+	router := common.HexToAddress(config.UniswapV2RouterAddress)
+	weth := common.HexToAddress(config.WETHAddress)
+	sim := common.HexToAddress(config.HoneypotSimContractAddress)
+	receiver := common.HexToAddress(config.HoneypotSimReceiverAddress)
+	path := []common.Address{token, weth}
+
+	return s.packCalls([]simCallSpec{
+		{token, nil, "approve", []interface{}{router, amount}},
+		{router, nil, "getAmountsOut", []interface{}{amount, path}},
+		{sim, nil, "getEthBalance", []interface{}{receiver}},
+		{router, nil, "swapExactTokensForETHSupportingFeeOnTransferTokens",
+			[]interface{}{amount, big.NewInt(0), path, receiver, simDeadline()}},
+		{sim, nil, "getEthBalance", []interface{}{receiver}},
+	})
+}
+
+// simCallSpec 待打包的子调用描述
+type simCallSpec struct {
+	target common.Address
+	value  *big.Int
+	method string
+	args   []interface{}
+}
+
+// packCalls 将子调用描述打包为允许失败的 Multicall3 子调用
+func (s *HoneypotSimulator) packCalls(specs []simCallSpec) ([]multicallCall, error) {
+	calls := make([]multicallCall, 0, len(specs))
+	for _, spec := range specs {
+		data, err := s.abi.Pack(spec.method, spec.args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack %s: %w", spec.method, err)
+		}
+		value := spec.value
+		if value == nil {
+			value = big.NewInt(0)
+		}
+		calls = append(calls, multicallCall{Target: spec.target, AllowFailure: true, Value: value, CallData: data})
+	}
+	return calls, nil
+}
+
+// execute 以 state override 执行一次 aggregate3Value 调用
+func (s *HoneypotSimulator) execute(ctx context.Context, calls []multicallCall, value *big.Int) ([]multicallResult, error) {
+	code, err := s.loadMulticallCode(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.abi.Pack("aggregate3Value", calls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack multicall: %w", err)
+	}
+
+	caller := common.HexToAddress(config.HoneypotSimCallerAddress)
+	sim := common.HexToAddress(config.HoneypotSimContractAddress)
+	overrides := map[common.Address]gethclient.OverrideAccount{
+		caller: {Balance: ethToWei(100)},
+		sim:    {Code: code},
+	}
+
+	output, err := s.gethClient.CallContract(ctx, ethereum.CallMsg{
+		From:  caller,
+		To:    &sim,
+		Value: value,
+		Data:  data,
+	}, nil, &overrides)
+	if err != nil {
+		return nil, fmt.Errorf("simulation call failed: %w", err)
+	}
+
+	unpacked, err := s.abi.Unpack("aggregate3Value", output)
+	if err != nil || len(unpacked) == 0 {
+		return nil, fmt.Errorf("failed to unpack simulation result: %v", err)
+	}
+	results := *abi.ConvertType(unpacked[0], new([]multicallResult)).(*[]multicallResult)
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("unexpected simulation result length %d", len(results))
+	}
+	return results, nil
+}
+
+// loadMulticallCode 读取并缓存 Multicall3 运行时字节码
+func (s *HoneypotSimulator) loadMulticallCode(ctx context.Context) ([]byte, error) {
+	s.codeOnce.Do(func() {
+		s.multicallCode, s.codeErr = s.client.CodeAt(ctx, common.HexToAddress(config.Multicall3Address), nil)
+		if s.codeErr == nil && len(s.multicallCode) == 0 {
+			s.codeErr = fmt.Errorf("multicall3 not deployed")
+		}
+	})
+	return s.multicallCode, s.codeErr
+}
+
+// unpackLastAmount 解析 getAmountsOut 返回值的最后一项
+func (s *HoneypotSimulator) unpackLastAmount(data []byte) (*big.Int, error) {
+	unpacked, err := s.abi.Unpack("getAmountsOut", data)
+	if err != nil || len(unpacked) == 0 {
+		return nil, fmt.Errorf("failed to unpack amounts: %v", err)
+	}
+	amounts, ok := unpacked[0].([]*big.Int)
+	if !ok || len(amounts) == 0 {
+		return nil, fmt.Errorf("invalid amounts")
+	}
+	return amounts[len(amounts)-1], nil
+}
+
+// unpackUint 解析单个 uint256 返回值
+func (s *HoneypotSimulator) unpackUint(data []byte) (*big.Int, error) {
+	if len(data) < 32 {
+		return nil, fmt.Errorf("invalid uint256 result")
+	}
+	return new(big.Int).SetBytes(data[:32]), nil
+}
+
+// Name 服务名（实现 SecurityProvider）
+func (s *HoneypotSimulator) Name() string {
+	return HoneypotSourceSimulation
+}
+
+// Check 实现 SecurityProvider，等同于 Simulate
+func (s *HoneypotSimulator) Check(tokenAddress string) (*HoneypotResult, error) {
+	return s.Simulate(tokenAddress)
+}
+
+// Close 关闭
+func (s *HoneypotSimulator) Close() {
+	s.client.Close()
+}
+
+// lossPercent 计算实际到账相对预期的损耗百分比
+func lossPercent(expected, actual *big.Int) float64 {
+	if expected.Sign() == 0 {
+		return 0
+	}
+	loss := new(big.Int).Sub(expected, actual)
+	if loss.Sign() <= 0 {
+		return 0
+	}
+	return percentOf(loss, expected)
+}
+
+// ethToWei 将 ETH 数量转换为 Wei
+func ethToWei(eth float64) *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(eth), big.NewFloat(1e18)).Int(nil)
+	return wei
+}
+
+// simDeadline 模拟交易的截止时间
+func simDeadline() *big.Int {
+	return big.NewInt(time.Now().Add(time.Hour).Unix())
+}
@@ -3,6 +3,7 @@ package analyzer
 import (
 	"context"
 	"ethereum-monitor/config"
+	"ethereum-monitor/utils"
 	"fmt"
 	"math/big"
 	"strings"
@@ -12,6 +13,9 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
+// mockETHPriceUSD 假定 ETH 价格 $2500 (后续可优化)
+const mockETHPriceUSD = 2500.0
+
 // LiquidityAnalyzer 流动性分析器
 type LiquidityAnalyzer struct {
 	client *ethclient.Client
@@ -19,7 +23,7 @@ type LiquidityAnalyzer struct {
 
 // NewLiquidityAnalyzer 创建流动性分析器
 func NewLiquidityAnalyzer(rpcURL string) (*LiquidityAnalyzer, error) {
-	client, err := ethclient.Dial(rpcURL)
+	client, err := utils.DialRateLimitedEthClient(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial rpc: %w", err)
 	}
@@ -110,12 +114,49 @@ func (la *LiquidityAnalyzer) GetLiquidityInfo(pairAddress, tokenAddress string)
 
 	// 注意：有些工具显示 Liquidity 仅指 ETH 这一侧的价值，有些指双侧。
 	// 这里我们按 **双侧总价值** 计算。
-	ethPrice := 2500.0 // Mock Price
-	liquidityUSD = ethCount * ethPrice * 2
+	liquidityUSD = ethCount * mockETHPriceUSD * 2
 
 	return liquidityUSD, ethCount, nil
 }
 
+// PoolState 池子状态
+type PoolState struct {
+	LiquidityUSD float64
+	LiquidityETH float64
+	PriceETH     float64 // 每枚代币的 ETH 价格
+}
+
+// GetPoolState 读取池子的 ETH 数量与代币价格
+func (la *LiquidityAnalyzer) GetPoolState(pairAddress string, decimals uint8) (*PoolState, error) {
+	reserves, err := la.GetReserves(pairAddress)
+	if err != nil {
+		return nil, err
+	}
+	token0Addr, err := la.getToken0(pairAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	wethReserve, tokenReserve := reserves.Reserve1, reserves.Reserve0
+	if strings.EqualFold(token0Addr, config.WETHAddress) {
+		wethReserve, tokenReserve = reserves.Reserve0, reserves.Reserve1
+	}
+
+	ethCount, _ := new(big.Float).Quo(new(big.Float).SetInt(wethReserve), big.NewFloat(1e18)).Float64()
+	state := &PoolState{
+		LiquidityETH: ethCount,
+		LiquidityUSD: ethCount * mockETHPriceUSD * 2,
+	}
+
+	if tokenReserve.Sign() > 0 {
+		unit := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+		tokenCount, _ := new(big.Float).Quo(new(big.Float).SetInt(tokenReserve), unit).Float64()
+		state.PriceETH = ethCount / tokenCount
+	}
+
+	return state, nil
+}
+
 // getToken0 读取 token0 地址
 func (la *LiquidityAnalyzer) getToken0(pairAddress string) (string, error) {
 	// token0() signature: 0dfe1681
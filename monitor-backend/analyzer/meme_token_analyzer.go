@@ -2,6 +2,8 @@ package analyzer
 
 import (
 	"encoding/json"
+	"errors"
+	"ethereum-monitor/config"
 	"ethereum-monitor/database"
 	"ethereum-monitor/logger"
 	"ethereum-monitor/model"
@@ -15,6 +17,13 @@ import (
 type MemeTokenAnalyzer struct {
 	tokenReader      *TokenInfoReader
 	honeypotDetector *HoneypotDetector
+	simulator        *HoneypotSimulator
+	verifier         *ContractVerifier
+	deployerProfiler *DeployerProfiler
+	holderAnalyzer   *HolderAnalyzer
+	ownerAnalyzer    *OwnershipAnalyzer
+	socialEnricher   *SocialEnricher
+	sniperAnalyzer   *SniperAnalyzer
 	riskScorer       *TokenRiskScorer
 	tokenRepo        *database.TokenAnalysisRepository
 }
@@ -26,9 +35,41 @@ func NewMemeTokenAnalyzer(rpcURL, goPlusAPIKey string) (*MemeTokenAnalyzer, erro
 		return nil, fmt.Errorf("failed to create token reader: %w", err)
 	}
 
+	holderAnalyzer, err := NewHolderAnalyzer(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create holder analyzer: %w", err)
+	}
+
+	ownerAnalyzer, err := NewOwnershipAnalyzer(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ownership analyzer: %w", err)
+	}
+
+	simulator, err := NewHoneypotSimulator(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create honeypot simulator: %w", err)
+	}
+
+	socialEnricher, err := NewSocialEnricher(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create social enricher: %w", err)
+	}
+
+	sniperAnalyzer, err := NewSniperAnalyzer(rpcURL, config.GetExplorerAPIURL(), config.GetExplorerAPIKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sniper analyzer: %w", err)
+	}
+
 	return &MemeTokenAnalyzer{
 		tokenReader:      tokenReader,
-		honeypotDetector: NewHoneypotDetector(goPlusAPIKey),
+		honeypotDetector: NewHoneypotDetector(goPlusAPIKey, simulator),
+		simulator:        simulator,
+		verifier:         NewContractVerifier(config.GetExplorerAPIURL(), config.GetExplorerAPIKey()),
+		deployerProfiler: NewDeployerProfiler(config.GetExplorerAPIURL(), config.GetExplorerAPIKey()),
+		holderAnalyzer:   holderAnalyzer,
+		ownerAnalyzer:    ownerAnalyzer,
+		socialEnricher:   socialEnricher,
+		sniperAnalyzer:   sniperAnalyzer,
 		riskScorer:       NewTokenRiskScorer(),
 		tokenRepo:        database.NewTokenAnalysisRepository(),
 	}, nil
@@ -38,8 +79,11 @@ func NewMemeTokenAnalyzer(rpcURL, goPlusAPIKey string) (*MemeTokenAnalyzer, erro
 func (a *MemeTokenAnalyzer) AnalyzeToken(tokenAddress string) (*model.TokenAnalysis, error) {
 	logger.Log.Info("开始分析代币", zap.String("address", tokenAddress))
 
+	// 部署即分析：此时尚未建池，记录进入 PENDING_PAIR，建池后由 PairCreated 监听推进
 	analysis := &model.TokenAnalysis{
 		TokenAddress: tokenAddress,
+		Status:       model.TokenStatusPendingPair,
+		SafetyStatus: model.SafetyStatusPending,
 		AnalyzedAt:   time.Now(),
 	}
 
@@ -62,8 +106,10 @@ func (a *MemeTokenAnalyzer) AnalyzeToken(tokenAddress string) (*model.TokenAnaly
 		zap.String("symbol", analysis.Symbol),
 		zap.Uint8("decimals", analysis.Decimals))
 
-	// 2. 检查合约验证状态（需要 Etherscan API，这里简化处理）
-	analysis.IsVerified = false // TODO: 集成 Etherscan API
+	// 2. 检查合约源码验证状态
+	if err := a.verifier.Analyze(analysis); err != nil {
+		logger.Log.Warn("源码验证检查失败", zap.Error(err))
+	}
 
 	// 3. 蜜罐检测
 	honeypotResult, err := a.honeypotDetector.CheckHoneypot(tokenAddress)
@@ -71,10 +117,7 @@ func (a *MemeTokenAnalyzer) AnalyzeToken(tokenAddress string) (*model.TokenAnaly
 		logger.Log.Warn("蜜罐检测失败", zap.Error(err))
 		// 继续分析，不中断
 	} else {
-		analysis.IsHoneypot = honeypotResult.IsHoneypot
-		analysis.HoneypotReason = honeypotResult.Reason
-		analysis.BuyTax = honeypotResult.BuyTax
-		analysis.SellTax = honeypotResult.SellTax
+		applyHoneypotResult(analysis, honeypotResult)
 
 		logger.Log.Info("蜜罐检测结果",
 			zap.Bool("isHoneypot", analysis.IsHoneypot),
@@ -88,16 +131,27 @@ func (a *MemeTokenAnalyzer) AnalyzeToken(tokenAddress string) (*model.TokenAnaly
 	analysis.LiquidityUSD = 0
 	analysis.InitialMarketCap = 0
 
-	// 5. 持有者分析（需要 Etherscan API 或链上查询）
-	// TODO: 实现持有者分析
-	analysis.HolderCount = 0
-	analysis.Top10HoldingPct = 0
+	// 5. 持有者分析（回放 Transfer 日志）
+	if err := a.holderAnalyzer.Analyze(analysis); err != nil {
+		logger.Log.Warn("持有者分析失败", zap.String("address", tokenAddress), zap.Error(err))
+	}
+
+	// 6. 所有权、代理与危险函数检查
+	if err := a.ownerAnalyzer.Analyze(analysis); err != nil {
+		logger.Log.Warn("所有权分析失败", zap.String("address", tokenAddress), zap.Error(err))
+	}
+
+	// 7. 部署者画像（依赖持有者分析确定的部署者地址）
+	if err := a.deployerProfiler.Analyze(analysis); err != nil {
+		logger.Log.Warn("部署者画像分析失败", zap.String("address", tokenAddress), zap.Error(err))
+	}
 
-	// 6. 所有权检查
-	// TODO: 实现所有权检查
-	analysis.IsOwnershipRenounced = false
+	// 8. 社交信息与 ENS（依赖部署者与所有者地址）
+	if err := a.socialEnricher.Enrich(analysis); err != nil {
+		logger.Log.Warn("社交信息补充失败", zap.String("address", tokenAddress), zap.Error(err))
+	}
 
-	// 7. 计算风险评分
+	// 9. 计算风险评分
 	score, level, flags := a.riskScorer.CalculateRiskScore(analysis)
 	analysis.RiskScore = score
 	analysis.RiskLevel = level
@@ -111,9 +165,11 @@ func (a *MemeTokenAnalyzer) AnalyzeToken(tokenAddress string) (*model.TokenAnaly
 		zap.String("level", level),
 		zap.Int("flagCount", len(flags)))
 
-	// 8. 保存到数据库
+	// 10. 保存到数据库
 	if err := a.tokenRepo.Create(analysis); err != nil {
-		logger.Log.Error("保存代币分析失败", zap.Error(err))
+		if !errors.Is(err, database.ErrTokenExists) {
+			logger.Log.Error("保存代币分析失败", zap.Error(err))
+		}
 		return analysis, err
 	}
 
@@ -147,15 +203,41 @@ func (a *MemeTokenAnalyzer) AnalyzeSafetyOnly(analysis *model.TokenAnalysis) err
 		return err
 	}
 
-	analysis.IsHoneypot = honeypotResult.IsHoneypot
-	analysis.HoneypotReason = honeypotResult.Reason
-	analysis.BuyTax = honeypotResult.BuyTax
-	analysis.SellTax = honeypotResult.SellTax
+	applyHoneypotResult(analysis, honeypotResult)
+
+	// 2. 源码验证（项目方可能在上线后才验证，未验证时每次重新检查）
+	if !analysis.IsVerified {
+		if err := a.verifier.Analyze(analysis); err != nil {
+			logger.Log.Warn("源码验证检查失败", zap.String("token", analysis.TokenAddress), zap.Error(err))
+		}
+	}
+
+	// 3. 持有者分析（增量回放上次扫描之后的 Transfer 日志）
+	if err := a.holderAnalyzer.Analyze(analysis); err != nil {
+		logger.Log.Warn("持有者分析失败", zap.String("token", analysis.TokenAddress), zap.Error(err))
+	}
+
+	// 4. 所有权检查（所有权可能在上线后才放弃，每次都重新读取）
+	if err := a.ownerAnalyzer.Analyze(analysis); err != nil {
+		logger.Log.Warn("所有权分析失败", zap.String("token", analysis.TokenAddress), zap.Error(err))
+	}
+
+	// 5. 部署者画像（其他代币的结局会随时间变化，每次都重新汇总）
+	if err := a.deployerProfiler.Analyze(analysis); err != nil {
+		logger.Log.Warn("部署者画像分析失败", zap.String("token", analysis.TokenAddress), zap.Error(err))
+	}
+
+	// 6. 社交信息与 ENS（项目方可能在上线后才公布链接，缺失时每次重新读取）
+	if err := a.socialEnricher.Enrich(analysis); err != nil {
+		logger.Log.Warn("社交信息补充失败", zap.String("token", analysis.TokenAddress), zap.Error(err))
+	}
 
-	// 2. 也是时候做一些 Holder 分析了（如果能够的话）
-	// TODO: 集成 Etherscan / RPC Holder 分析
+	// 7. 早期买家 / 狙击分析（加池后窗口区块全部出块后只分析一次）
+	if err := a.sniperAnalyzer.Analyze(analysis); err != nil {
+		logger.Log.Warn("早期买家分析失败", zap.String("token", analysis.TokenAddress), zap.Error(err))
+	}
 
-	// 3. 计算风险评分（此时已有 Liquidity 和 Honeypot 信息）
+	// 8. 计算风险评分（此时已有 Liquidity、Honeypot、源码、持有者、所有权、部署者、社交和早期买家信息）
 	score, level, flags := a.riskScorer.CalculateRiskScore(analysis)
 	analysis.RiskScore = score
 	analysis.RiskLevel = level
@@ -167,9 +249,40 @@ func (a *MemeTokenAnalyzer) AnalyzeSafetyOnly(analysis *model.TokenAnalysis) err
 	return nil
 }
 
+// applyHoneypotResult 将蜜罐检测结论写入分析结果
+func applyHoneypotResult(analysis *model.TokenAnalysis, result *HoneypotResult) {
+	analysis.IsHoneypot = result.IsHoneypot
+	analysis.HoneypotReason = result.Reason
+	analysis.HoneypotSource = result.Source
+	analysis.HoneypotVotes = result.Votes
+	analysis.SecurityProvidersDisagree = result.Disagreed
+	analysis.CreatorHoneypotFlag = analysis.CreatorHoneypotFlag || result.SameCreatorHoneypot
+	analysis.BuyTax = result.BuyTax
+	analysis.SellTax = result.SellTax
+	analysis.OwnerChangeBalance = result.OwnerChangeBalance
+	analysis.HiddenOwner = result.HiddenOwner
+	analysis.CanTakeBackOwnership = result.CanTakeBackOwnership
+	analysis.TradingCooldown = result.TradingCooldown
+}
+
 // Close 关闭资源
 func (a *MemeTokenAnalyzer) Close() {
 	if a.tokenReader != nil {
 		a.tokenReader.Close()
 	}
+	if a.holderAnalyzer != nil {
+		a.holderAnalyzer.Close()
+	}
+	if a.ownerAnalyzer != nil {
+		a.ownerAnalyzer.Close()
+	}
+	if a.simulator != nil {
+		a.simulator.Close()
+	}
+	if a.socialEnricher != nil {
+		a.socialEnricher.Close()
+	}
+	if a.sniperAnalyzer != nil {
+		a.sniperAnalyzer.Close()
+	}
 }
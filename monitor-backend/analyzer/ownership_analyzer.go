@@ -0,0 +1,220 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// EIP-1967 标准存储槽
+var (
+	// bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1)
+	eip1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	// bytes32(uint256(keccak256("eip1967.proxy.admin")) - 1)
+	eip1967AdminSlot = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+	// bytes32(uint256(keccak256("eip1967.proxy.beacon")) - 1)
+	eip1967BeaconSlot = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+)
+
+// 危险函数类别
+const (
+	CapabilityMint      = "mint"
+	CapabilityBlacklist = "blacklist"
+	CapabilitySetFee    = "setFee"
+	CapabilityPause     = "pause"
+	CapabilitySetMaxTx  = "setMaxTx"
+)
+
+// dangerousFunctionSignatures 各类危险函数的常见签名
+var dangerousFunctionSignatures = map[string][]string{
+	CapabilityMint: {
+		"mint(address,uint256)",
+		"mint(uint256)",
+		"mintTo(address,uint256)",
+	},
+	CapabilityBlacklist: {
+		"blacklist(address)",
+		"addBlacklist(address)",
+		"addToBlacklist(address)",
+		"setBlacklist(address,bool)",
+		"blacklistAddress(address,bool)",
+		"setBots(address[])",
+		"addBots(address[])",
+	},
+	CapabilitySetFee: {
+		"setFee(uint256)",
+		"setFees(uint256,uint256)",
+		"setTaxFee(uint256)",
+		"setBuyFee(uint256)",
+		"setSellFee(uint256)",
+		"setTax(uint256,uint256)",
+		"updateFees(uint256,uint256)",
+	},
+	CapabilityPause: {
+		"pause()",
+		"setPaused(bool)",
+	},
+	CapabilitySetMaxTx: {
+		"setMaxTxAmount(uint256)",
+		"setMaxTx(uint256)",
+		"setMaxTxPercent(uint256)",
+		"setMaxWallet(uint256)",
+		"setMaxWalletSize(uint256)",
+	},
+}
+
+// dangerousCapabilityOrder 危险函数类别的输出顺序
+var dangerousCapabilityOrder = []string{
+	CapabilityMint,
+	CapabilityBlacklist,
+	CapabilitySetFee,
+	CapabilityPause,
+	CapabilitySetMaxTx,
+}
+
+// OwnershipAnalyzer 所有权与合约权限分析器
+// 读取 owner()/getOwner()、EIP-1967 代理存储槽，并扫描字节码中的危险函数选择器
+type OwnershipAnalyzer struct {
+	client *ethclient.Client
+}
+
+// NewOwnershipAnalyzer 创建所有权分析器
+func NewOwnershipAnalyzer(rpcURL string) (*OwnershipAnalyzer, error) {
+	client, err := utils.DialRateLimitedEthClient(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial rpc: %w", err)
+	}
+	return &OwnershipAnalyzer{client: client}, nil
+}
+
+// Analyze 分析所有权、代理与危险函数，填充 analysis 的对应字段
+func (o *OwnershipAnalyzer) Analyze(analysis *model.TokenAnalysis) error {
+	ctx := context.Background()
+	token := common.HexToAddress(analysis.TokenAddress)
+
+	// 1. 所有者
+	if owner, ok := o.readOwner(ctx, token); ok {
+		analysis.OwnerAddress = strings.ToLower(owner.Hex())
+		analysis.IsOwnershipRenounced = isRenouncedOwner(owner)
+	}
+
+	// 2. EIP-1967 代理
+	impl, err := o.readAddressSlot(ctx, token, eip1967ImplementationSlot)
+	if err != nil {
+		return fmt.Errorf("failed to read implementation slot: %w", err)
+	}
+	beacon, _ := o.readAddressSlot(ctx, token, eip1967BeaconSlot)
+	if impl == (common.Address{}) && beacon != (common.Address{}) {
+		// 信标代理：实现合约由信标的 implementation() 给出
+		if beaconImpl, ok := o.readBeaconImplementation(ctx, beacon); ok {
+			impl = beaconImpl
+		} else {
+			logger.Log.Warn("读取信标实现合约失败",
+				zap.String("token", analysis.TokenAddress),
+				zap.String("beacon", strings.ToLower(beacon.Hex())))
+		}
+	}
+	if impl != (common.Address{}) || beacon != (common.Address{}) {
+		analysis.IsProxy = true
+		if impl != (common.Address{}) {
+			analysis.ProxyImplementation = strings.ToLower(impl.Hex())
+		}
+		if admin, err := o.readAddressSlot(ctx, token, eip1967AdminSlot); err == nil && admin != (common.Address{}) {
+			analysis.ProxyAdmin = strings.ToLower(admin.Hex())
+		}
+	}
+
+	// 3. 字节码危险函数扫描（代理合约同时扫描实现合约）
+	code, err := o.client.CodeAt(ctx, token, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get code: %w", err)
+	}
+	selectors := extractSelectors(code)
+	if impl != (common.Address{}) {
+		if implCode, err := o.client.CodeAt(ctx, impl, nil); err == nil {
+			for sel := range extractSelectors(implCode) {
+				selectors[sel] = struct{}{}
+			}
+		}
+	}
+
+	capabilities := detectDangerousCapabilities(selectors)
+	capabilitiesJSON, _ := json.Marshal(capabilities)
+	analysis.DangerousFunctions = string(capabilitiesJSON)
+
+	logger.Log.Info("所有权分析完成",
+		zap.String("token", analysis.TokenAddress),
+		zap.String("owner", analysis.OwnerAddress),
+		zap.Bool("renounced", analysis.IsOwnershipRenounced),
+		zap.Bool("proxy", analysis.IsProxy),
+		zap.Strings("capabilities", capabilities))
+
+	return nil
+}
+
+// readOwner 依次尝试 owner() 与 getOwner()
+func (o *OwnershipAnalyzer) readOwner(ctx context.Context, token common.Address) (common.Address, bool) {
+	for _, sig := range []string{"owner()", "getOwner()"} {
+		result, err := o.client.CallContract(ctx, ethereum.CallMsg{
+			To:   &token,
+			Data: common.Hex2Bytes(selectorOf(sig)),
+		}, nil)
+		if err != nil || len(result) < 32 {
+			continue
+		}
+		return common.BytesToAddress(result[:32]), true
+	}
+	return common.Address{}, false
+}
+
+// readBeaconImplementation 调用信标合约的 implementation()
+func (o *OwnershipAnalyzer) readBeaconImplementation(ctx context.Context, beacon common.Address) (common.Address, bool) {
+	result, err := o.client.CallContract(ctx, ethereum.CallMsg{
+		To:   &beacon,
+		Data: common.Hex2Bytes(selectorOf("implementation()")),
+	}, nil)
+	if err != nil || len(result) < 32 {
+		return common.Address{}, false
+	}
+	impl := common.BytesToAddress(result[:32])
+	return impl, impl != (common.Address{})
+}
+
+// readAddressSlot 读取存储槽中的地址
+func (o *OwnershipAnalyzer) readAddressSlot(ctx context.Context, contract common.Address, slot common.Hash) (common.Address, error) {
+	value, err := o.client.StorageAt(ctx, contract, slot, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.BytesToAddress(value), nil
+}
+
+// Close 关闭
+func (o *OwnershipAnalyzer) Close() {
+	o.client.Close()
+}
+
+// detectDangerousCapabilities 根据选择器集合识别危险函数类别
+func detectDangerousCapabilities(selectors map[string]struct{}) []string {
+	capabilities := []string{}
+	for _, capability := range dangerousCapabilityOrder {
+		if hasAnySelector(selectors, dangerousFunctionSignatures[capability]) {
+			capabilities = append(capabilities, capability)
+		}
+	}
+	return capabilities
+}
+
+// isRenouncedOwner 所有者为零地址或销毁地址即视为已放弃所有权
+func isRenouncedOwner(owner common.Address) bool {
+	return owner == (common.Address{}) || isBurnAddress(owner)
+}
@@ -0,0 +1,328 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"ethereum-monitor/config"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// 条件运算符
+const (
+	RuleOpEq       = "eq"
+	RuleOpNe       = "ne"
+	RuleOpGt       = "gt"
+	RuleOpGte      = "gte"
+	RuleOpLt       = "lt"
+	RuleOpLte      = "lte"
+	RuleOpHas      = "has"       // JSON 数组字段包含指定元素
+	RuleOpEmpty    = "empty"     // 字段为零值
+	RuleOpNotEmpty = "not_empty" // 字段非零值
+)
+
+// RiskRuleSet 风险评分规则集
+type RiskRuleSet struct {
+	Version  string            `json:"version"`
+	MaxScore float64           `json:"max_score"`
+	Levels   []RiskLevelCutoff `json:"levels"`
+	Rules    []RiskRule        `json:"rules"`
+}
+
+// RiskLevelCutoff 风险等级分界，按顺序匹配 score < Below 的第一项；Below 为空表示兜底等级
+type RiskLevelCutoff struct {
+	Below *float64 `json:"below"`
+	Level string   `json:"level"`
+}
+
+// RiskRule 单条评分规则，When 中的条件全部满足时计分
+// WeightField 非空时，计分为 Weight * 该字段的值
+type RiskRule struct {
+	ID          string          `json:"id"`
+	When        []RiskCondition `json:"when"`
+	Weight      float64         `json:"weight"`
+	WeightField string          `json:"weight_field"`
+	Flag        string          `json:"flag"` // 支持 {field} 占位符
+}
+
+// RiskCondition 针对 TokenAnalysis 字段（json 标签名）的条件
+type RiskCondition struct {
+	Field string      `json:"field"`
+	Op    string      `json:"op"`
+	Value interface{} `json:"value"`
+}
+
+var (
+	activeRuleSet atomic.Pointer[RiskRuleSet]
+
+	// 规则文件热加载状态
+	ruleFileMu      sync.Mutex
+	ruleFileModTime time.Time
+
+	flagPlaceholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+	analysisFieldsOnce sync.Once
+	analysisFields     map[string]int // json 标签 -> 字段下标
+)
+
+// ActiveRiskRuleSet 获取当前生效的规则集（未加载外部文件时使用内置默认规则）
+func ActiveRiskRuleSet() *RiskRuleSet {
+	if rs := activeRuleSet.Load(); rs != nil {
+		return rs
+	}
+	rs, err := ParseRiskRuleSet(config.DefaultRiskRulesJSON)
+	if err != nil {
+		// 内置规则随代码发布，正常不会失败；失败时所有代币都落入兜底等级，避免误判为低风险
+		logger.Log.Error("内置风险规则解析失败", zap.Error(err))
+		rs = &RiskRuleSet{Version: "invalid", Levels: []RiskLevelCutoff{{Level: "unknown"}}}
+	}
+	activeRuleSet.CompareAndSwap(nil, rs)
+	return activeRuleSet.Load()
+}
+
+// LoadRiskRuleSetFile 从文件加载规则集
+func LoadRiskRuleSetFile(path string) (*RiskRuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	return ParseRiskRuleSet(data)
+}
+
+// ReloadRiskRules 规则文件有变化时重新加载并替换当前规则集
+// 解析失败时保留原规则集
+func ReloadRiskRules(path string) {
+	ruleFileMu.Lock()
+	defer ruleFileMu.Unlock()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		logger.Log.Warn("读取风险规则文件失败", zap.String("path", path), zap.Error(err))
+		return
+	}
+	if !info.ModTime().After(ruleFileModTime) {
+		return
+	}
+
+	rs, err := LoadRiskRuleSetFile(path)
+	if err != nil {
+		logger.Log.Error("风险规则文件无效，继续使用原规则", zap.String("path", path), zap.Error(err))
+		return
+	}
+	ruleFileModTime = info.ModTime()
+
+	old := ActiveRiskRuleSet()
+	activeRuleSet.Store(rs)
+	logger.Log.Info("✅ 风险规则已加载",
+		zap.String("path", path),
+		zap.String("old_version", old.Version),
+		zap.String("version", rs.Version),
+		zap.Int("rules", len(rs.Rules)))
+}
+
+// ParseRiskRuleSet 解析并校验规则集
+func ParseRiskRuleSet(data []byte) (*RiskRuleSet, error) {
+	var rs RiskRuleSet
+	if err := json.Unmarshal(data, &rs); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	if rs.Version == "" {
+		return nil, fmt.Errorf("rules version is required")
+	}
+	if len(rs.Levels) == 0 || rs.Levels[len(rs.Levels)-1].Below != nil {
+		return nil, fmt.Errorf("levels must end with a fallback level without 'below'")
+	}
+
+	fields := tokenAnalysisFields()
+	for _, rule := range rs.Rules {
+		if rule.ID == "" {
+			return nil, fmt.Errorf("rule id is required")
+		}
+		if rule.WeightField != "" {
+			if _, ok := fields[rule.WeightField]; !ok {
+				return nil, fmt.Errorf("rule %s: unknown weight_field %s", rule.ID, rule.WeightField)
+			}
+		}
+		for _, cond := range rule.When {
+			if _, ok := fields[cond.Field]; !ok {
+				return nil, fmt.Errorf("rule %s: unknown field %s", rule.ID, cond.Field)
+			}
+			switch cond.Op {
+			case RuleOpEq, RuleOpNe, RuleOpGt, RuleOpGte, RuleOpLt, RuleOpLte, RuleOpHas, RuleOpEmpty, RuleOpNotEmpty:
+			default:
+				return nil, fmt.Errorf("rule %s: unknown op %s", rule.ID, cond.Op)
+			}
+		}
+	}
+
+	return &rs, nil
+}
+
+// Evaluate 按规则集计算风险评分
+func (rs *RiskRuleSet) Evaluate(analysis *model.TokenAnalysis) (float64, string, []string) {
+	value := reflect.ValueOf(analysis).Elem()
+	score := 0.0
+	riskFlags := []string{}
+
+	for _, rule := range rs.Rules {
+		if !rule.matches(value) {
+			continue
+		}
+		weight := rule.Weight
+		if rule.WeightField != "" {
+			weight *= toFloat(fieldValue(value, rule.WeightField))
+		}
+		score += weight
+		if rule.Flag != "" {
+			riskFlags = append(riskFlags, formatRuleFlag(rule.Flag, value))
+		}
+	}
+
+	if rs.MaxScore > 0 && score > rs.MaxScore {
+		score = rs.MaxScore
+	}
+
+	return score, rs.levelFor(score), riskFlags
+}
+
+// levelFor 根据分界确定风险等级
+func (rs *RiskRuleSet) levelFor(score float64) string {
+	for _, cutoff := range rs.Levels {
+		if cutoff.Below == nil || score < *cutoff.Below {
+			return cutoff.Level
+		}
+	}
+	return ""
+}
+
+// matches 判断规则的全部条件是否满足
+func (r *RiskRule) matches(value reflect.Value) bool {
+	for _, cond := range r.When {
+		if !cond.matches(fieldValue(value, cond.Field)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches 判断单个条件是否满足
+func (c *RiskCondition) matches(field reflect.Value) bool {
+	switch c.Op {
+	case RuleOpEmpty:
+		return field.IsZero()
+	case RuleOpNotEmpty:
+		return !field.IsZero()
+	case RuleOpHas:
+		var items []string
+		if err := json.Unmarshal([]byte(field.String()), &items); err != nil {
+			return false
+		}
+		want := fmt.Sprint(c.Value)
+		for _, item := range items {
+			if item == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch field.Kind() {
+	case reflect.Bool:
+		want, ok := c.Value.(bool)
+		if !ok {
+			return false
+		}
+		return compareEquality(c.Op, field.Bool() == want)
+	case reflect.String:
+		return compareEquality(c.Op, field.String() == fmt.Sprint(c.Value))
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint64, reflect.Float64:
+		want, ok := c.Value.(float64)
+		if !ok {
+			return false
+		}
+		got := toFloat(field)
+		switch c.Op {
+		case RuleOpEq:
+			return got == want
+		case RuleOpNe:
+			return got != want
+		case RuleOpGt:
+			return got > want
+		case RuleOpGte:
+			return got >= want
+		case RuleOpLt:
+			return got < want
+		case RuleOpLte:
+			return got <= want
+		}
+	}
+	return false
+}
+
+// compareEquality 仅支持 eq / ne 的类型
+func compareEquality(op string, equal bool) bool {
+	switch op {
+	case RuleOpEq:
+		return equal
+	case RuleOpNe:
+		return !equal
+	}
+	return false
+}
+
+// formatRuleFlag 替换风险描述中的 {field} 占位符
+func formatRuleFlag(flag string, value reflect.Value) string {
+	return flagPlaceholderPattern.ReplaceAllStringFunc(flag, func(match string) string {
+		name := strings.Trim(match, "{}")
+		if _, ok := tokenAnalysisFields()[name]; !ok {
+			return match
+		}
+		field := fieldValue(value, name)
+		if field.Kind() == reflect.Float64 {
+			return formatFloat(field.Float())
+		}
+		return fmt.Sprint(field.Interface())
+	})
+}
+
+// fieldValue 按 json 标签取字段值
+func fieldValue(value reflect.Value, name string) reflect.Value {
+	return value.Field(tokenAnalysisFields()[name])
+}
+
+// toFloat 将数值字段转换为 float64
+func toFloat(field reflect.Value) float64 {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int64:
+		return float64(field.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint64:
+		return float64(field.Uint())
+	case reflect.Float64:
+		return field.Float()
+	}
+	return 0
+}
+
+// tokenAnalysisFields TokenAnalysis 的 json 标签到字段下标的映射
+func tokenAnalysisFields() map[string]int {
+	analysisFieldsOnce.Do(func() {
+		analysisFields = make(map[string]int)
+		t := reflect.TypeOf(model.TokenAnalysis{})
+		for i := 0; i < t.NumField(); i++ {
+			tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if tag != "" && tag != "-" {
+				analysisFields[tag] = i
+			}
+		}
+	})
+	return analysisFields
+}
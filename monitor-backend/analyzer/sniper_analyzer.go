@@ -0,0 +1,322 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"ethereum-monitor/config"
+	"ethereum-monitor/database"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// 早期买家分类
+const (
+	BuyerClassSniper  = "sniper"  // 已知 MEV / 狙击机器人，或自建合约买入
+	BuyerClassInsider = "insider" // 部署者本人或由部署者资助的钱包
+	BuyerClassOrganic = "organic"
+)
+
+// pairSwapSignature 直接调用交易对 swap 的机器人合约会包含该选择器
+const pairSwapSignature = "swap(uint256,uint256,address,bytes)"
+
+// earlyBuy 早期买入（同一交易发起者的多笔买入合并）
+type earlyBuy struct {
+	Buyer       common.Address // 交易发起者
+	Recipient   common.Address // Swap 事件中的 to
+	Entry       common.Address // 交易调用的合约
+	Amount      *big.Int       // 买入代币数量
+	LaunchBlock bool           // 是否在加池区块内买入
+
+	// 首笔买入交易的订单流
+	MempoolCovered bool // 出块时内存池监听在线，Private 结论有效
+	Private        bool // 打包前未在公开内存池出现
+	CoinbaseTip    bool // 交易内直接向 Builder 支付小费
+}
+
+// SniperAnalyzer 早期买家 / 狙击机器人分析器
+// 解码交易对在加池后前 N 个区块的 Swap 事件，把买家分为机器人、部署者关联钱包和普通买家
+type SniperAnalyzer struct {
+	client    *ethclient.Client
+	explorer  *utils.ExplorerClient
+	orderflow *utils.PrivateOrderflowDetector
+}
+
+// NewSniperAnalyzer 创建早期买家分析器
+func NewSniperAnalyzer(rpcURL, explorerURL, apiKey string) (*SniperAnalyzer, error) {
+	client, err := utils.DialRateLimitedEthClient(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial rpc: %w", err)
+	}
+	return &SniperAnalyzer{
+		client:    client,
+		explorer:  utils.NewExplorerClient(explorerURL, apiKey),
+		orderflow: utils.NewPrivateOrderflowDetector(client),
+	}, nil
+}
+
+// Analyze 分析早期买家，窗口内区块全部出块后标记 SniperScanned，之后不再重复分析
+// 需要在持有者分析之后调用（由其确定 CreationBlock 与 DeployerAddress）
+func (s *SniperAnalyzer) Analyze(analysis *model.TokenAnalysis) error {
+	if analysis.SniperScanned || analysis.PairAddress == "" {
+		return nil
+	}
+	ctx := context.Background()
+	pair := common.HexToAddress(analysis.PairAddress)
+
+	latest, err := s.client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	// 1. 定位加池区块
+	if analysis.LaunchBlock == 0 {
+		launchBlock, err := s.findLaunchBlock(ctx, pair, analysis.CreationBlock, latest)
+		if err != nil {
+			return err
+		}
+		if launchBlock == 0 {
+			return nil
+		}
+		analysis.LaunchBlock = launchBlock
+	}
+
+	windowEnd := analysis.LaunchBlock + config.SniperWindowBlocks - 1
+	if latest < windowEnd {
+		// 窗口尚未结束，下次再分析
+		return nil
+	}
+
+	// 2. 解码窗口内的买入
+	buys, err := s.collectBuys(ctx, analysis, pair, windowEnd)
+	if err != nil {
+		return err
+	}
+
+	// 3. 分类并汇总
+	totalSupply, _ := new(big.Int).SetString(analysis.TotalSupply, 10)
+	sniperAmount, insiderAmount := new(big.Int), new(big.Int)
+	analysis.EarlyBuyerCount = len(buys)
+	analysis.SniperBuyerCount, analysis.InsiderBuyerCount = 0, 0
+	analysis.BundledLaunch = false
+	analysis.PrivateBuyerCount, analysis.TipBuyerCount = 0, 0
+	analysis.LaunchMempoolCovered = len(buys) > 0
+
+	for _, buy := range buys {
+		if !buy.MempoolCovered {
+			analysis.LaunchMempoolCovered = false
+		}
+		if buy.Private {
+			analysis.PrivateBuyerCount++
+		}
+		if buy.CoinbaseTip {
+			analysis.TipBuyerCount++
+		}
+
+		switch s.classifyBuyer(ctx, analysis, buy) {
+		case BuyerClassSniper:
+			analysis.SniperBuyerCount++
+			sniperAmount.Add(sniperAmount, buy.Amount)
+		case BuyerClassInsider:
+			analysis.InsiderBuyerCount++
+			insiderAmount.Add(insiderAmount, buy.Amount)
+			if buy.LaunchBlock {
+				analysis.BundledLaunch = true
+			}
+		}
+	}
+	if totalSupply != nil && totalSupply.Sign() > 0 {
+		analysis.SniperSharePct = percentOf(sniperAmount, totalSupply)
+		analysis.InsiderSharePct = percentOf(insiderAmount, totalSupply)
+	}
+	analysis.SniperScanned = true
+
+	logger.Log.Info("早期买家分析完成",
+		zap.String("token", analysis.TokenAddress),
+		zap.Uint64("launchBlock", analysis.LaunchBlock),
+		zap.Int("buyers", analysis.EarlyBuyerCount),
+		zap.Int("snipers", analysis.SniperBuyerCount),
+		zap.Int("insiders", analysis.InsiderBuyerCount),
+		zap.Int("private", analysis.PrivateBuyerCount),
+		zap.Int("tipped", analysis.TipBuyerCount),
+		zap.Float64("sniperPct", analysis.SniperSharePct),
+		zap.Bool("bundled", analysis.BundledLaunch))
+
+	return nil
+}
+
+// findLaunchBlock 查找交易对首个 Mint 事件所在区块，尚未加池时返回 0
+func (s *SniperAnalyzer) findLaunchBlock(ctx context.Context, pair common.Address, from, latest uint64) (uint64, error) {
+	if from == 0 || latest-from > config.HolderScanMaxLookbackBlocks {
+		from = latest - config.HolderScanMaxLookbackBlocks
+	}
+	mintTopic := common.HexToHash(config.UniswapV2MintTopic)
+	for start := from; start <= latest; start += config.HolderScanChunkBlocks {
+		end := start + config.HolderScanChunkBlocks - 1
+		if end > latest {
+			end = latest
+		}
+		logs, err := s.client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+			Addresses: []common.Address{pair},
+			Topics:    [][]common.Hash{{mintTopic}},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to filter mint logs [%d, %d]: %w", start, end, err)
+		}
+		if len(logs) > 0 {
+			return logs[0].BlockNumber, nil
+		}
+	}
+	return 0, nil
+}
+
+// collectBuys 解码窗口内 Swap 事件中买入代币的部分，按交易发起者合并
+func (s *SniperAnalyzer) collectBuys(ctx context.Context, analysis *model.TokenAnalysis, pair common.Address, windowEnd uint64) ([]*earlyBuy, error) {
+	logs, err := s.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(analysis.LaunchBlock),
+		ToBlock:   new(big.Int).SetUint64(windowEnd),
+		Addresses: []common.Address{pair},
+		Topics:    [][]common.Hash{{common.HexToHash(config.UniswapV2SwapTopic)}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter swap logs: %w", err)
+	}
+
+	// Uniswap V2 中地址较小的一方为 token0
+	tokenIsToken0 := bytes.Compare(common.HexToAddress(analysis.TokenAddress).Bytes(), common.HexToAddress(config.WETHAddress).Bytes()) < 0
+
+	var buys []*earlyBuy
+	byBuyer := make(map[common.Address]*earlyBuy)
+	headers := make(map[uint64]*types.Header)
+	for _, l := range logs {
+		if len(l.Data) < 128 || len(l.Topics) < 3 {
+			continue
+		}
+		amountOut := new(big.Int).SetBytes(l.Data[96:128]) // amount1Out
+		if tokenIsToken0 {
+			amountOut = new(big.Int).SetBytes(l.Data[64:96]) // amount0Out
+		}
+		if amountOut.Sign() == 0 {
+			continue // 卖出
+		}
+
+		tx, _, err := s.client.TransactionByHash(ctx, l.TxHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get swap tx %s: %w", l.TxHash.Hex(), err)
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		if err != nil {
+			continue
+		}
+
+		if buy, ok := byBuyer[sender]; ok {
+			buy.Amount.Add(buy.Amount, amountOut)
+			continue
+		}
+		if len(buys) >= config.SniperMaxBuyers {
+			continue
+		}
+		buy := &earlyBuy{
+			Buyer:       sender,
+			Recipient:   common.BytesToAddress(l.Topics[2].Bytes()),
+			Amount:      amountOut,
+			LaunchBlock: l.BlockNumber == analysis.LaunchBlock,
+		}
+		if tx.To() != nil {
+			buy.Entry = *tx.To()
+		}
+		s.inspectOrderflow(ctx, buy, tx, l.BlockNumber, headers)
+		byBuyer[sender] = buy
+		buys = append(buys, buy)
+	}
+	return buys, nil
+}
+
+// inspectOrderflow 检查买家首笔买入交易是否经私有通道提交，区块头按区块号缓存
+func (s *SniperAnalyzer) inspectOrderflow(ctx context.Context, buy *earlyBuy, tx *types.Transaction, blockNumber uint64, headers map[uint64]*types.Header) {
+	header, ok := headers[blockNumber]
+	if !ok {
+		var err error
+		header, err = s.client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+		if err != nil {
+			return
+		}
+		headers[blockNumber] = header
+	}
+
+	inspection := s.orderflow.Inspect(ctx, tx, header)
+	buy.MempoolCovered = inspection.MempoolCovered
+	buy.Private = inspection.PrivateOrderflow
+	buy.CoinbaseTip = inspection.CoinbaseTipWei != nil
+}
+
+// classifyBuyer 判断买家类型
+func (s *SniperAnalyzer) classifyBuyer(ctx context.Context, analysis *model.TokenAnalysis, buy *earlyBuy) string {
+	buyer := strings.ToLower(buy.Buyer.Hex())
+	recipient := strings.ToLower(buy.Recipient.Hex())
+	entry := strings.ToLower(buy.Entry.Hex())
+	deployer := strings.ToLower(analysis.DeployerAddress)
+
+	// 1. 部署者本人或其资助的钱包
+	if deployer != "" {
+		if buyer == deployer || recipient == deployer {
+			return BuyerClassInsider
+		}
+		if funder, err := firstFunder(s.explorer, buyer); err == nil && funder == deployer {
+			return BuyerClassInsider
+		}
+	}
+
+	// 2. 已知 MEV / 狙击机器人
+	labels := database.GetAddressLabelRegistry()
+	if isBot, _ := labels.IsMevBot(buyer); isBot {
+		return BuyerClassSniper
+	}
+	if isBot, _ := labels.IsMevBot(recipient); isBot {
+		return BuyerClassSniper
+	}
+	if label, ok := labels.Lookup(entry); ok && label.Category == model.AddressCategorySniperRouter {
+		return BuyerClassSniper
+	}
+
+	// 3. 首笔买入直接向 Builder 支付小费（Bundle 抢跑）
+	if buy.CoinbaseTip {
+		return BuyerClassSniper
+	}
+
+	// 4. 字节码特征：经由非常见路由的自建合约买入，或接收方合约会直接调用交易对 swap
+	if _, ok := config.KnownSwapRouters[entry]; !ok && buy.Entry != (common.Address{}) && !strings.EqualFold(entry, analysis.PairAddress) {
+		if s.hasCode(ctx, buy.Entry) {
+			return BuyerClassSniper
+		}
+	}
+	if code, err := s.client.CodeAt(ctx, buy.Recipient, nil); err == nil && len(code) > 0 {
+		if hasAnySelector(extractSelectors(code), []string{pairSwapSignature}) {
+			return BuyerClassSniper
+		}
+	}
+
+	return BuyerClassOrganic
+}
+
+// hasCode 判断地址是否为合约
+func (s *SniperAnalyzer) hasCode(ctx context.Context, addr common.Address) bool {
+	code, err := s.client.CodeAt(ctx, addr, nil)
+	return err == nil && len(code) > 0
+}
+
+// Close 关闭客户端
+func (s *SniperAnalyzer) Close() {
+	s.client.Close()
+}
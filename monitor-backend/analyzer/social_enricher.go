@@ -0,0 +1,360 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"ethereum-monitor/config"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+var (
+	// socialURLPattern 带协议的链接，或不带协议的 www. / t.me / twitter.com / x.com 链接
+	socialURLPattern    = regexp.MustCompile(`(?i)(?:https?://|www\.|\bt\.me/|\btwitter\.com/|\bx\.com/)[^\s"'<>()\[\]{}*]+`)
+	socialHandlePattern = regexp.MustCompile(`^@?\w{1,32}$`)
+)
+
+// socialGetters 常见的链上社交信息 getter（多为 string public 常量）
+var socialGetters = map[string][]string{
+	"website":  {"website()", "WEBSITE()", "Website()"},
+	"twitter":  {"twitter()", "TWITTER()", "Twitter()"},
+	"telegram": {"telegram()", "TELEGRAM()", "Telegram()"},
+}
+
+// contractURISignature ERC-7572 合约元数据
+const contractURISignature = "contractURI()"
+
+// socialLinks 社交链接
+type socialLinks struct {
+	Website  string
+	Twitter  string
+	Telegram string
+}
+
+// SocialEnricher 社交信息与 ENS 补充
+// 源码注释中的链接由 ContractVerifier 提取；这里读取链上元数据，并反向解析部署者与所有者的 ENS
+type SocialEnricher struct {
+	client *ethclient.Client
+}
+
+// NewSocialEnricher 创建社交信息补充器
+func NewSocialEnricher(rpcURL string) (*SocialEnricher, error) {
+	client, err := utils.DialRateLimitedEthClient(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial rpc: %w", err)
+	}
+	return &SocialEnricher{client: client}, nil
+}
+
+// Enrich 补充缺失的社交链接与 ENS 名称，已有的值不覆盖
+func (e *SocialEnricher) Enrich(analysis *model.TokenAnalysis) error {
+	ctx := context.Background()
+
+	if analysis.Website == "" || analysis.Twitter == "" || analysis.Telegram == "" {
+		links, err := e.readOnChainSocials(ctx, analysis)
+		if err != nil {
+			return fmt.Errorf("failed to read on-chain socials: %w", err)
+		}
+		applySocialLinks(analysis, links)
+	}
+
+	if analysis.DeployerENS == "" && analysis.DeployerAddress != "" {
+		analysis.DeployerENS = e.lookupENS(ctx, analysis.DeployerAddress)
+	}
+	if analysis.OwnerENS == "" && analysis.OwnerAddress != "" && !analysis.IsOwnershipRenounced {
+		analysis.OwnerENS = e.lookupENS(ctx, analysis.OwnerAddress)
+	}
+
+	logger.Log.Info("社交信息补充完成",
+		zap.String("token", analysis.TokenAddress),
+		zap.String("website", analysis.Website),
+		zap.String("twitter", analysis.Twitter),
+		zap.String("telegram", analysis.Telegram),
+		zap.String("deployer_ens", analysis.DeployerENS),
+		zap.String("owner_ens", analysis.OwnerENS))
+
+	return nil
+}
+
+// readOnChainSocials 调用字节码中存在的社交 getter 与 contractURI()
+func (e *SocialEnricher) readOnChainSocials(ctx context.Context, analysis *model.TokenAnalysis) (socialLinks, error) {
+	var links socialLinks
+	token := common.HexToAddress(analysis.TokenAddress)
+
+	code, err := e.client.CodeAt(ctx, token, nil)
+	if err != nil {
+		return links, err
+	}
+	selectors := extractSelectors(code)
+	if analysis.ProxyImplementation != "" {
+		if implCode, err := e.client.CodeAt(ctx, common.HexToAddress(analysis.ProxyImplementation), nil); err == nil {
+			for sel := range extractSelectors(implCode) {
+				selectors[sel] = struct{}{}
+			}
+		}
+	}
+
+	read := func(kind string) string {
+		for _, sig := range socialGetters[kind] {
+			if !hasAnySelector(selectors, []string{sig}) {
+				continue
+			}
+			if value, err := e.callString(ctx, token, sig); err == nil && value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+	links.Website = normalizeSocialValue("website", read("website"))
+	links.Twitter = normalizeSocialValue("twitter", read("twitter"))
+	links.Telegram = normalizeSocialValue("telegram", read("telegram"))
+
+	if hasAnySelector(selectors, []string{contractURISignature}) {
+		if uri, err := e.callString(ctx, token, contractURISignature); err == nil {
+			fromURI := parseContractURI(uri)
+			if links.Website == "" {
+				links.Website = fromURI.Website
+			}
+			if links.Twitter == "" {
+				links.Twitter = fromURI.Twitter
+			}
+			if links.Telegram == "" {
+				links.Telegram = fromURI.Telegram
+			}
+		}
+	}
+
+	return links, nil
+}
+
+// lookupENS 反向解析地址的 ENS 主名称，并用正向解析校验，失败时返回空
+func (e *SocialEnricher) lookupENS(ctx context.Context, address string) string {
+	addr := common.HexToAddress(address)
+	reverseNode := ensNamehash(strings.ToLower(addr.Hex()[2:]) + ".addr.reverse")
+
+	resolver, err := e.ensResolver(ctx, reverseNode)
+	if err != nil || resolver == (common.Address{}) {
+		return ""
+	}
+	name, err := e.callString(ctx, resolver, "name(bytes32)", reverseNode[:]...)
+	if err != nil || name == "" {
+		return ""
+	}
+
+	// 反向记录可由地址所有者任意设置，必须正向解析回同一地址才可信
+	forwardNode := ensNamehash(name)
+	forwardResolver, err := e.ensResolver(ctx, forwardNode)
+	if err != nil || forwardResolver == (common.Address{}) {
+		return ""
+	}
+	result, err := e.call(ctx, forwardResolver, "addr(bytes32)", forwardNode[:]...)
+	if err != nil || len(result) < 32 || common.BytesToAddress(result[12:32]) != addr {
+		return ""
+	}
+	return name
+}
+
+// ensResolver 查询 ENS 注册表中节点的解析器
+func (e *SocialEnricher) ensResolver(ctx context.Context, node common.Hash) (common.Address, error) {
+	result, err := e.call(ctx, common.HexToAddress(config.ENSRegistryAddress), "resolver(bytes32)", node[:]...)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(result) < 32 {
+		return common.Address{}, fmt.Errorf("unexpected resolver result length %d", len(result))
+	}
+	return common.BytesToAddress(result[12:32]), nil
+}
+
+// call 调用参数为静态类型的方法，args 为 ABI 编码后的参数
+func (e *SocialEnricher) call(ctx context.Context, to common.Address, signature string, args ...byte) ([]byte, error) {
+	data := append(crypto.Keccak256([]byte(signature))[:4], args...)
+	return e.client.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+}
+
+// callString 调用返回 string 的方法
+func (e *SocialEnricher) callString(ctx context.Context, to common.Address, signature string, args ...byte) (string, error) {
+	result, err := e.call(ctx, to, signature, args...)
+	if err != nil {
+		return "", err
+	}
+	stringType, _ := abi.NewType("string", "", nil)
+	unpacked, err := abi.Arguments{{Type: stringType}}.Unpack(result)
+	if err != nil {
+		return "", err
+	}
+	value, _ := unpacked[0].(string)
+	return strings.TrimSpace(value), nil
+}
+
+// Close 关闭客户端
+func (e *SocialEnricher) Close() {
+	e.client.Close()
+}
+
+// ensNamehash 计算 ENS namehash（名称需已规范化为小写）
+func ensNamehash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		node = common.BytesToHash(crypto.Keccak256(node[:], labelHash))
+	}
+	return node
+}
+
+// extractSourceSocials 从源码注释中提取社交链接（项目方通常写在文件头注释里）
+func extractSourceSocials(source string) socialLinks {
+	var comments strings.Builder
+	for _, comment := range solidityCommentPattern.FindAllString(source, -1) {
+		comments.WriteString(comment)
+		comments.WriteString("\n")
+	}
+	return classifySocialURLs(socialURLPattern.FindAllString(comments.String(), -1))
+}
+
+// parseContractURI 解析 data: 形式的 contractURI 元数据（外部 URI 不主动请求）
+func parseContractURI(uri string) socialLinks {
+	const jsonPrefix = "data:application/json"
+	if !strings.HasPrefix(uri, jsonPrefix) {
+		return socialLinks{}
+	}
+	header, payload, ok := strings.Cut(uri[len(jsonPrefix):], ",")
+	if !ok {
+		return socialLinks{}
+	}
+	data := []byte(payload)
+	if strings.Contains(header, ";base64") {
+		decoded, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return socialLinks{}
+		}
+		data = decoded
+	} else if unescaped, err := url.PathUnescape(payload); err == nil {
+		data = []byte(unescaped)
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return socialLinks{}
+	}
+	str := func(keys ...string) string {
+		for _, key := range keys {
+			if value, ok := metadata[key].(string); ok && value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+	return socialLinks{
+		Website:  normalizeSocialValue("website", str("external_link", "external_url", "website")),
+		Twitter:  normalizeSocialValue("twitter", str("twitter", "x")),
+		Telegram: normalizeSocialValue("telegram", str("telegram")),
+	}
+}
+
+// classifySocialURLs 按域名归类链接，每类取第一个
+func classifySocialURLs(urls []string) socialLinks {
+	var links socialLinks
+	for _, raw := range urls {
+		link := normalizeURL(raw)
+		parsed, err := url.Parse(link)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		host := strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+		path := strings.Trim(parsed.Path, "/")
+
+		switch {
+		case host == "twitter.com" || host == "x.com":
+			if links.Twitter == "" && path != "" && !strings.HasPrefix(path, "intent") {
+				links.Twitter = link
+			}
+		case host == "t.me" || host == "telegram.me":
+			if links.Telegram == "" && path != "" {
+				links.Telegram = link
+			}
+		default:
+			if links.Website == "" && !isIgnoredSocialDomain(host) {
+				links.Website = link
+			}
+		}
+	}
+	return links
+}
+
+// normalizeSocialValue 规范化链上 getter 返回的值，支持 @handle 形式
+func normalizeSocialValue(kind, value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	if socialHandlePattern.MatchString(value) {
+		handle := strings.TrimPrefix(value, "@")
+		switch kind {
+		case "twitter":
+			return "https://x.com/" + handle
+		case "telegram":
+			return "https://t.me/" + handle
+		}
+		return ""
+	}
+
+	links := classifySocialURLs([]string{value})
+	switch kind {
+	case "twitter":
+		return links.Twitter
+	case "telegram":
+		return links.Telegram
+	default:
+		return links.Website
+	}
+}
+
+// normalizeURL 去掉结尾标点并补全协议
+func normalizeURL(raw string) string {
+	link := strings.TrimRight(raw, ".,;:!?'\"`")
+	if !strings.HasPrefix(strings.ToLower(link), "http://") && !strings.HasPrefix(strings.ToLower(link), "https://") {
+		link = "https://" + link
+	}
+	return link
+}
+
+// isIgnoredSocialDomain 判断是否为库文档、标准等非项目官网域名
+func isIgnoredSocialDomain(host string) bool {
+	for _, domain := range config.SocialIgnoredDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// applySocialLinks 填充缺失的社交链接
+func applySocialLinks(analysis *model.TokenAnalysis, links socialLinks) {
+	if analysis.Website == "" {
+		analysis.Website = links.Website
+	}
+	if analysis.Twitter == "" {
+		analysis.Twitter = links.Twitter
+	}
+	if analysis.Telegram == "" {
+		analysis.Telegram = links.Telegram
+	}
+}
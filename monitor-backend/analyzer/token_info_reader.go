@@ -2,6 +2,7 @@ package analyzer
 
 import (
 	"context"
+	"ethereum-monitor/utils"
 	"fmt"
 	"math/big"
 	"strings"
@@ -28,7 +29,7 @@ type TokenInfo struct {
 
 // NewTokenInfoReader 创建代币信息读取器
 func NewTokenInfoReader(rpcURL string) (*TokenInfoReader, error) {
-	client, err := ethclient.Dial(rpcURL)
+	client, err := utils.DialRateLimitedEthClient(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to ethereum client: %w", err)
 	}
@@ -80,15 +81,6 @@ func (r *TokenInfoReader) ReadTokenInfo(tokenAddress string) (*TokenInfo, error)
 	return info, nil
 }
 
-// IsERC20Token 检查是否是 ERC20 代币
-func (r *TokenInfoReader) IsERC20Token(contractAddress string) bool {
-	info, err := r.ReadTokenInfo(contractAddress)
-	if err != nil {
-		return false
-	}
-	return info.IsValid
-}
-
 // callStringMethod 调用返回 string 的方法
 func (r *TokenInfoReader) callStringMethod(contract common.Address, method string) (string, error) {
 	// 构造方法签名
@@ -3,8 +3,10 @@ package analyzer
 import (
 	"encoding/json"
 	"ethereum-monitor/config"
+	"ethereum-monitor/database"
 	"ethereum-monitor/model"
 	"fmt"
+	"strings"
 )
 
 // TokenRiskScorer 代币风险评分器
@@ -15,80 +17,66 @@ func NewTokenRiskScorer() *TokenRiskScorer {
 	return &TokenRiskScorer{}
 }
 
-// CalculateRiskScore 计算风险评分
+// CalculateRiskScore 按当前生效的规则集计算风险评分，并记录评分所用的规则集版本
 func (s *TokenRiskScorer) CalculateRiskScore(analysis *model.TokenAnalysis) (float64, string, []string) {
-	score := 0.0
-	riskFlags := []string{}
-
-	// 1. 未验证合约 +30
-	if !analysis.IsVerified {
-		score += config.RiskScoreUnverified
-		riskFlags = append(riskFlags, "合约未验证")
-	}
-
-	// 2. 蜜罐 +50
-	if analysis.IsHoneypot {
-		score += config.RiskScoreHoneypot
-		riskFlags = append(riskFlags, "⚠️ 检测到蜜罐: "+analysis.HoneypotReason)
-	}
-
-	// 3. 高税率 (>10%) +20
-	if analysis.BuyTax > config.HighTaxThreshold {
-		score += config.RiskScoreHighTax
-		riskFlags = append(riskFlags, "买入税过高: "+formatPercent(analysis.BuyTax))
-	}
-	if analysis.SellTax > config.HighTaxThreshold {
-		score += config.RiskScoreHighTax
-		riskFlags = append(riskFlags, "卖出税过高: "+formatPercent(analysis.SellTax))
-	}
-
-	// 4. 持有者过度集中 (>50%) +25
-	if analysis.Top10HoldingPct > config.Top10HoldingThreshold {
-		score += config.RiskScoreConcentratedHolding
-		riskFlags = append(riskFlags, "持有者过度集中: 前10持有"+formatPercent(analysis.Top10HoldingPct))
-	}
-
-	// 5. 无流动性 +40
-	if !analysis.HasLiquidity {
-		score += config.RiskScoreNoLiquidity
-		riskFlags = append(riskFlags, "无流动性")
-	} else if analysis.LiquidityUSD < config.MemeMinLiquidityUSD {
-		score += config.RiskScoreNoLiquidity / 2 // 流动性不足 +20
-		riskFlags = append(riskFlags, "流动性不足: $"+formatFloat(analysis.LiquidityUSD))
-	}
-
-	// 6. 未放弃所有权 +15
-	if !analysis.IsOwnershipRenounced && analysis.OwnerAddress != "" {
-		score += config.RiskScoreNotRenounced
-		riskFlags = append(riskFlags, "未放弃所有权")
-	}
-
-	// 限制最大值为 100
-	if score > 100 {
-		score = 100
-	}
-
-	// 确定风险等级
-	riskLevel := s.determineRiskLevel(score)
+	ruleSet := ActiveRiskRuleSet()
+	analysis.RuleSetVersion = ruleSet.Version
+	return ruleSet.Evaluate(analysis)
+}
 
-	return score, riskLevel, riskFlags
+// RescoreSummary 重新评分结果汇总
+type RescoreSummary struct {
+	Version      string
+	Total        int
+	Changed      int            // 风险等级发生变化的数量
+	LevelChanges map[string]int // "旧等级 -> 新等级" 的数量
 }
 
-// determineRiskLevel 确定风险等级
-func (s *TokenRiskScorer) determineRiskLevel(score float64) string {
-	if score < 20 {
-		return "low"
-	} else if score < 40 {
-		return "medium"
-	} else if score < 70 {
-		return "high"
+// RescoreTokens 用指定规则集重新评估已保存的全部代币
+// dryRun 为 true 时只统计变化，不写回数据库
+func RescoreTokens(ruleSet *RiskRuleSet, dryRun bool) (*RescoreSummary, error) {
+	repo := database.NewTokenAnalysisRepository()
+	summary := &RescoreSummary{Version: ruleSet.Version, LevelChanges: make(map[string]int)}
+
+	err := repo.FindInBatches(200, func(batch []model.TokenAnalysis) error {
+		rescored := make([]model.TokenAnalysis, 0, len(batch))
+		for i := range batch {
+			analysis := &batch[i]
+			oldLevel := analysis.RiskLevel
+			// 尚未完成安全分析的代币没有可比较的评分，跳过
+			if oldLevel == "" || oldLevel == "unknown" {
+				continue
+			}
+
+			score, level, flags := ruleSet.Evaluate(analysis)
+			flagsJSON, _ := json.Marshal(flags)
+			analysis.RiskScore = score
+			analysis.RiskLevel = level
+			analysis.RiskFlags = string(flagsJSON)
+			analysis.RuleSetVersion = ruleSet.Version
+
+			summary.Total++
+			if oldLevel != level {
+				summary.Changed++
+				summary.LevelChanges[oldLevel+" -> "+level]++
+			}
+			rescored = append(rescored, *analysis)
+		}
+		if dryRun || len(rescored) == 0 {
+			return nil
+		}
+		return repo.SaveRiskScores(rescored)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to rescore tokens: %w", err)
 	}
-	return "critical"
+
+	return summary, nil
 }
 
-// IsLowRisk 判断是否是低风险代币
+// IsLowRisk 判断是否是低风险代币（以当前规则集评出的风险等级为准）
 func (s *TokenRiskScorer) IsLowRisk(analysis *model.TokenAnalysis) bool {
-	return analysis.RiskScore < config.MemeRiskScoreThresholdLow
+	return analysis.RiskLevel == "low"
 }
 
 // IsPotentialGem 判断是否是潜力币
@@ -129,12 +117,52 @@ func (s *TokenRiskScorer) GenerateRiskReport(analysis *model.TokenAnalysis) stri
 	report += "💰 流动性: $" + formatFloat(analysis.LiquidityUSD) + "\n"
 	report += "📈 初始市值: $" + formatFloat(analysis.InitialMarketCap) + "\n"
 	report += "👥 持有者数量: " + formatInt(analysis.HolderCount) + "\n"
+	report += "🐳 前10持有: " + formatPercent(analysis.Top10HoldingPct) + "\n"
+	report += "👤 部署者持有: " + formatPercent(analysis.DeployerHoldingPct) + "\n"
+	if analysis.DeployerSummary != "" {
+		report += "🧑‍💻 部署者: " + analysis.DeployerSummary + "\n"
+	}
+	if analysis.SniperScanned {
+		report += fmt.Sprintf("🎯 早期买家: %d (机器人 %d, 部署者关联 %d)，机器人占 %s\n",
+			analysis.EarlyBuyerCount, analysis.SniperBuyerCount, analysis.InsiderBuyerCount, formatPercent(analysis.SniperSharePct))
+		if analysis.LaunchMempoolCovered {
+			report += fmt.Sprintf("🕶️ 私有订单流买家: %d\n", analysis.PrivateBuyerCount)
+		}
+		if analysis.TipBuyerCount > 0 {
+			report += fmt.Sprintf("💰 向 Builder 付小费的买家: %d\n", analysis.TipBuyerCount)
+		}
+	}
+	if socials := formatSocials(analysis); socials != "" {
+		report += "🌐 社交: " + socials + "\n"
+	}
+	if analysis.IsVerified {
+		report += "📄 源码: 已验证 (" + analysis.CompilerVersion + ")\n"
+	} else {
+		report += "📄 源码: 未验证\n"
+	}
 	report += "💸 买入税: " + formatPercent(analysis.BuyTax) + "\n"
 	report += "💸 卖出税: " + formatPercent(analysis.SellTax) + "\n"
 
 	return report
 }
 
+// formatSocials 拼接社交链接与 ENS
+func formatSocials(analysis *model.TokenAnalysis) string {
+	var parts []string
+	for _, link := range []string{analysis.Website, analysis.Twitter, analysis.Telegram} {
+		if link != "" {
+			parts = append(parts, link)
+		}
+	}
+	if analysis.DeployerENS != "" {
+		parts = append(parts, "部署者 "+analysis.DeployerENS)
+	}
+	if analysis.OwnerENS != "" {
+		parts = append(parts, "所有者 "+analysis.OwnerENS)
+	}
+	return strings.Join(parts, " | ")
+}
+
 // 辅助函数
 func formatPercent(value float64) string {
 	return fmt.Sprintf("%.2f%%", value)
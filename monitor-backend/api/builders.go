@@ -0,0 +1,89 @@
+package api
+
+import (
+	"ethereum-monitor/database"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Builders 区块 Builder 归属与统计：支持 block(单个区块)、stats=share(每日出块份额)、stats=mev(各 Builder 的 MEV 区块占比)、
+// start/end 时间范围（统计默认最近 7 天）、limit
+// GET /api/builders?block=19000000 | stats=share&start=2025-02-10T00:00:00Z | stats=mev | limit=20
+func Builders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JSONErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := parseLimit(q.Get("limit"), defaultLimit, maxLimit)
+	repo := database.NewBlockBuilderRepository()
+
+	// 1) 按区块号查单条
+	if blockStr := strings.TrimSpace(q.Get("block")); blockStr != "" {
+		number, err := strconv.ParseUint(blockStr, 10, 64)
+		if err != nil {
+			JSONErr(w, http.StatusBadRequest, "invalid block")
+			return
+		}
+		builder, err := repo.GetByBlock(number)
+		if err != nil {
+			JSONErr(w, http.StatusNotFound, "not found")
+			return
+		}
+		JSON(w, http.StatusOK, builder)
+		return
+	}
+
+	// 2) 统计，默认最近 7 天
+	if stats := strings.ToLower(strings.TrimSpace(q.Get("stats"))); stats != "" {
+		end := time.Now()
+		start := end.AddDate(0, 0, -7)
+		if startStr := strings.TrimSpace(q.Get("start")); startStr != "" {
+			t, err := time.Parse(time.RFC3339, startStr)
+			if err != nil {
+				JSONErr(w, http.StatusBadRequest, "invalid start, use RFC3339")
+				return
+			}
+			start = t
+		}
+		if endStr := strings.TrimSpace(q.Get("end")); endStr != "" {
+			t, err := time.Parse(time.RFC3339, endStr)
+			if err != nil {
+				JSONErr(w, http.StatusBadRequest, "invalid end, use RFC3339")
+				return
+			}
+			end = t
+		}
+
+		switch stats {
+		case "share":
+			list, err := repo.GetDailyShare(start, end)
+			if err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			JSON(w, http.StatusOK, list)
+		case "mev":
+			list, err := repo.GetMevShare(start, end)
+			if err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			JSON(w, http.StatusOK, list)
+		default:
+			JSONErr(w, http.StatusBadRequest, "invalid stats, use share or mev")
+		}
+		return
+	}
+
+	// 3) 默认：最近 N 个区块
+	list, err := repo.GetRecent(limit)
+	if err != nil {
+		JSONErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, list)
+}
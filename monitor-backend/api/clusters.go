@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"ethereum-monitor/database"
+	"ethereum-monitor/model"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// clusterRequest 新建 / 修改地址实体的请求体
+type clusterRequest struct {
+	ID        uint     `json:"id"`
+	Name      string   `json:"name"`
+	Category  string   `json:"category"`
+	Watched   *bool    `json:"watched"`
+	Addresses []string `json:"addresses"` // 手动加入的成员地址（已属于其他实体时移入本实体）
+}
+
+// clusterDetail 实体详情
+type clusterDetail struct {
+	Cluster *model.AddressCluster         `json:"cluster"`
+	Members []*model.AddressClusterMember `json:"members"`
+}
+
+// Clusters 地址实体（聚类）与实体监控
+// GET    /api/clusters?id=1 | id=1&stats=flows&start=...&end=...（默认最近 24 小时）| address=0x... | watched=true
+// POST   /api/clusters  {"name":"...","watched":true,"addresses":["0x..."]}（id 为空时新建，否则修改）
+// DELETE /api/clusters?address=0x...（移除成员）| id=1（删除实体）
+func Clusters(w http.ResponseWriter, r *http.Request) {
+	repo := database.NewAddressClusterRepository()
+
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+
+		// 1) 按地址查所属实体
+		if address := strings.TrimSpace(q.Get("address")); address != "" {
+			member, err := repo.GetMemberByAddress(address)
+			if err != nil {
+				JSONErr(w, http.StatusNotFound, "not found")
+				return
+			}
+			cluster, err := repo.GetByID(member.ClusterID)
+			if err != nil {
+				JSONErr(w, http.StatusNotFound, "not found")
+				return
+			}
+			JSON(w, http.StatusOK, clusterDetail{Cluster: cluster, Members: []*model.AddressClusterMember{member}})
+			return
+		}
+
+		// 2) 按 ID 查实体详情 / 转账汇总
+		if idStr := strings.TrimSpace(q.Get("id")); idStr != "" {
+			id, err := strconv.ParseUint(idStr, 10, 64)
+			if err != nil {
+				JSONErr(w, http.StatusBadRequest, "invalid id")
+				return
+			}
+			cluster, err := repo.GetByID(uint(id))
+			if err != nil {
+				JSONErr(w, http.StatusNotFound, "not found")
+				return
+			}
+
+			if strings.ToLower(strings.TrimSpace(q.Get("stats"))) == "flows" {
+				end := time.Now()
+				start := end.Add(-24 * time.Hour)
+				if s := strings.TrimSpace(q.Get("start")); s != "" {
+					t, err := time.Parse(time.RFC3339, s)
+					if err != nil {
+						JSONErr(w, http.StatusBadRequest, "invalid start, use RFC3339")
+						return
+					}
+					start = t
+				}
+				if s := strings.TrimSpace(q.Get("end")); s != "" {
+					t, err := time.Parse(time.RFC3339, s)
+					if err != nil {
+						JSONErr(w, http.StatusBadRequest, "invalid end, use RFC3339")
+						return
+					}
+					end = t
+				}
+				stats, err := database.NewTransferRecordRepository().GetClusterFlowStats(cluster.ID, start, end)
+				if err != nil {
+					JSONErr(w, http.StatusInternalServerError, err.Error())
+					return
+				}
+				JSON(w, http.StatusOK, stats)
+				return
+			}
+
+			members, err := repo.GetMembers(cluster.ID)
+			if err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			JSON(w, http.StatusOK, clusterDetail{Cluster: cluster, Members: members})
+			return
+		}
+
+		// 3) 列出实体
+		watchedOnly, _ := strconv.ParseBool(q.Get("watched"))
+		list, err := repo.GetAll(watchedOnly)
+		if err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		JSON(w, http.StatusOK, list)
+
+	case http.MethodPost:
+		var req clusterRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			JSONErr(w, http.StatusBadRequest, "invalid json body")
+			return
+		}
+		for _, address := range req.Addresses {
+			if !common.IsHexAddress(address) {
+				JSONErr(w, http.StatusBadRequest, "invalid address: "+address)
+				return
+			}
+		}
+		if req.Category != "" && !model.IsValidAddressCategory(req.Category) {
+			JSONErr(w, http.StatusBadRequest, "invalid category")
+			return
+		}
+
+		var cluster *model.AddressCluster
+		if req.ID == 0 {
+			if strings.TrimSpace(req.Name) == "" {
+				JSONErr(w, http.StatusBadRequest, "name is required")
+				return
+			}
+			cluster = &model.AddressCluster{
+				Name:     strings.TrimSpace(req.Name),
+				Category: req.Category,
+				Watched:  req.Watched != nil && *req.Watched,
+			}
+			if cluster.Category == "" {
+				cluster.Category = model.AddressCategoryOther
+			}
+			if err := repo.Create(cluster, nil); err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		} else {
+			existing, err := repo.GetByID(req.ID)
+			if err != nil {
+				JSONErr(w, http.StatusNotFound, "not found")
+				return
+			}
+			cluster = existing
+			if name := strings.TrimSpace(req.Name); name != "" {
+				cluster.Name = name
+				cluster.Auto = false // 人工命名后不再被自动合并
+			}
+			if req.Category != "" {
+				cluster.Category = req.Category
+			}
+			if req.Watched != nil {
+				cluster.Watched = *req.Watched
+			}
+			if err := repo.Update(cluster); err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+
+		for _, address := range req.Addresses {
+			member := &model.AddressClusterMember{
+				Address:   address,
+				Label:     database.GetAddressLabelRegistry().Name(address),
+				Heuristic: model.ClusterHeuristicManual,
+				Evidence:  "api",
+			}
+			if err := repo.Assign(cluster.ID, member); err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+		if err := database.GetAddressClusterRegistry().Reload(); err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		cluster, err := repo.GetByID(cluster.ID)
+		if err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		JSON(w, http.StatusOK, cluster)
+
+	case http.MethodDelete:
+		q := r.URL.Query()
+		if address := strings.TrimSpace(q.Get("address")); address != "" {
+			if !common.IsHexAddress(address) {
+				JSONErr(w, http.StatusBadRequest, "invalid address")
+				return
+			}
+			if err := repo.RemoveMember(address); err != nil {
+				JSONErr(w, http.StatusNotFound, "not found")
+				return
+			}
+		} else {
+			id, err := strconv.ParseUint(strings.TrimSpace(q.Get("id")), 10, 64)
+			if err != nil || id == 0 {
+				JSONErr(w, http.StatusBadRequest, "address or id is required")
+				return
+			}
+			if err := repo.Delete(uint(id)); err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
+		if err := database.GetAddressClusterRegistry().Reload(); err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		JSON(w, http.StatusOK, nil)
+
+	default:
+		JSONErr(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
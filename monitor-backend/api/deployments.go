@@ -0,0 +1,58 @@
+package api
+
+import (
+	"ethereum-monitor/database"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Deployments 合约部署查询：支持 address(单条)、type、deployer、start/end 时间范围（可组合）、limit
+// GET /api/deployments?address=0x... | type=ERC721&deployer=0x...&start=2025-02-10T00:00:00Z&end=... | limit=20
+func Deployments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JSONErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := parseLimit(q.Get("limit"), defaultLimit, maxLimit)
+	repo := database.NewContractDeploymentRepository()
+
+	// 1) 按合约地址查单条
+	if address := strings.TrimSpace(q.Get("address")); address != "" {
+		deployment, err := repo.GetByAddress(strings.ToLower(address))
+		if err != nil {
+			JSONErr(w, http.StatusNotFound, "not found")
+			return
+		}
+		JSON(w, http.StatusOK, deployment)
+		return
+	}
+
+	// 2) 组合过滤：类型、部署者、时间范围（均可选）
+	var start, end time.Time
+	if startStr := strings.TrimSpace(q.Get("start")); startStr != "" {
+		t, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			JSONErr(w, http.StatusBadRequest, "invalid start, use RFC3339")
+			return
+		}
+		start = t
+	}
+	if endStr := strings.TrimSpace(q.Get("end")); endStr != "" {
+		t, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			JSONErr(w, http.StatusBadRequest, "invalid end, use RFC3339")
+			return
+		}
+		end = t
+	}
+
+	list, err := repo.Search(strings.TrimSpace(q.Get("type")), strings.TrimSpace(q.Get("deployer")), start, end, limit)
+	if err != nil {
+		JSONErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, list)
+}
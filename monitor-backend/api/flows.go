@@ -0,0 +1,119 @@
+package api
+
+import (
+	"ethereum-monitor/config"
+	"ethereum-monitor/database"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Flows 交易所净流量：支持 alerts=1(告警记录，可按 entity 过滤)、entity+currency+series=1 或 start/end(小时序列，默认最近 7 天，
+// window 指定时附带滚动窗口净流量)、entity+currency(各窗口当前净流量与 z-score，window 可只取一个)，默认返回所有实体各窗口的当前净流量
+// GET /api/flows?alerts=1&entity=Binance | entity=Binance&currency=ETH&series=1&window=24h | entity=Binance&currency=ETH&window=1h
+func Flows(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JSONErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	repo := database.NewExchangeFlowRepository()
+	entity := strings.TrimSpace(q.Get("entity"))
+	currency := strings.TrimSpace(q.Get("currency"))
+
+	// 1) 告警记录
+	if q.Get("alerts") == "1" || q.Get("alerts") == "true" {
+		limit := parseLimit(q.Get("limit"), defaultLimit, maxLimit)
+		list, err := repo.GetAlerts(entity, limit)
+		if err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		JSON(w, http.StatusOK, list)
+		return
+	}
+
+	windows := config.FlowWindows
+	var window *config.FlowWindow
+	if name := strings.TrimSpace(q.Get("window")); name != "" {
+		found, ok := config.GetFlowWindow(name)
+		if !ok {
+			JSONErr(w, http.StatusBadRequest, "invalid window, use 1h, 24h or 7d")
+			return
+		}
+		window = &found
+		windows = []config.FlowWindow{found}
+	}
+
+	end := database.FlowWindowEnd(time.Now())
+
+	if entity != "" || currency != "" {
+		if entity == "" || currency == "" {
+			JSONErr(w, http.StatusBadRequest, "entity and currency are both required")
+			return
+		}
+
+		// 2) 小时序列，默认最近 7 天
+		startStr := strings.TrimSpace(q.Get("start"))
+		endStr := strings.TrimSpace(q.Get("end"))
+		if q.Get("series") == "1" || q.Get("series") == "true" || startStr != "" || endStr != "" {
+			start := end.Add(-7 * 24 * time.Hour)
+			if startStr != "" {
+				t, err := time.Parse(time.RFC3339, startStr)
+				if err != nil {
+					JSONErr(w, http.StatusBadRequest, "invalid start, use RFC3339")
+					return
+				}
+				start = t
+			}
+			if endStr != "" {
+				t, err := time.Parse(time.RFC3339, endStr)
+				if err != nil {
+					JSONErr(w, http.StatusBadRequest, "invalid end, use RFC3339")
+					return
+				}
+				end = t
+			}
+			points, err := repo.GetSeries(entity, currency, start, end, window)
+			if err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			JSON(w, http.StatusOK, points)
+			return
+		}
+
+		// 3) 单个实体、币种各窗口的当前净流量
+		stats := make([]*database.FlowWindowStat, 0, len(windows))
+		for _, fw := range windows {
+			stat, err := repo.GetWindowStat(entity, currency, fw, end)
+			if err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			stats = append(stats, stat)
+		}
+		JSON(w, http.StatusOK, stats)
+		return
+	}
+
+	// 4) 默认：最近 7 天有流水的所有实体、币种各窗口的当前净流量
+	pairs, err := repo.GetPairs(end.Add(-7 * 24 * time.Hour))
+	if err != nil {
+		JSONErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	stats := make([]*database.FlowWindowStat, 0, len(pairs)*len(windows))
+	for _, pair := range pairs {
+		for _, fw := range windows {
+			stat, err := repo.GetWindowStat(pair.Entity, pair.Currency, fw, end)
+			if err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			stats = append(stats, stat)
+		}
+	}
+	JSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"ethereum-monitor/database"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Gas 区块费用历史（base fee、滚动 base fee、有效小费分布）：支持 block(单个区块)、stats=summary(时间范围汇总，默认最近 24 小时)、
+// start/end 时间范围、limit
+// GET /api/gas?block=19000000 | stats=summary&start=2025-02-10T00:00:00Z | start=...&end=... | limit=100
+func Gas(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JSONErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := parseLimit(q.Get("limit"), defaultLimit, maxLimit)
+	repo := database.NewBlockFeeRepository()
+
+	// 1) 按区块号查单条
+	if blockStr := strings.TrimSpace(q.Get("block")); blockStr != "" {
+		number, err := strconv.ParseUint(blockStr, 10, 64)
+		if err != nil {
+			JSONErr(w, http.StatusBadRequest, "invalid block")
+			return
+		}
+		fee, err := repo.GetByBlock(number)
+		if err != nil {
+			JSONErr(w, http.StatusNotFound, "not found")
+			return
+		}
+		JSON(w, http.StatusOK, fee)
+		return
+	}
+
+	startStr := strings.TrimSpace(q.Get("start"))
+	endStr := strings.TrimSpace(q.Get("end"))
+	stats := strings.ToLower(strings.TrimSpace(q.Get("stats")))
+
+	// 2) 时间范围汇总 / 列表，汇总默认最近 24 小时
+	if stats != "" || startStr != "" || endStr != "" {
+		end := time.Now()
+		start := end.Add(-24 * time.Hour)
+		if startStr != "" {
+			t, err := time.Parse(time.RFC3339, startStr)
+			if err != nil {
+				JSONErr(w, http.StatusBadRequest, "invalid start, use RFC3339")
+				return
+			}
+			start = t
+		}
+		if endStr != "" {
+			t, err := time.Parse(time.RFC3339, endStr)
+			if err != nil {
+				JSONErr(w, http.StatusBadRequest, "invalid end, use RFC3339")
+				return
+			}
+			end = t
+		}
+
+		switch stats {
+		case "summary":
+			summary, err := repo.GetSummary(start, end)
+			if err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			JSON(w, http.StatusOK, summary)
+		case "":
+			list, err := repo.GetByTimeRange(start, end, limit)
+			if err != nil {
+				JSONErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			JSON(w, http.StatusOK, list)
+		default:
+			JSONErr(w, http.StatusBadRequest, "invalid stats, use summary")
+		}
+		return
+	}
+
+	// 3) 默认：最近 N 个区块
+	list, err := repo.GetRecent(limit)
+	if err != nil {
+		JSONErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, list)
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"ethereum-monitor/database"
+	"ethereum-monitor/model"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// labelRequest 新增 / 修改地址标签的请求体
+type labelRequest struct {
+	Address    string `json:"address"`
+	Name       string `json:"name"`
+	Category   string `json:"category"`
+	Url        string `json:"url"`
+	BotAddress string `json:"bot_address"`
+	Ens        string `json:"ens"`
+}
+
+// Labels 地址标签注册表
+// GET    /api/labels?address=0x... | category=exchange&limit=100
+// POST   /api/labels  {"address":"0x...","name":"...","category":"searcher"}（按地址新增或覆盖，来源记为 manual）
+// DELETE /api/labels?address=0x...
+func Labels(w http.ResponseWriter, r *http.Request) {
+	repo := database.NewMevBuilderRepository()
+
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+
+		// 1) 按地址查单条
+		if address := strings.TrimSpace(q.Get("address")); address != "" {
+			label, err := repo.GetByAddress(address)
+			if err != nil {
+				JSONErr(w, http.StatusNotFound, "not found")
+				return
+			}
+			JSON(w, http.StatusOK, label)
+			return
+		}
+
+		// 2) 按分类列出（为空时全部）
+		limit := parseLimit(q.Get("limit"), defaultLimit, maxLimit)
+		list, err := repo.GetByCategory(strings.TrimSpace(q.Get("category")), limit)
+		if err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		JSON(w, http.StatusOK, list)
+
+	case http.MethodPost:
+		var req labelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			JSONErr(w, http.StatusBadRequest, "invalid json body")
+			return
+		}
+		if !common.IsHexAddress(req.Address) {
+			JSONErr(w, http.StatusBadRequest, "invalid address")
+			return
+		}
+		if strings.TrimSpace(req.Name) == "" {
+			JSONErr(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		if req.Category == "" {
+			req.Category = model.AddressCategoryOther
+		}
+		if !model.IsValidAddressCategory(req.Category) {
+			JSONErr(w, http.StatusBadRequest, "invalid category")
+			return
+		}
+
+		label := &model.MevBuilder{
+			Address:    strings.ToLower(req.Address),
+			Name:       strings.TrimSpace(req.Name),
+			Category:   req.Category,
+			Url:        req.Url,
+			BotAddress: strings.ToLower(req.BotAddress),
+			Ens:        req.Ens,
+			Source:     model.LabelSourceManual,
+		}
+		if err := repo.Upsert(label); err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if err := database.GetAddressLabelRegistry().Reload(); err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		JSON(w, http.StatusOK, label)
+
+	case http.MethodDelete:
+		address := strings.TrimSpace(r.URL.Query().Get("address"))
+		if !common.IsHexAddress(address) {
+			JSONErr(w, http.StatusBadRequest, "invalid address")
+			return
+		}
+		if err := repo.DeleteByAddress(address); err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if err := database.GetAddressLabelRegistry().Reload(); err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		JSON(w, http.StatusOK, nil)
+
+	default:
+		JSONErr(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
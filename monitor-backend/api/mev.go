@@ -0,0 +1,58 @@
+package api
+
+import (
+	"ethereum-monitor/database"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Mev 区块级 MEV 事件查询：支持 tx(与交易相关的事件)、type、bot、start/end 时间范围（可组合）、limit
+// GET /api/mev?tx=0x... | type=sandwich&bot=0x...&start=2025-02-10T00:00:00Z&end=... | limit=20
+func Mev(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JSONErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := parseLimit(q.Get("limit"), defaultLimit, maxLimit)
+	repo := database.NewMevEventRepository()
+
+	// 1) 按交易哈希查询（主交易或关联交易）
+	if txHash := strings.TrimSpace(q.Get("tx")); txHash != "" {
+		list, err := repo.GetByTxHash(txHash)
+		if err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		JSON(w, http.StatusOK, list)
+		return
+	}
+
+	// 2) 组合过滤：事件类型、Bot、时间范围（均可选）
+	var start, end time.Time
+	if startStr := strings.TrimSpace(q.Get("start")); startStr != "" {
+		t, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			JSONErr(w, http.StatusBadRequest, "invalid start, use RFC3339")
+			return
+		}
+		start = t
+	}
+	if endStr := strings.TrimSpace(q.Get("end")); endStr != "" {
+		t, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			JSONErr(w, http.StatusBadRequest, "invalid end, use RFC3339")
+			return
+		}
+		end = t
+	}
+
+	list, err := repo.Search(strings.TrimSpace(q.Get("type")), strings.TrimSpace(q.Get("bot")), start, end, limit)
+	if err != nil {
+		JSONErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, list)
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"ethereum-monitor/database"
+	"ethereum-monitor/scheduler"
+	"ethereum-monitor/utils"
+	"net/http"
+)
+
+// Queues 扫描任务队列深度、延迟、数据库积压与外部依赖限速状态
+// GET /api/queues
+func Queues(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JSONErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	pendingLiquidity, safetyCheckDue, err := database.NewTokenAnalysisRepository().CountBacklog()
+	if err != nil {
+		JSONErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"queues": scheduler.QueueStats(),
+		"backlog": map[string]int64{
+			"pending_liquidity": pendingLiquidity,
+			"safety_check_due":  safetyCheckDue,
+		},
+		"rate_limiters": utils.RateLimiterStats(),
+	})
+}
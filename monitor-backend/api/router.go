@@ -5,18 +5,27 @@ import (
 	"strings"
 )
 
-// Route 注册三个聚合查询接口，并包装 CORS
+// Route 注册聚合查询接口，并包装 CORS
 func Route(mux *http.ServeMux) {
 	mux.HandleFunc("/api/transfer-records", CORS(TransferRecords))
 	mux.HandleFunc("/api/notifications", CORS(Notifications))
 	mux.HandleFunc("/api/tokens", CORS(Tokens))
+	mux.HandleFunc("/api/token-status-events", CORS(TokenStatusEvents))
+	mux.HandleFunc("/api/queues", CORS(Queues))
+	mux.HandleFunc("/api/deployments", CORS(Deployments))
+	mux.HandleFunc("/api/mev", CORS(Mev))
+	mux.HandleFunc("/api/labels", CORS(Labels))
+	mux.HandleFunc("/api/builders", CORS(Builders))
+	mux.HandleFunc("/api/gas", CORS(Gas))
+	mux.HandleFunc("/api/clusters", CORS(Clusters))
+	mux.HandleFunc("/api/flows", CORS(Flows))
 }
 
-// CORS 包装 handler，允许 GET 跨域（可选）
+// CORS 包装 handler，允许跨域（/api/labels、/api/clusters 需要 POST / DELETE）
 func CORS(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"ethereum-monitor/database"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TokenStatusEvents 代币状态流转记录：支持 address(单个代币完整历史)、stats(各状态停留时长)、since、limit
+// GET /api/token-status-events?address=0x... | stats=1&since=2025-02-10T00:00:00Z | limit=20
+func TokenStatusEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		JSONErr(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	limit := parseLimit(q.Get("limit"), defaultLimit, maxLimit)
+	repo := database.NewTokenStatusEventRepository()
+
+	// 1) 按代币地址查完整历史
+	if address := strings.TrimSpace(q.Get("address")); address != "" {
+		list, err := repo.GetByToken(address)
+		if err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		JSON(w, http.StatusOK, list)
+		return
+	}
+
+	// 2) 各状态停留时长统计，默认统计最近 7 天进入的状态
+	if q.Get("stats") == "1" || strings.ToLower(q.Get("stats")) == "true" {
+		since := time.Now().AddDate(0, 0, -7)
+		if sinceStr := strings.TrimSpace(q.Get("since")); sinceStr != "" {
+			t, err := time.Parse(time.RFC3339, sinceStr)
+			if err != nil {
+				JSONErr(w, http.StatusBadRequest, "invalid since, use RFC3339")
+				return
+			}
+			since = t
+		}
+		stats, err := repo.GetStateDurationStats(since)
+		if err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		JSON(w, http.StatusOK, stats)
+		return
+	}
+
+	// 3) 默认：最近 N 条
+	list, err := repo.GetRecent(limit)
+	if err != nil {
+		JSONErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	JSON(w, http.StatusOK, list)
+}
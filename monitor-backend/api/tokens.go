@@ -2,6 +2,7 @@ package api
 
 import (
 	"ethereum-monitor/database"
+	"ethereum-monitor/model"
 	"net/http"
 	"strconv"
 	"strings"
@@ -76,7 +77,7 @@ func Tokens(w http.ResponseWriter, r *http.Request) {
 
 	// 5) 按状态
 	if status := strings.TrimSpace(q.Get("status")); status != "" {
-		list, err := repo.GetByStatus(status, limit)
+		list, err := repo.GetByStatus(model.TokenStatus(status), limit)
 		if err != nil {
 			JSONErr(w, http.StatusInternalServerError, err.Error())
 			return
@@ -11,8 +11,8 @@ import (
 const defaultLimit = 20
 const maxLimit = 100
 
-// TransferRecords 聚合查询：支持 tx_hash、address、start/end 时间范围、limit
-// GET /api/transfer-records?tx_hash=0x... | address=0x... | start=...&end=... | limit=20
+// TransferRecords 聚合查询：支持 tx_hash、cluster_id（地址实体）、address、start/end 时间范围、limit
+// GET /api/transfer-records?tx_hash=0x... | cluster_id=1 | address=0x... | start=...&end=... | limit=20
 func TransferRecords(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		JSONErr(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -34,7 +34,23 @@ func TransferRecords(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 2) 按地址查流水
+	// 2) 按实体查流水
+	if clusterStr := strings.TrimSpace(q.Get("cluster_id")); clusterStr != "" {
+		clusterID, err := strconv.ParseUint(clusterStr, 10, 64)
+		if err != nil {
+			JSONErr(w, http.StatusBadRequest, "invalid cluster_id")
+			return
+		}
+		list, err := repo.GetByCluster(uint(clusterID), limit)
+		if err != nil {
+			JSONErr(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		JSON(w, http.StatusOK, list)
+		return
+	}
+
+	// 3) 按地址查流水
 	if address := strings.TrimSpace(q.Get("address")); address != "" {
 		list, err := repo.GetByAddress(address, limit)
 		if err != nil {
@@ -45,7 +61,7 @@ func TransferRecords(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 3) 按时间范围查
+	// 4) 按时间范围查
 	if startStr, endStr := q.Get("start"), q.Get("end"); startStr != "" && endStr != "" {
 		start, err1 := time.Parse(time.RFC3339, startStr)
 		end, err2 := time.Parse(time.RFC3339, endStr)
@@ -66,7 +82,7 @@ func TransferRecords(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 4) 默认：最近 N 条
+	// 5) 默认：最近 N 条
 	list, err := repo.GetRecent(limit)
 	if err != nil {
 		JSONErr(w, http.StatusInternalServerError, err.Error())
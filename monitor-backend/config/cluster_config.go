@@ -0,0 +1,42 @@
+package config
+
+import "time"
+
+// 地址聚类（实体识别）配置
+const (
+	// ClusterExpandInterval 同一成员地址两次运行启发式的最小间隔，链上历史变化慢，不必每轮重查
+	ClusterExpandInterval = 24 * time.Hour
+
+	// ClusterExpandBatchSize 每轮最多以多少个成员地址为起点扩展（每个地址需要若干次浏览器请求）
+	ClusterExpandBatchSize = 20
+
+	// ClusterFunderScanTxs 共同资金来源：查看资金来源地址最早的交易数，从中找出它资助过的其他地址
+	ClusterFunderScanTxs = 100
+
+	// ClusterMaxFundedChecks 共同资金来源：每个资金来源最多核对多少个被资助地址的第一笔资金
+	ClusterMaxFundedChecks = 10
+
+	// ClusterSweepScanTxs 充值地址归集：查看热钱包 / 候选充值地址最近的交易数（普通交易与代币转账各取这么多）
+	ClusterSweepScanTxs = 100
+
+	// ClusterMaxSweepChecks 充值地址归集：每个热钱包最多核对多少个转入方
+	ClusterMaxSweepChecks = 20
+
+	// ClusterSweepMinTxs 充值地址归集：候选地址至少有这么多笔转出才做判断
+	ClusterSweepMinTxs = 2
+
+	// ClusterSweepMinRatio 充值地址归集：转出中归集到同一热钱包的比例下限
+	ClusterSweepMinRatio = 0.9
+
+	// ClusterCoSpendMinBlocks 共同支出：两个地址在至少这么多个不同区块里向同一私有地址（非交易所、实体或公共地址）转账才关联
+	ClusterCoSpendMinBlocks = 3
+
+	// ClusterCoSpendLookback 共同支出：统计最近多长时间的交易流水
+	ClusterCoSpendLookback = 7 * 24 * time.Hour
+)
+
+// WalletEntities 钱包监控地址所属实体（小写地址 -> 实体名），启动时作为种子写入地址聚类
+var WalletEntities = map[string]string{
+	OkxWalletAddress:     "OKX",
+	BinanceWalletAddress: "Binance",
+}
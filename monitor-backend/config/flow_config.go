@@ -0,0 +1,42 @@
+package config
+
+import "time"
+
+// FlowWindow 交易所净流量滚动窗口
+type FlowWindow struct {
+	Name           string        // 窗口名称，如 "24h"
+	Duration       time.Duration // 窗口长度（小时桶的整数倍）
+	HistorySamples int           // 计算 z-score 的历史窗口数（当前窗口之前不重叠的同长度窗口）
+}
+
+// FlowWindows 交易所净流量统计窗口
+var FlowWindows = []FlowWindow{
+	{Name: "1h", Duration: time.Hour, HistorySamples: 168},
+	{Name: "24h", Duration: 24 * time.Hour, HistorySamples: 30},
+	{Name: "7d", Duration: 7 * 24 * time.Hour, HistorySamples: 12},
+}
+
+// 交易所净流量配置
+const (
+	// FlowBucketSize 净流量汇总桶大小，滚动窗口按桶累加
+	FlowBucketSize = time.Hour
+
+	// FlowRecomputeBuckets 每轮重新汇总最近几个桶，补上处理延迟写入的流水
+	FlowRecomputeBuckets = 3
+
+	// FlowMinHistorySamples 历史窗口少于这个数时没有基线，不计算 z-score、不告警
+	FlowMinHistorySamples = 6
+
+	// DefaultFlowZScoreThreshold 默认 z-score 告警阈值（|z| 达到即告警），可通过 FLOW_ZSCORE_THRESHOLDS 按窗口覆盖
+	DefaultFlowZScoreThreshold = 3.0
+)
+
+// GetFlowWindow 按名称查找净流量窗口
+func GetFlowWindow(name string) (FlowWindow, bool) {
+	for _, window := range FlowWindows {
+		if window.Name == name {
+			return window, true
+		}
+	}
+	return FlowWindow{}, false
+}
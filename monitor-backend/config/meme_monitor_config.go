@@ -1,7 +1,18 @@
 package config
 
+import (
+	_ "embed"
+	"time"
+)
+
 // Meme 币监控配置
 
+// DefaultRiskRulesJSON 内置风险评分规则集
+// 可通过 RISK_RULES_PATH 指定外部规则文件覆盖，运行时修改会自动重新加载
+//
+//go:embed risk_rules.json
+var DefaultRiskRulesJSON []byte
+
 // Uniswap V2 配置
 const (
 	// Uniswap V2 Factory 地址
@@ -11,15 +22,24 @@ const (
 	// event PairCreated(address indexed token0, address indexed token1, address pair, uint)
 	UniswapV2PairCreatedTopic = "0x0d3648bd0f6ba80134a33ba9275ac585d9d315f0ad8355cddefde31afa28d0e9"
 
+	// Mint(address indexed sender, uint amount0, uint amount1) 事件签名（加流动性）
+	UniswapV2MintTopic = "0x4c209b5fc8ad50758f13e2e1088ba56a560dff690a1c6fef26394f4c03821c4f"
+
+	// Swap(address indexed sender, uint amount0In, uint amount1In, uint amount0Out, uint amount1Out, address indexed to) 事件签名
+	UniswapV2SwapTopic = "0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822"
+
+	// Sync(uint112 reserve0, uint112 reserve1) 事件签名，每次 V2 Swap 后发出
+	UniswapV2SyncTopic = "0x1c411e9a96e071241c2f21f7726b17ae89e3cab4c78be50e062b03a9fffbbad1"
+
+	// Swap(address indexed sender, address indexed recipient, int256 amount0, int256 amount1, uint160 sqrtPriceX96, uint128 liquidity, int24 tick) 事件签名
+	UniswapV3SwapTopic = "0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67"
+
 	// WETH 地址（用于识别 ETH 交易对）
 	WETHAddress = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
 )
 
-// Meme 币风险评分阈值
+// Meme 币潜力币筛选阈值（低风险以规则集评出的风险等级为准）
 const (
-	// 低风险阈值（<30 分）
-	MemeRiskScoreThresholdLow = 30.0
-
 	// 初始市值阈值（USD）
 	MemeMarketCapThreshold = 100000.0
 
@@ -34,24 +54,233 @@ const (
 
 	// GoPlus Security API
 	GoPlusAPIURL = "https://api.gopluslabs.io/api/v1/token_security/1"
+
+	// SecurityCheckCacheTTL 安全检测结论的缓存时长（服务均无数据时不缓存，便于重试）
+	// 需短于 SafetyRetryBaseDelay，否则安全扫描器的重试会命中上一次的缓存结论
+	SecurityCheckCacheTTL = 1 * time.Minute
+)
+
+// 多个安全检测服务结论不一致时的裁决策略，可通过 SECURITY_CONSENSUS_POLICY 设置
+const (
+	SecurityConsensusMajority = "majority" // 加权多数，平票判定为蜜罐（默认）
+	SecurityConsensusAny      = "any"      // 任一服务判定为蜜罐即为蜜罐
+	SecurityConsensusPriority = "priority" // 按 GoPlus -> Honeypot.is -> 本地模拟 的顺序取第一个有数据的结论
+)
+
+// SecurityProviderWeights 加权多数策略中各服务的权重（key 为服务名）
+// 本地模拟真实执行了买卖，权重更高
+var SecurityProviderWeights = map[string]float64{
+	"goplus":      1.0,
+	"honeypot.is": 1.0,
+	"simulation":  1.5,
+}
+
+// 区块浏览器 API（Etherscan V2 兼容）
+const (
+	// 默认 API 地址，可通过 EXPLORER_API_URL 覆盖（例如指向本地 stub）
+	ExplorerDefaultAPIURL = "https://api.etherscan.io/v2/api"
+
+	// 以太坊主网 Chain ID
+	ExplorerChainID = "1"
+)
+
+// 本地蜜罐模拟配置
+// 通过 eth_call + state override 在最新区块上模拟 买入 -> 卖出，不依赖外部 API
+const (
+	// Uniswap V2 Router02 地址
+	UniswapV2RouterAddress = "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"
+
+	// Multicall3 地址（模拟时读取其运行时字节码，注入到模拟合约地址）
+	Multicall3Address = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+	// 模拟交易的发起地址（通过 state override 注入 ETH 余额）
+	HoneypotSimCallerAddress = "0x8f3a9c2e41b7d05e6a1c9b3f27d4e8a05c6b1d92"
+
+	// 模拟合约地址（注入 Multicall3 字节码，在一次 eth_call 内顺序执行买卖）
+	HoneypotSimContractAddress = "0x4b2e7d91a3c05f68e1d9b7a2c43f0e5d86a9b1c7"
+
+	// 卖出所得 ETH 的接收地址（普通地址，避免合约拒收 ETH）
+	HoneypotSimReceiverAddress = "0x9d1c4a7e2b5f08c3d6e9a1b4f7c2e5d8a0b3c6f9"
+
+	// 模拟买入金额（ETH）
+	HoneypotSimBuyETH = 0.05
+
+	// 模拟卖出税超过此值（百分比）即判定为蜜罐
+	HoneypotSellTaxThreshold = 50.0
+)
+
+// 持有者分析配置
+const (
+	// ERC20TransferTopic Transfer(address indexed from, address indexed to, uint256 value) 事件签名
+	ERC20TransferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+
+	// HolderScanChunkBlocks 回放 Transfer 日志时每次 eth_getLogs 查询的区块跨度
+	HolderScanChunkBlocks = 2000
+
+	// HolderScanMaxLookbackBlocks 查找加池区块时的最大回溯区块数（约 7 天）
+	HolderScanMaxLookbackBlocks = 50000
+)
+
+// BurnAddresses 销毁地址（统计持有者集中度时排除）
+var BurnAddresses = []string{
+	"0x0000000000000000000000000000000000000000",
+	"0x000000000000000000000000000000000000dead",
+}
+
+// LiquidityLockerAddresses 常见流动性 / 代币锁仓合约（统计持有者集中度时排除）
+var LiquidityLockerAddresses = map[string]string{
+	"0x663a5c229c09b049e36dcc11a9b0d4a8eb9db214": "Unicrypt V2 Locker",
+	"0xe2fe530c047f2d85298b07d9333c05737f1435fb": "Team Finance Lock",
+	"0x71b5759d73262fbb223956913ecf4ecc51057641": "PinkLock V2",
+}
+
+// 快照与回测配置
+const (
+	// RugLiquidityDropPct 池子 ETH 相对历史峰值下降超过该比例（百分比）判定为 Rug
+	RugLiquidityDropPct = 80.0
+
+	// SnapshotTrackingHours 代币加池后持续快照的时长，超过后标记为 EXPIRED
+	SnapshotTrackingHours = 168
+
+	// BacktestMoonMultiple 价格达到首个快照的该倍数记为 10x
+	BacktestMoonMultiple = 10.0
+
+	// BacktestDeadLiquidityUSD 最后一次快照流动性低于该值记为 dead
+	BacktestDeadLiquidityUSD = 1000.0
+)
+
+// 安全检测重试配置
+const (
+	// SafetyRetryBaseDelay 首次重试间隔，之后每次翻倍
+	SafetyRetryBaseDelay = 2 * time.Minute
+
+	// SafetyRetryMaxDelay 重试间隔上限
+	SafetyRetryMaxDelay = time.Hour
+
+	// SafetyRetryMaxAttempts 最大检测次数，超过后标记为 RETRY_EXHAUSTED
+	SafetyRetryMaxAttempts = 10
+
+	// SafetyRetryMaxAgeHours 代币加池超过该时长仍无安全数据，停止重试
+	SafetyRetryMaxAgeHours = 24
 )
 
-// 风险评分权重
+// 部署者信誉配置
 const (
-	RiskScoreUnverified          = 30.0 // 未验证合约
-	RiskScoreHoneypot            = 50.0 // 蜜罐
-	RiskScoreHighTax             = 20.0 // 高税率 (>10%)
-	RiskScoreConcentratedHolding = 25.0 // 持有者集中 (>50%)
-	RiskScoreNoLiquidity         = 40.0 // 无流动性
-	RiskScoreNotRenounced        = 15.0 // 未放弃所有权
+	// DeployerSurvivalHours 代币创建超过该时长仍未 Rug / 蜜罐 / 被拒绝，视为存活
+	DeployerSurvivalHours = 72
+
+	// DeployerSerialLaunchThreshold 发过的其他代币数（不含正在评估的代币）达到该值视为连续发币者
+	DeployerSerialLaunchThreshold = 5
+
+	// DeployerFundingTxLookup 追溯资金来源时每个地址查询的最早交易数
+	DeployerFundingTxLookup = 20
+
+	// DeployerFundingMaxHops 资金来源最多向上追溯的跳数
+	DeployerFundingMaxHops = 2
 )
 
-// 持有者集中度阈值
+// 部署者信誉风险分（0-100，越高越可疑）
 const (
-	Top10HoldingThreshold = 50.0 // 前10持有者占比阈值
+	DeployerRepRugged              = 30.0 // 每个已 Rug 的代币
+	DeployerRepHoneypot            = 30.0 // 每个蜜罐代币
+	DeployerRepSameCreatorHoneypot = 40.0 // GoPlus 标记同创建者发过蜜罐
+	DeployerRepMixerFunded         = 30.0 // 资金来自混币器
+	DeployerRepSerialLauncher      = 10.0 // 连续发币
+	DeployerRepSurvivedBonus       = 10.0 // 每个存活代币抵扣
 )
 
-// 税率阈值
+// 资金来源类别
 const (
-	HighTaxThreshold = 10.0 // 高税率阈值（百分比）
+	FundingCategoryMixer = "mixer"
+	FundingCategoryCEX   = "cex"
+)
+
+// 外部依赖限速（令牌桶）名称
+const (
+	RateLimiterRPC        = "rpc"
+	RateLimiterGoPlus     = "goplus"
+	RateLimiterHoneypotIs = "honeypot.is"
+	RateLimiterExplorer   = "explorer"
+)
+
+// RateLimits 各外部依赖的限速：每秒请求数与突发容量
+var RateLimits = map[string]struct {
+	PerSecond float64
+	Burst     int
+}{
+	RateLimiterRPC:        {PerSecond: 20, Burst: 40},
+	RateLimiterGoPlus:     {PerSecond: 0.5, Burst: 5}, // 免费额度约 30 次/分钟
+	RateLimiterHoneypotIs: {PerSecond: 1, Burst: 3},
+	RateLimiterExplorer:   {PerSecond: 5, Burst: 5}, // Etherscan 免费额度 5 次/秒
+}
+
+// 扫描任务并发配置（可通过环境变量覆盖，见 monitor_config.go）
+const (
+	DefaultLiquidityScanWorkers = 4
+	LiquidityScanBatchSize      = 100
+
+	DefaultSafetyScanWorkers = 3
+	SafetyScanBatchSize      = 30
+)
+
+// ENS 配置
+const (
+	// ENSRegistryAddress ENS 注册表合约地址
+	ENSRegistryAddress = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+)
+
+// SocialIgnoredDomains 从源码注释提取官网时忽略的域名（库文档、标准、浏览器等）
+var SocialIgnoredDomains = []string{
+	"github.com",
+	"github.io",
+	"openzeppelin.com",
+	"ethereum.org",
+	"soliditylang.org",
+	"readthedocs.io",
+	"etherscan.io",
+	"uniswap.org",
+	"wikipedia.org",
+	"stackexchange.com",
+	"stackoverflow.com",
+	"hardhat.org",
+	"spdx.org",
+	"opensource.org",
+	"gnu.org",
+	"xn--2-umb.com",
+}
+
+// 早期买家 / 狙击分析配置
+const (
+	// SniperWindowBlocks 加池区块起分析的区块数（含加池区块）
+	SniperWindowBlocks = 3
+
+	// SniperMaxBuyers 最多分析的早期买家数量（每个买家需查询浏览器追溯资金来源）
+	SniperMaxBuyers = 30
+)
+
+// KnownSwapRouters 常见 DEX / 聚合器路由，经由这些合约的买入不视为自建机器人合约
+var KnownSwapRouters = map[string]string{
+	"0x7a250d5630b4cf539739df2c5dacb4c659f2488d": "Uniswap V2 Router02",
+	"0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad": "Uniswap Universal Router",
+	"0xef1c6e67703c7bd7107eed8303fbe6ec2554bf6b": "Uniswap Universal Router (Old)",
+	"0x68b3465833fb72a70ecdf485e0e4c7bd8665fc45": "Uniswap SwapRouter02",
+	"0xdef1c0ded9bec7f1a1670819833240f027b25eff": "0x Exchange Proxy",
+	"0x1111111254eeb25477b68fb85ed929f73a960582": "1inch V5 Router",
+	"0x111111125421ca6dc452d289314280a0f8842a65": "1inch V6 Router",
+}
+
+// KnownSniperRouters 常见 Telegram 狙击机器人路由
+var KnownSniperRouters = map[string]string{
+	"0x3328f7f4a1d1c57c35df56bbf0c9dcafca309c49": "Banana Gun Router",
+	"0x80a64c6d7f12c47b7c66c5b4e20e72bc1fcd5d9e": "Maestro Router 2",
+	"0x126c9fbab3a2fca24edfd17322e71a5e36e91865": "Unibot Router",
+}
+
+// 合约部署监听配置
+const (
+	// PendingPairExpireHours 识别为代币的部署超过该时长仍未创建交易对，标记为 EXPIRED
+	PendingPairExpireHours = 24
+
+	// ContractClassCacheSize 按字节码哈希缓存合约类型指纹的最大条目数
+	ContractClassCacheSize = 10000
 )
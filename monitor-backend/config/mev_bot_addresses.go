@@ -1,9 +1,10 @@
 package config
 
-// KnownMevBots 已知的 MEV Bot 和 Builder 地址
+// 地址标签注册表（mev_builders 表）的初始数据，启动时写入数据库，之后可通过 /api/labels 维护
 // 数据来源：社区收集和公开数据整理
-var KnownMevBots = map[string]string{
-	// === MEV Builders (区块构建者) ===
+
+// KnownMevBuilders 已知的 MEV Builder（区块构建者）地址
+var KnownMevBuilders = map[string]string{
 	"0xdafea492d9c6733ae3d56b7ed1adb60692c98bc5": "Flashbots Builder",
 	"0xb64a30399f7f6b0c154c2e7af0a3ec7b0a5b131a": "Flashbots Builder (Old)",
 	"0xf2f5c73fa04406b1995e397b55c24ab1f3ea726c": "bloXroute Max-Profit",
@@ -13,8 +14,10 @@ var KnownMevBots = map[string]string{
 	"0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5": "beaverbuild.org",
 	"0x1f9090aae28b8a3dceadf281b0f12828e676c326": "rsync-builder.xyz",
 	"0x4838b106fce9647bdf1e7877bf73ce8b0bad5f97": "Titan Builder",
+}
 
-	// === 知名 MEV Searchers (搜索者/Bot) ===
+// KnownMevBots 已知的 MEV Searcher（搜索者 / Bot）地址
+var KnownMevBots = map[string]string{
 	// jaredfromsubway.eth - 最活跃的三明治攻击 Bot
 	"0xae2fc483527b8ef99eb5d9b44875f005ba1fae13": "jaredfromsubway.eth",
 	"0x6b75d8af000000e20b7a7ddf000ba900b4009a80": "jaredfromsubway.eth Bot",
@@ -29,25 +32,31 @@ var KnownMevBots = map[string]string{
 	"0xd2269f890854a8c5f03e8ea091e3d5a2e0e0f890": "MEV Bot",
 }
 
+// KnownExchangeAddresses 已知的中心化交易所热钱包
+var KnownExchangeAddresses = map[string]string{
+	"0x28c6c06298d514db089934071355e5743bf21d60": "Binance 14",
+	"0x21a31ee1afc51d94c2efccaa2092ad1028285549": "Binance 15",
+	"0xdfd5293d8e347dfe59e90efd55b2956a1343963d": "Binance 16",
+	"0x71660c4005ba85c37ccec55d0c4493e66fe775d3": "Coinbase 1",
+	"0x503828976d22510aad0201ac7ec88293211d23da": "Coinbase 2",
+	"0xa9d1e08c7793af67e9d92fe308d5697fb81d3e43": "Coinbase 10",
+	"0x2910543af39aba0cd09dbb2d50200b3e800a63d2": "Kraken 1",
+	"0x267be1c1d684f78cb4f6a176c4911b741e4ffdc0": "Kraken 4",
+	"0x6cc5f688a315f3dc28a7781717a9a798a59fda7b": "OKX 1",
+}
+
+// KnownMixerAddresses 已知的混币器合约
+var KnownMixerAddresses = map[string]string{
+	"0x12d66f87a04a9e220743712ce6d9bb1b5616b8fc": "Tornado Cash 0.1 ETH",
+	"0x47ce0c6ed5b0ce3d3a51fdb1c52dc66a7c3c2936": "Tornado Cash 1 ETH",
+	"0x910cbd523d972eb0a6f4cae4618ad62622b39dbf": "Tornado Cash 10 ETH",
+	"0xa160cdab225685da1d56aa342ad8841c3b53f291": "Tornado Cash 100 ETH",
+	"0xd90e2f925da726b50c4ed8d0fb90ad053324f31b": "Tornado Cash Router",
+}
+
 // MevBotAddressPatterns MEV Bot 地址的常见模式
 // 很多 MEV Bot 使用特殊的地址模式（如多个前导零）
 var MevBotAddressPatterns = []string{
 	"0x000000000000", // 12个前导零
 	"0x00000000",     // 8个前导零
 }
-
-// IsMevBot 检查地址是否为已知的 MEV Bot
-func IsMevBot(address string) (bool, string) {
-	if name, exists := KnownMevBots[address]; exists {
-		return true, name
-	}
-
-	// 检查地址模式
-	for _, pattern := range MevBotAddressPatterns {
-		if len(address) >= len(pattern) && address[:len(pattern)] == pattern {
-			return true, "Potential MEV Bot (Pattern Match)"
-		}
-	}
-
-	return false, ""
-}
@@ -0,0 +1,126 @@
+package config
+
+import "time"
+
+// 三明治检测配置
+const (
+	// SandwichBackRunTolerance 后置交易卖出数量相对前置交易买入数量的允许偏差（0.5 即 50%~150%）
+	// 带转账税的代币或攻击者使用库存时两者不完全相等
+	SandwichBackRunTolerance = 0.5
+
+	// UniswapV2FeeBps Uniswap V2 交易手续费（万分之 30），用于估算受害者损失
+	UniswapV2FeeBps = 30
+
+	// MevSandwichCacheBlocks 钱包 MEV 检测按区块哈希缓存三明治识别结果的最大区块数
+	MevSandwichCacheBlocks = 64
+)
+
+// 区块级 MEV 分析事件签名
+const (
+	// AaveLiquidationCallTopic Aave V2/V3 LiquidationCall(address indexed collateralAsset, address indexed debtAsset, address indexed user, uint256 debtToCover, uint256 liquidatedCollateralAmount, address liquidator, bool receiveAToken)
+	AaveLiquidationCallTopic = "0xe413a321e8681d831f4dbccbca790d2952b56f977908e45be37335533e005286"
+
+	// CompoundLiquidateBorrowTopic Compound V2 LiquidateBorrow(address liquidator, address borrower, uint repayAmount, address cTokenCollateral, uint seizeTokens)
+	CompoundLiquidateBorrowTopic = "0x298637f684da70674f26509b10f07ec2fbc77a335ab1e7d6215a4b2484d8bb52"
+
+	// CompoundAbsorbDebtTopic Compound V3 AbsorbDebt(address indexed absorber, address indexed borrower, uint basePaidOut, uint usdValue)
+	CompoundAbsorbDebtTopic = "0x1547a878dc89ad3c367b6338b4be6a65a5dd74fb77ae044da1e8747ef1f4f62f"
+
+	// UniswapV3MintTopic Mint(address sender, address indexed owner, int24 indexed tickLower, int24 indexed tickUpper, uint128 amount, uint256 amount0, uint256 amount1)
+	UniswapV3MintTopic = "0x7a53080ba414158be7ec69b987b5fb7d07dee101fe85488f0853ae16239d0bde"
+
+	// UniswapV3BurnTopic Burn(address indexed owner, int24 indexed tickLower, int24 indexed tickUpper, uint128 amount, uint256 amount0, uint256 amount1)
+	UniswapV3BurnTopic = "0x0c396cd989a39f4459b5fa1aed6a9a8dcdbc45908acfd67e028cd568da98982c"
+
+	// UniswapV3CollectTopic Collect(address indexed owner, address recipient, int24 indexed tickLower, int24 indexed tickUpper, uint128 amount0, uint128 amount1)
+	UniswapV3CollectTopic = "0x70935338e69775456a85ddef226c395fb668b63fa0115f5f20610b388e6ca9c0"
+)
+
+// 原子套利检测配置
+const (
+	// ArbitrageMinSwaps 同一交易内构成闭环所需的最少兑换次数
+	ArbitrageMinSwaps = 2
+)
+
+// BuilderExtraDataTag 区块 extraData 中的 Builder 标识
+type BuilderExtraDataTag struct {
+	Keyword string // 小写关键字，extraData 文本包含即匹配
+	Name    string
+}
+
+// KnownBuilderExtraData 常见 Builder 写入 extraData 的标识（按顺序匹配）
+// fee recipient 为提议者地址（Builder 不经最后一笔交易付款）时用于识别 Builder
+var KnownBuilderExtraData = []BuilderExtraDataTag{
+	{Keyword: "beaverbuild", Name: "beaverbuild.org"},
+	{Keyword: "titan", Name: "Titan Builder"},
+	{Keyword: "rsync", Name: "rsync-builder.xyz"},
+	{Keyword: "illuminate", Name: "Flashbots Builder"},
+	{Keyword: "flashbots", Name: "Flashbots Builder"},
+	{Keyword: "bloxroute", Name: "bloXroute"},
+	{Keyword: "builder0x69", Name: "builder0x69"},
+	{Keyword: "buildai", Name: "BuildAI"},
+	{Keyword: "eden", Name: "Eden Network"},
+	{Keyword: "jetbldr", Name: "Jetbuilder"},
+	{Keyword: "penguinbuild", Name: "Penguin Build"},
+}
+
+// MEV 证据合并配置
+const (
+	// DefaultMevConfidenceThreshold 综合置信度达到该值判定为 MEV（钱包监控只落库不通知），可由 MEV_CONFIDENCE_THRESHOLD 覆盖
+	DefaultMevConfidenceThreshold = 0.8
+)
+
+// MevSignalWeights 各 MEV 信号的权重（0-1），按 noisy-OR 合并：score = 1 - Π(1 - weight × likelihood)
+// 权重越低信号越弱；未列出的信号权重为 1
+var MevSignalWeights = map[string]float64{
+	"known_bot":          1.0,
+	"bot_pattern":        1.0,
+	"sandwich":           1.0,
+	"front_run":          0.8,
+	"high_gas":           0.8,
+	"failed_high_gas":    0.8,
+	"internal_transfers": 0.5, // 普通路由兑换也会产生多次 Transfer，只作辅助
+	"coinbase_tip":       0.8,
+	"private_orderflow":  0.5, // Flashbots Protect 等防夹 RPC 的普通用户也不经公开内存池，只作辅助
+}
+
+// 私有订单流检测配置
+const (
+	// MempoolIncludedTTL 交易上链后哈希继续保留的时间，需覆盖钱包监控与早期买家分析的处理延迟
+	MempoolIncludedTTL = 30 * time.Minute
+
+	// MempoolPendingMaxAge 未上链交易哈希的最长保留时间：被丢弃、或只订阅哈希时被替换的交易无法按 nonce 清理，
+	// 超过该时间才移除，防止无限增长
+	MempoolPendingMaxAge = 24 * time.Hour
+
+	// MempoolWarmup 订阅建立后的预热时间，此前已广播的交易可能没被看到，预热期内出块的交易不做判断
+	MempoolWarmup = 1 * time.Minute
+
+	// MempoolReconnectDelay 订阅断开后的重连间隔
+	MempoolReconnectDelay = 10 * time.Second
+)
+
+// USD 估值配置（三明治受害告警估算损失）
+const (
+	// ChainlinkEthUsdFeed Chainlink ETH/USD 价格源，latestAnswer 为 8 位小数
+	ChainlinkEthUsdFeed = "0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"
+)
+
+// KnownStablecoins 按 1 美元估值的稳定币（小写地址 -> 精度）
+var KnownStablecoins = map[string]int{
+	"0xdac17f958d2ee523a2206206994597c13d831ec7": 6,  // USDT
+	"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48": 6,  // USDC
+	"0x6b175474e89094c44da98b954eedeac495271d0f": 18, // DAI
+}
+
+// EIP-1559 Gas 费模型配置
+const (
+	// FeeOutlierIQRMultiplier 有效小费超过 P75 + k × IQR（Tukey 上界）判定为区块内离群值
+	FeeOutlierIQRMultiplier = 3.0
+
+	// FeeOutlierMinTipGwei 离群上界的下限（Gwei），避免小费普遍为 0 的区块把任何正小费都判为离群
+	FeeOutlierMinTipGwei = 2
+
+	// FeeRollingWindowBlocks 滚动 base fee 的区块窗口（约 10 分钟）
+	FeeRollingWindowBlocks = 50
+)
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 )
 
 const (
@@ -62,3 +64,150 @@ func GetEthereumWsUrl() string {
 	}
 	return fmt.Sprintf("wss://mainnet.infura.io/ws/v3/%s", infuraKey)
 }
+
+// GetExplorerAPIURL 获取区块浏览器 API 地址（Etherscan V2 兼容）
+func GetExplorerAPIURL() string {
+	if apiURL := os.Getenv("EXPLORER_API_URL"); apiURL != "" {
+		return apiURL
+	}
+	return ExplorerDefaultAPIURL
+}
+
+// GetExplorerAPIKey 获取区块浏览器 API Key
+func GetExplorerAPIKey() string {
+	return os.Getenv("ETHERSCAN_API_KEY")
+}
+
+// GetRiskRulesPath 获取外部风险规则文件路径（为空时使用内置规则）
+func GetRiskRulesPath() string {
+	return os.Getenv("RISK_RULES_PATH")
+}
+
+// GetLiquidityScanWorkers 获取流动性扫描并发数（LIQUIDITY_SCAN_WORKERS）
+func GetLiquidityScanWorkers() int {
+	return getEnvInt("LIQUIDITY_SCAN_WORKERS", DefaultLiquidityScanWorkers)
+}
+
+// GetSafetyScanWorkers 获取安全扫描并发数（SAFETY_SCAN_WORKERS）
+func GetSafetyScanWorkers() int {
+	return getEnvInt("SAFETY_SCAN_WORKERS", DefaultSafetyScanWorkers)
+}
+
+// GetSecurityConsensusPolicy 安全检测服务结论不一致时的裁决策略（SECURITY_CONSENSUS_POLICY），默认加权多数
+func GetSecurityConsensusPolicy() string {
+	switch policy := strings.ToLower(os.Getenv("SECURITY_CONSENSUS_POLICY")); policy {
+	case SecurityConsensusAny, SecurityConsensusPriority:
+		return policy
+	default:
+		return SecurityConsensusMajority
+	}
+}
+
+// GetDeploymentMonitorEnabled 是否启用合约部署监听（ENABLE_DEPLOYMENT_MONITOR=true）
+// 需要为合约创建交易拉取回执并追踪每个区块，RPC 开销较大，默认关闭
+func GetDeploymentMonitorEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("ENABLE_DEPLOYMENT_MONITOR"))
+	return enabled
+}
+
+// GetMevBlockAnalysisEnabled 是否启用区块级 MEV 分析（ENABLE_MEV_BLOCK_ANALYSIS=true）
+// 每个区块拉取一次全部相关日志，识别三明治、原子套利、清算与 JIT 流动性并写入 mev_events，
+// 同时识别区块 Builder 写入 block_builders，默认关闭
+func GetMevBlockAnalysisEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("ENABLE_MEV_BLOCK_ANALYSIS"))
+	return enabled
+}
+
+// GetGasTrackingEnabled 是否记录区块费用历史（ENABLE_GAS_TRACKING=true）
+// 每个区块拉取一次完整区块，统计 base fee 与有效小费分布写入 block_fees，供 /api/gas 查询，默认关闭
+func GetGasTrackingEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("ENABLE_GAS_TRACKING"))
+	return enabled
+}
+
+// GetMempoolTrackingEnabled 是否启用公开内存池监听（ENABLE_MEMPOOL_TRACKING=true）
+// 需要 WebSocket 节点订阅 newPendingTransactions，用于识别未经公开内存池打包的私有订单流，默认关闭
+func GetMempoolTrackingEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("ENABLE_MEMPOOL_TRACKING"))
+	return enabled
+}
+
+// GetAddressClusteringEnabled 是否启用地址聚类（ENABLE_ADDRESS_CLUSTERING=true）
+// 定时按共同资金来源、充值地址归集、共同支出把地址归并为实体，需要区块浏览器 API，默认关闭；
+// 关闭时已有的聚类与实体监控仍然生效
+func GetAddressClusteringEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("ENABLE_ADDRESS_CLUSTERING"))
+	return enabled
+}
+
+// GetFlowAnalyticsEnabled 是否启用交易所净流量统计（ENABLE_FLOW_ANALYTICS=true）
+// 定时把交易流水按实体、币种汇总为小时桶写入 exchange_flows，净流量偏离历史时告警，默认关闭
+func GetFlowAnalyticsEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv("ENABLE_FLOW_ANALYTICS"))
+	return enabled
+}
+
+// GetFlowZScoreThresholds 各窗口的净流量 z-score 告警阈值（FLOW_ZSCORE_THRESHOLDS）
+// 可写作单个数字（所有窗口通用）或 窗口:阈值 列表，如 "1h:4,24h:3,7d:2.5"；未配置的窗口使用默认阈值
+func GetFlowZScoreThresholds() map[string]float64 {
+	thresholds := make(map[string]float64, len(FlowWindows))
+	for _, window := range FlowWindows {
+		thresholds[window.Name] = DefaultFlowZScoreThreshold
+	}
+
+	raw := strings.TrimSpace(os.Getenv("FLOW_ZSCORE_THRESHOLDS"))
+	if value, err := strconv.ParseFloat(raw, 64); err == nil {
+		if value > 0 {
+			for name := range thresholds {
+				thresholds[name] = value
+			}
+		}
+		return thresholds
+	}
+	for _, item := range strings.Split(raw, ",") {
+		name, valueStr, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		value, err := strconv.ParseFloat(strings.TrimSpace(valueStr), 64)
+		if _, known := thresholds[name]; !known || err != nil || value <= 0 {
+			continue
+		}
+		thresholds[name] = value
+	}
+	return thresholds
+}
+
+// GetMevConfidenceThreshold MEV 综合置信度阈值（MEV_CONFIDENCE_THRESHOLD，0-1），达到阈值的转账只落库不通知
+func GetMevConfidenceThreshold() float64 {
+	threshold, err := strconv.ParseFloat(os.Getenv("MEV_CONFIDENCE_THRESHOLD"), 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		return DefaultMevConfidenceThreshold
+	}
+	return threshold
+}
+
+// GetMevWatchAddresses 需要三明治受害告警的地址（MEV_WATCH_ADDRESSES，逗号分隔，可写作 地址:标签），返回小写地址 -> 标签
+// 依赖区块级 MEV 分析（ENABLE_MEV_BLOCK_ANALYSIS=true）
+func GetMevWatchAddresses() map[string]string {
+	addresses := make(map[string]string)
+	for _, item := range strings.Split(os.Getenv("MEV_WATCH_ADDRESSES"), ",") {
+		address, label, _ := strings.Cut(strings.TrimSpace(item), ":")
+		address = strings.ToLower(strings.TrimSpace(address))
+		if address == "" {
+			continue
+		}
+		addresses[address] = strings.TrimSpace(label)
+	}
+	return addresses
+}
+
+// getEnvInt 读取正整数环境变量，未设置或无效时返回默认值
+func getEnvInt(key string, defaultValue int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n <= 0 {
+		return defaultValue
+	}
+	return n
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"ethereum-monitor/model"
+	"strings"
+	"sync"
+)
+
+// AddressClusterRegistry 地址实体注册表：address_clusters / address_cluster_members 的内存缓存，
+// 供钱包监控判断实体成员、归属与内部调拨；实体通过 API 或聚类任务修改后调用 Reload
+type AddressClusterRegistry struct {
+	mu       sync.RWMutex
+	loaded   bool
+	clusters map[uint]model.AddressCluster
+	members  map[string]uint // 小写地址 -> 实体 ID
+}
+
+var addressClusterRegistry = &AddressClusterRegistry{}
+
+// GetAddressClusterRegistry 获取全局地址实体注册表
+func GetAddressClusterRegistry() *AddressClusterRegistry {
+	return addressClusterRegistry
+}
+
+// Reload 从数据库重新加载全部实体，数据库未初始化时为空
+func (r *AddressClusterRegistry) Reload() error {
+	clusters := make(map[uint]model.AddressCluster)
+	members := make(map[string]uint)
+	if DB != nil {
+		repo := NewAddressClusterRepository()
+		list, err := repo.GetAll(false)
+		if err != nil {
+			return err
+		}
+		for _, c := range list {
+			clusters[c.ID] = *c
+		}
+		all, err := repo.GetAllMembers()
+		if err != nil {
+			return err
+		}
+		for _, m := range all {
+			members[strings.ToLower(m.Address)] = m.ClusterID
+		}
+	}
+
+	r.mu.Lock()
+	r.clusters = clusters
+	r.members = members
+	r.loaded = true
+	r.mu.Unlock()
+	return nil
+}
+
+// Lookup 查询地址所属实体
+func (r *AddressClusterRegistry) Lookup(address string) (model.AddressCluster, bool) {
+	r.mu.RLock()
+	loaded := r.loaded
+	r.mu.RUnlock()
+	if !loaded {
+		if err := r.Reload(); err != nil {
+			return model.AddressCluster{}, false
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	id, ok := r.members[strings.ToLower(address)]
+	if !ok {
+		return model.AddressCluster{}, false
+	}
+	cluster, ok := r.clusters[id]
+	return cluster, ok
+}
+
+// IsWatched 地址是否属于整体监控的实体
+func (r *AddressClusterRegistry) IsWatched(address string) bool {
+	cluster, ok := r.Lookup(address)
+	return ok && cluster.Watched
+}
+
+// SameCluster 两个地址是否属于同一实体
+func (r *AddressClusterRegistry) SameCluster(a, b string) bool {
+	ca, ok := r.Lookup(a)
+	if !ok {
+		return false
+	}
+	cb, ok := r.Lookup(b)
+	return ok && ca.ID == cb.ID
+}
+
+// WatchedMemberCount 整体监控实体的成员地址总数
+func (r *AddressClusterRegistry) WatchedMemberCount() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	count := 0
+	for _, id := range r.members {
+		if r.clusters[id].Watched {
+			count++
+		}
+	}
+	return count
+}
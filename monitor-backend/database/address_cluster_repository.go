@@ -0,0 +1,311 @@
+package database
+
+import (
+	"errors"
+	"ethereum-monitor/config"
+	"ethereum-monitor/model"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrClusterConflict 两个已命名实体之间出现关联，不自动合并，需人工确认
+var ErrClusterConflict = errors.New("address belongs to another named cluster")
+
+// AddressClusterRepository 地址实体（聚类）数据访问层
+type AddressClusterRepository struct{}
+
+// NewAddressClusterRepository 创建 Repository
+func NewAddressClusterRepository() *AddressClusterRepository {
+	return &AddressClusterRepository{}
+}
+
+// GetAll 获取全部实体，watchedOnly 为 true 时只返回整体监控的实体
+func (r *AddressClusterRepository) GetAll(watchedOnly bool) ([]*model.AddressCluster, error) {
+	query := DB.Model(&model.AddressCluster{})
+	if watchedOnly {
+		query = query.Where("watched = ?", true)
+	}
+	var list []*model.AddressCluster
+	err := query.Order("name").Find(&list).Error
+	return list, err
+}
+
+// GetByID 按 ID 查询实体
+func (r *AddressClusterRepository) GetByID(id uint) (*model.AddressCluster, error) {
+	var cluster model.AddressCluster
+	err := DB.First(&cluster, id).Error
+	return &cluster, err
+}
+
+// GetMembers 获取实体的全部成员地址
+func (r *AddressClusterRepository) GetMembers(clusterID uint) ([]*model.AddressClusterMember, error) {
+	var list []*model.AddressClusterMember
+	err := DB.Where("cluster_id = ?", clusterID).Order("created_at").Find(&list).Error
+	return list, err
+}
+
+// GetAllMembers 获取全部成员地址（注册表加载用）
+func (r *AddressClusterRepository) GetAllMembers() ([]*model.AddressClusterMember, error) {
+	var list []*model.AddressClusterMember
+	err := DB.Find(&list).Error
+	return list, err
+}
+
+// GetMemberByAddress 按地址查询成员
+func (r *AddressClusterRepository) GetMemberByAddress(address string) (*model.AddressClusterMember, error) {
+	var member model.AddressClusterMember
+	err := DB.Where("address = ?", strings.ToLower(address)).First(&member).Error
+	return &member, err
+}
+
+// GetExpandCandidates 获取待扩展的成员地址：从未扩展或上次扩展早于 staleBefore，最久未扩展的优先
+// 充值地址的资金来自交易所用户，不作为扩展起点
+func (r *AddressClusterRepository) GetExpandCandidates(staleBefore time.Time, limit int) ([]*model.AddressClusterMember, error) {
+	var list []*model.AddressClusterMember
+	err := DB.Where("heuristic <> ?", model.ClusterHeuristicDepositSweep).
+		Where("expanded_at IS NULL OR expanded_at < ?", staleBefore).
+		Order("expanded_at").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// MarkExpanded 记录成员地址的扩展时间
+func (r *AddressClusterRepository) MarkExpanded(address string, at time.Time) error {
+	return DB.Model(&model.AddressClusterMember{}).
+		Where("address = ?", strings.ToLower(address)).
+		Update("expanded_at", at).Error
+}
+
+// Create 创建实体，并写入初始成员
+func (r *AddressClusterRepository) Create(cluster *model.AddressCluster, members []*model.AddressClusterMember) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(cluster).Error; err != nil {
+			return err
+		}
+		for _, member := range members {
+			if err := assignMember(tx, cluster.ID, member); err != nil {
+				return err
+			}
+		}
+		return refreshMemberCounts(tx, cluster.ID)
+	})
+}
+
+// Update 更新实体名称、分类与监控状态
+func (r *AddressClusterRepository) Update(cluster *model.AddressCluster) error {
+	return DB.Model(cluster).Select("name", "category", "watched", "auto").Updates(cluster).Error
+}
+
+// Assign 把地址加入实体（手动维护）：地址已属于其他实体时只移动该地址
+func (r *AddressClusterRepository) Assign(clusterID uint, member *model.AddressClusterMember) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		var previous uint
+		var existing model.AddressClusterMember
+		err := tx.Where("address = ?", strings.ToLower(member.Address)).First(&existing).Error
+		if err == nil {
+			previous = existing.ClusterID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := assignMember(tx, clusterID, member); err != nil {
+			return err
+		}
+		return refreshMemberCounts(tx, clusterID, previous)
+	})
+}
+
+// Link 按启发式把地址关联到实体，返回关联后地址所在的实体 ID：
+// 地址不属于任何实体时加入；属于其他实体时两个实体合并（自动实体并入已命名实体，同为自动实体时并入较早的一个）；
+// 两个已命名实体之间返回 ErrClusterConflict
+func (r *AddressClusterRepository) Link(clusterID uint, member *model.AddressClusterMember) (uint, error) {
+	member.Address = strings.ToLower(member.Address)
+	survivor := clusterID
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		var existing model.AddressClusterMember
+		err := tx.Where("address = ?", member.Address).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			member.ClusterID = clusterID
+			if err := tx.Create(member).Error; err != nil {
+				return err
+			}
+			return refreshMemberCounts(tx, clusterID)
+		}
+		if err != nil {
+			return err
+		}
+		if existing.ClusterID == clusterID {
+			return nil
+		}
+
+		var target, other model.AddressCluster
+		if err := tx.First(&target, clusterID).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&other, existing.ClusterID).Error; err != nil {
+			return err
+		}
+		if !target.Auto && !other.Auto {
+			return ErrClusterConflict
+		}
+
+		into, from := target, other
+		if target.Auto && (!other.Auto || other.ID < target.ID) {
+			into, from = other, target
+		}
+		survivor = into.ID
+		return mergeClusters(tx, &into, &from)
+	})
+	return survivor, err
+}
+
+// RemoveMember 从实体中移除地址，自动实体移空后一并删除
+func (r *AddressClusterRepository) RemoveMember(address string) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		var member model.AddressClusterMember
+		if err := tx.Where("address = ?", strings.ToLower(address)).First(&member).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&member).Error; err != nil {
+			return err
+		}
+		if err := refreshMemberCounts(tx, member.ClusterID); err != nil {
+			return err
+		}
+		return tx.Where("id = ? AND auto = ? AND member_count = 0", member.ClusterID, true).
+			Delete(&model.AddressCluster{}).Error
+	})
+}
+
+// Delete 删除实体及其全部成员
+func (r *AddressClusterRepository) Delete(id uint) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cluster_id = ?", id).Delete(&model.AddressClusterMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.AddressCluster{}, id).Error
+	})
+}
+
+// SeedFromConfig 用配置初始化实体：已知交易所热钱包按名称首个单词归入同名实体（"Binance 14" -> Binance），
+// 钱包监控地址归入 WalletEntities 中的实体；已属于某个实体的地址保持不变
+func (r *AddressClusterRepository) SeedFromConfig() error {
+	type seed struct {
+		entity, address, label string
+	}
+	var seeds []seed
+	for address, name := range config.KnownExchangeAddresses {
+		if fields := strings.Fields(name); len(fields) > 0 {
+			seeds = append(seeds, seed{fields[0], strings.ToLower(address), name})
+		}
+	}
+	for address, entity := range config.WalletEntities {
+		seeds = append(seeds, seed{entity, strings.ToLower(address), ""})
+	}
+	sort.Slice(seeds, func(i, j int) bool { return seeds[i].address < seeds[j].address })
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		touched := make(map[uint]bool)
+		for _, s := range seeds {
+			var count int64
+			if err := tx.Model(&model.AddressClusterMember{}).Where("address = ?", s.address).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+
+			cluster := model.AddressCluster{Name: s.entity, Category: model.AddressCategoryExchange}
+			if err := tx.Where("name = ?", s.entity).FirstOrCreate(&cluster).Error; err != nil {
+				return err
+			}
+			member := &model.AddressClusterMember{
+				ClusterID: cluster.ID,
+				Address:   s.address,
+				Label:     s.label,
+				Heuristic: model.ClusterHeuristicSeed,
+				Evidence:  "config",
+			}
+			if err := tx.Create(member).Error; err != nil {
+				return err
+			}
+			touched[cluster.ID] = true
+		}
+
+		for id := range touched {
+			if err := refreshMemberCounts(tx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// NewAutoClusterName 自动实体的名称（以首个成员地址命名）
+func NewAutoClusterName(address string) string {
+	address = strings.ToLower(address)
+	if len(address) > 10 {
+		address = address[:10]
+	}
+	return fmt.Sprintf("实体-%s", address)
+}
+
+// assignMember 把地址写入实体，已存在时改为指向该实体
+func assignMember(tx *gorm.DB, clusterID uint, member *model.AddressClusterMember) error {
+	member.Address = strings.ToLower(member.Address)
+	member.ClusterID = clusterID
+
+	var existing model.AddressClusterMember
+	err := tx.Where("address = ?", member.Address).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return tx.Create(member).Error
+	}
+	if err != nil {
+		return err
+	}
+	member.ID = existing.ID
+	member.CreatedAt = existing.CreatedAt
+	member.ExpandedAt = existing.ExpandedAt
+	if member.Label == "" {
+		member.Label = existing.Label
+	}
+	return tx.Save(member).Error
+}
+
+// mergeClusters 把 from 的成员并入 into 并删除 from；任一实体整体监控时合并后仍整体监控
+func mergeClusters(tx *gorm.DB, into, from *model.AddressCluster) error {
+	if err := tx.Model(&model.AddressClusterMember{}).
+		Where("cluster_id = ?", from.ID).
+		Update("cluster_id", into.ID).Error; err != nil {
+		return err
+	}
+	if from.Watched && !into.Watched {
+		if err := tx.Model(into).Update("watched", true).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Delete(&model.AddressCluster{}, from.ID).Error; err != nil {
+		return err
+	}
+	return refreshMemberCounts(tx, into.ID)
+}
+
+// refreshMemberCounts 重新统计实体成员数（ID 为 0 时跳过）
+func refreshMemberCounts(tx *gorm.DB, clusterIDs ...uint) error {
+	for _, id := range clusterIDs {
+		if id == 0 {
+			continue
+		}
+		var count int64
+		if err := tx.Model(&model.AddressClusterMember{}).Where("cluster_id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.AddressCluster{}).Where("id = ?", id).Update("member_count", count).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
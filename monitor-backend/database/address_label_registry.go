@@ -0,0 +1,112 @@
+package database
+
+import (
+	"ethereum-monitor/config"
+	"ethereum-monitor/model"
+	"strings"
+	"sync"
+)
+
+// AddressLabelRegistry 地址标签注册表：mev_builders 表的内存缓存，供 MEV 检测、钱包监控与通知查询对手方名称
+// 数据库未初始化时（如独立工具）使用配置中的已知地址
+type AddressLabelRegistry struct {
+	mu     sync.RWMutex
+	labels map[string]model.MevBuilder
+}
+
+var addressLabelRegistry = &AddressLabelRegistry{}
+
+// GetAddressLabelRegistry 获取全局地址标签注册表
+func GetAddressLabelRegistry() *AddressLabelRegistry {
+	return addressLabelRegistry
+}
+
+// Reload 从数据库重新加载全部标签（标签通过 API 修改后调用）
+func (r *AddressLabelRegistry) Reload() error {
+	labels := make(map[string]model.MevBuilder)
+	if DB == nil {
+		for _, label := range configAddressLabels() {
+			labels[label.Address] = label
+		}
+	} else {
+		builders, err := NewMevBuilderRepository().GetAll()
+		if err != nil {
+			return err
+		}
+		for _, b := range builders {
+			labels[strings.ToLower(b.Address)] = *b
+		}
+	}
+
+	r.mu.Lock()
+	r.labels = labels
+	r.mu.Unlock()
+	return nil
+}
+
+// Lookup 查询地址标签
+func (r *AddressLabelRegistry) Lookup(address string) (model.MevBuilder, bool) {
+	r.mu.RLock()
+	loaded := r.labels != nil
+	r.mu.RUnlock()
+	if !loaded {
+		if err := r.Reload(); err != nil {
+			return model.MevBuilder{}, false
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	label, ok := r.labels[strings.ToLower(address)]
+	return label, ok
+}
+
+// Name 返回地址名称，未知时为空
+func (r *AddressLabelRegistry) Name(address string) string {
+	label, ok := r.Lookup(address)
+	if !ok {
+		return ""
+	}
+	return label.Name
+}
+
+// MevLabel 地址是已知 Builder 或搜索者时返回其标签
+func (r *AddressLabelRegistry) MevLabel(address string) (model.MevBuilder, bool) {
+	label, ok := r.Lookup(address)
+	if !ok || !model.IsMevCategory(label.Category) {
+		return model.MevBuilder{}, false
+	}
+	return label, true
+}
+
+// MevLabelName 地址是已知 Builder 或搜索者时返回其名称
+func (r *AddressLabelRegistry) MevLabelName(address string) (string, bool) {
+	label, ok := r.MevLabel(address)
+	return label.Name, ok
+}
+
+// BuilderName 地址是已知 Builder 时返回其名称
+func (r *AddressLabelRegistry) BuilderName(address string) (string, bool) {
+	label, ok := r.Lookup(address)
+	if !ok || label.Category != model.AddressCategoryBuilder {
+		return "", false
+	}
+	return label.Name, true
+}
+
+// IsMevBot 检查地址是否为已知的 MEV Bot / Builder，或符合 MEV Bot 地址模式
+func (r *AddressLabelRegistry) IsMevBot(address string) (bool, string) {
+	address = strings.ToLower(address)
+	if label, ok := r.MevLabel(address); ok {
+		return true, label.Name
+	}
+
+	// 检查地址模式
+	for _, pattern := range config.MevBotAddressPatterns {
+		if strings.HasPrefix(address, pattern) {
+			return true, "Potential MEV Bot (Pattern Match)"
+		}
+	}
+
+	return false, ""
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"ethereum-monitor/model"
+	"time"
+)
+
+// BlockBuilderRepository 区块 Builder 归属数据访问层
+type BlockBuilderRepository struct{}
+
+// NewBlockBuilderRepository 创建 Repository
+func NewBlockBuilderRepository() *BlockBuilderRepository {
+	return &BlockBuilderRepository{}
+}
+
+// BuilderDailyShare 某天某个 Builder 的出块份额
+type BuilderDailyShare struct {
+	Date        string  `json:"date"`
+	BuilderName string  `json:"builder_name"` // 空表示无法识别
+	Blocks      int     `json:"blocks"`
+	Share       float64 `json:"share"` // 占当天已归属区块的比例
+}
+
+// BuilderMevShare 某个 Builder 的 MEV 区块占比
+type BuilderMevShare struct {
+	BuilderName string  `json:"builder_name"`
+	Blocks      int     `json:"blocks"`
+	MevBlocks   int     `json:"mev_blocks"` // 含 MEV 事件的区块数
+	MevEvents   int     `json:"mev_events"` // MEV 事件总数
+	MevRatio    float64 `json:"mev_ratio"`  // 该 Builder 区块中含 MEV 的比例
+	MevShare    float64 `json:"mev_share"`  // 占全部 MEV 区块的比例
+}
+
+// GetByBlock 查询区块的 Builder 归属
+func (r *BlockBuilderRepository) GetByBlock(blockNumber uint64) (*model.BlockBuilder, error) {
+	var builder model.BlockBuilder
+	err := DB.Where("block_number = ?", blockNumber).First(&builder).Error
+	return &builder, err
+}
+
+// GetRecent 获取最近的区块归属
+func (r *BlockBuilderRepository) GetRecent(limit int) ([]model.BlockBuilder, error) {
+	var builders []model.BlockBuilder
+	err := DB.Order("block_number DESC").Limit(limit).Find(&builders).Error
+	return builders, err
+}
+
+// GetDailyShare 按天统计各 Builder 的出块份额
+func (r *BlockBuilderRepository) GetDailyShare(start, end time.Time) ([]BuilderDailyShare, error) {
+	var rows []BuilderDailyShare
+	err := DB.Model(&model.BlockBuilder{}).
+		Select("block_date AS date, builder_name, COUNT(*) AS blocks").
+		Where("block_time >= ? AND block_time <= ?", start, end).
+		Group("block_date, builder_name").
+		Order("block_date DESC, blocks DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int)
+	for _, row := range rows {
+		totals[row.Date] += row.Blocks
+	}
+	for i := range rows {
+		if total := totals[rows[i].Date]; total > 0 {
+			rows[i].Share = float64(rows[i].Blocks) / float64(total)
+		}
+	}
+	return rows, nil
+}
+
+// GetMevShare 统计各 Builder 区块中含 MEV 的比例及其占全部 MEV 区块的份额
+func (r *BlockBuilderRepository) GetMevShare(start, end time.Time) ([]BuilderMevShare, error) {
+	var rows []BuilderMevShare
+	err := DB.Model(&model.BlockBuilder{}).
+		Select(`builder_name, COUNT(*) AS blocks,
+			SUM(CASE WHEN mev_event_count > 0 THEN 1 ELSE 0 END) AS mev_blocks,
+			SUM(mev_event_count) AS mev_events`).
+		Where("block_time >= ? AND block_time <= ?", start, end).
+		Group("builder_name").
+		Order("mev_blocks DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totalMevBlocks := 0
+	for _, row := range rows {
+		totalMevBlocks += row.MevBlocks
+	}
+	for i := range rows {
+		if rows[i].Blocks > 0 {
+			rows[i].MevRatio = float64(rows[i].MevBlocks) / float64(rows[i].Blocks)
+		}
+		if totalMevBlocks > 0 {
+			rows[i].MevShare = float64(rows[i].MevBlocks) / float64(totalMevBlocks)
+		}
+	}
+	return rows, nil
+}
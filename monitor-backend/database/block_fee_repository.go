@@ -0,0 +1,80 @@
+package database
+
+import (
+	"ethereum-monitor/model"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// BlockFeeRepository 区块费用历史数据访问层
+type BlockFeeRepository struct{}
+
+// NewBlockFeeRepository 创建 Repository
+func NewBlockFeeRepository() *BlockFeeRepository {
+	return &BlockFeeRepository{}
+}
+
+// GasSummary 时间范围内的 base fee 与小费汇总（Gwei）
+type GasSummary struct {
+	Blocks         int     `json:"blocks"`
+	AvgBaseFeeGwei float64 `json:"avg_base_fee_gwei"`
+	MinBaseFeeGwei float64 `json:"min_base_fee_gwei"`
+	MaxBaseFeeGwei float64 `json:"max_base_fee_gwei"`
+	AvgTipP50Gwei  float64 `json:"avg_tip_p50_gwei"`
+	AvgTipP90Gwei  float64 `json:"avg_tip_p90_gwei"`
+	OutlierTxs     int     `json:"outlier_txs"` // 小费离群的交易总数
+}
+
+// Save 保存区块费用，同一区块（重组后重新出块）覆盖旧记录
+func (r *BlockFeeRepository) Save(fee *model.BlockFee) error {
+	return DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "block_number"}},
+		UpdateAll: true,
+	}).Create(fee).Error
+}
+
+// DeleteByBlock 删除区块费用（区块被重组移除时）
+func (r *BlockFeeRepository) DeleteByBlock(blockNumber uint64) error {
+	return DB.Where("block_number = ?", blockNumber).Delete(&model.BlockFee{}).Error
+}
+
+// GetByBlock 查询区块费用
+func (r *BlockFeeRepository) GetByBlock(blockNumber uint64) (*model.BlockFee, error) {
+	var fee model.BlockFee
+	err := DB.Where("block_number = ?", blockNumber).First(&fee).Error
+	return &fee, err
+}
+
+// GetRecent 获取最近的区块费用
+func (r *BlockFeeRepository) GetRecent(limit int) ([]model.BlockFee, error) {
+	var fees []model.BlockFee
+	err := DB.Order("block_number DESC").Limit(limit).Find(&fees).Error
+	return fees, err
+}
+
+// GetByTimeRange 查询时间范围内的区块费用
+func (r *BlockFeeRepository) GetByTimeRange(start, end time.Time, limit int) ([]model.BlockFee, error) {
+	var fees []model.BlockFee
+	err := DB.Where("block_time >= ? AND block_time <= ?", start, end).
+		Order("block_number DESC").
+		Limit(limit).
+		Find(&fees).Error
+	return fees, err
+}
+
+// GetSummary 汇总时间范围内的 base fee 与小费
+func (r *BlockFeeRepository) GetSummary(start, end time.Time) (*GasSummary, error) {
+	var summary GasSummary
+	err := DB.Model(&model.BlockFee{}).
+		Select(`COUNT(*) AS blocks,
+			COALESCE(AVG(base_fee_gwei), 0) AS avg_base_fee_gwei,
+			COALESCE(MIN(base_fee_gwei), 0) AS min_base_fee_gwei,
+			COALESCE(MAX(base_fee_gwei), 0) AS max_base_fee_gwei,
+			COALESCE(AVG(tip_p50_gwei), 0) AS avg_tip_p50_gwei,
+			COALESCE(AVG(tip_p90_gwei), 0) AS avg_tip_p90_gwei,
+			COALESCE(SUM(outlier_count), 0) AS outlier_txs`).
+		Where("block_time >= ? AND block_time <= ?", start, end).
+		Scan(&summary).Error
+	return &summary, err
+}
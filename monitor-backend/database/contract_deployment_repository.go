@@ -2,6 +2,7 @@ package database
 
 import (
 	"ethereum-monitor/model"
+	"strings"
 	"time"
 )
 
@@ -62,3 +63,24 @@ func (r *ContractDeploymentRepository) CountDailyDeployments(date time.Time) (in
 		Count(&count).Error
 	return count, err
 }
+
+// Search 按合约类型、部署者与时间范围组合查询，空值/零值表示不限
+func (r *ContractDeploymentRepository) Search(contractType, deployer string, start, end time.Time, limit int) ([]model.ContractDeployment, error) {
+	query := DB.Model(&model.ContractDeployment{})
+	if contractType != "" {
+		query = query.Where("contract_type = ?", contractType)
+	}
+	if deployer != "" {
+		query = query.Where("deployer_address = ?", strings.ToLower(deployer))
+	}
+	if !start.IsZero() {
+		query = query.Where("timestamp >= ?", start)
+	}
+	if !end.IsZero() {
+		query = query.Where("timestamp <= ?", end)
+	}
+
+	var deployments []model.ContractDeployment
+	err := query.Order("block_number DESC").Limit(limit).Find(&deployments).Error
+	return deployments, err
+}
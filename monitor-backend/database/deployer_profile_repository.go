@@ -0,0 +1,92 @@
+package database
+
+import (
+	"ethereum-monitor/model"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// DeployerProfileRepository 部署者画像数据访问层
+type DeployerProfileRepository struct{}
+
+// NewDeployerProfileRepository 创建 Repository
+func NewDeployerProfileRepository() *DeployerProfileRepository {
+	return &DeployerProfileRepository{}
+}
+
+// DeployerOutcomeStats 部署者发币结局统计
+type DeployerOutcomeStats struct {
+	TokenCount    int
+	RuggedCount   int
+	HoneypotCount int
+	SurvivedCount int
+}
+
+// GetByAddress 根据部署者地址查询
+func (r *DeployerProfileRepository) GetByAddress(address string) (*model.DeployerProfile, error) {
+	var profile model.DeployerProfile
+	err := DB.Where("deployer_address = ?", address).First(&profile).Error
+	return &profile, err
+}
+
+// Save 保存部署者画像（不存在则创建）
+// 同一部署者的多个代币可能同时分析，按 deployer_address upsert，避免并发插入触发唯一索引冲突
+func (r *DeployerProfileRepository) Save(profile *model.DeployerProfile) error {
+	return DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "deployer_address"}},
+		UpdateAll: true,
+	}).Create(profile).Error
+}
+
+// GetOutcomeStats 统计部署者发过的代币及其结局
+// 发币总数合并 contract_deployments 与 token_analyses（未建池的代币只出现在前者）
+// survivedBefore 之前创建且未 Rug、非蜜罐、未被拒绝的代币视为存活
+// excludeToken 为正在评估的代币，不计入统计，避免部署者信誉依赖它自己的判定结果
+func (r *DeployerProfileRepository) GetOutcomeStats(deployer, excludeToken string, survivedBefore time.Time) (*DeployerOutcomeStats, error) {
+	stats := &DeployerOutcomeStats{}
+	excludeToken = strings.ToLower(excludeToken)
+
+	var tokenCount int64
+	err := DB.Raw(`SELECT COUNT(*) FROM (
+		SELECT LOWER(token_address) AS addr FROM token_analyses WHERE deployer_address = ?
+		UNION
+		SELECT LOWER(contract_address) AS addr FROM contract_deployments WHERE deployer_address = ? AND is_token = ?
+	) WHERE addr <> ?`, deployer, deployer, true, excludeToken).Scan(&tokenCount).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.TokenCount = int(tokenCount)
+
+	var outcome struct {
+		Rugged   int
+		Honeypot int
+		Survived int
+	}
+	err = DB.Model(&model.TokenAnalysis{}).
+		Select(`SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS rugged,
+			SUM(CASE WHEN is_honeypot = ? THEN 1 ELSE 0 END) AS honeypot,
+			SUM(CASE WHEN status NOT IN ? AND is_honeypot = ? AND created_at < ? THEN 1 ELSE 0 END) AS survived`,
+			model.TokenStatusRugged, true,
+			[]model.TokenStatus{model.TokenStatusRugged, model.TokenStatusRejected, model.TokenStatusExpired}, false, survivedBefore).
+		Where("deployer_address = ? AND LOWER(token_address) <> ?", deployer, excludeToken).
+		Scan(&outcome).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.RuggedCount = outcome.Rugged
+	stats.HoneypotCount = outcome.Honeypot
+	stats.SurvivedCount = outcome.Survived
+
+	return stats, nil
+}
+
+// HasSameCreatorHoneypot 部署者名下是否有代币被 GoPlus 标记为同创建者蜜罐
+func (r *DeployerProfileRepository) HasSameCreatorHoneypot(deployer string) (bool, error) {
+	var count int64
+	err := DB.Model(&model.TokenAnalysis{}).
+		Where("deployer_address = ? AND creator_honeypot_flag = ?", deployer, true).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -50,6 +50,6 @@ func ExampleUsage() {
 		Address: "0x1234567890123456789012345678901234567890",
 		Ens:     "example.eth",
 	}
-	fmt.Printf("可以使用 repo.create(newBuilder) 添加新数据\n")
+	fmt.Printf("可以使用 repo.Create(newBuilder) 添加新数据\n")
 	fmt.Printf("示例: %s (%s)\n", newBuilder.Name, newBuilder.Address)
 }
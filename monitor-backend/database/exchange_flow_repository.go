@@ -0,0 +1,241 @@
+package database
+
+import (
+	"errors"
+	"ethereum-monitor/config"
+	"ethereum-monitor/model"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExchangeFlowRepository 交易所净流量数据访问层
+type ExchangeFlowRepository struct{}
+
+// NewExchangeFlowRepository 创建 Repository
+func NewExchangeFlowRepository() *ExchangeFlowRepository {
+	return &ExchangeFlowRepository{}
+}
+
+// FlowPair 有净流量记录的实体与币种
+type FlowPair struct {
+	Entity   string `json:"entity"`
+	Currency string `json:"currency"`
+}
+
+// FlowWindowStat 实体某币种在一个滚动窗口内的净流量，及其相对历史同长度窗口的 z-score
+type FlowWindowStat struct {
+	Entity       string    `json:"entity"`
+	Currency     string    `json:"currency"`
+	Window       string    `json:"window"`
+	WindowStart  time.Time `json:"window_start"`
+	WindowEnd    time.Time `json:"window_end"`
+	Inflow       float64   `json:"inflow"`
+	Outflow      float64   `json:"outflow"`
+	NetFlow      float64   `json:"net_flow"`
+	InflowCount  int       `json:"inflow_count"`
+	OutflowCount int       `json:"outflow_count"`
+	Samples      int       `json:"samples"` // 有数据的历史窗口数
+	Mean         float64   `json:"mean"`
+	StdDev       float64   `json:"std_dev"`
+	ZScore       float64   `json:"z_score"`
+	HasBaseline  bool      `json:"has_baseline"` // 历史窗口足够且有波动，z-score 有效
+}
+
+// FlowSeriesPoint 小时净流量序列中的一个点
+type FlowSeriesPoint struct {
+	BucketStart    time.Time `json:"bucket_start"`
+	Inflow         float64   `json:"inflow"`
+	Outflow        float64   `json:"outflow"`
+	NetFlow        float64   `json:"net_flow"`
+	RollingNetFlow float64   `json:"rolling_net_flow"` // 截至该桶的滚动窗口净流量（请求指定窗口时）
+}
+
+// SaveBuckets 保存小时桶，同一实体、币种、时间的桶覆盖旧值（重新汇总）
+func (r *ExchangeFlowRepository) SaveBuckets(flows []*model.ExchangeFlow) error {
+	if len(flows) == 0 {
+		return nil
+	}
+	return DB.Transaction(func(tx *gorm.DB) error {
+		for _, flow := range flows {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "entity"}, {Name: "currency"}, {Name: "bucket_start"}},
+				DoUpdates: clause.AssignmentColumns([]string{"inflow", "outflow", "net_flow", "inflow_count", "outflow_count", "updated_at"}),
+			}).Create(flow).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetLatestBucketStart 最新小时桶的起点，没有记录时为零值
+func (r *ExchangeFlowRepository) GetLatestBucketStart() (time.Time, error) {
+	var flow model.ExchangeFlow
+	err := DB.Order("bucket_start DESC").First(&flow).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	return flow.BucketStart, err
+}
+
+// GetPairs 获取 since 之后有净流量记录的实体与币种
+func (r *ExchangeFlowRepository) GetPairs(since time.Time) ([]FlowPair, error) {
+	var pairs []FlowPair
+	err := DB.Model(&model.ExchangeFlow{}).
+		Select("DISTINCT entity, currency").
+		Where("bucket_start >= ?", since.UTC()).
+		Order("entity, currency").
+		Scan(&pairs).Error
+	return pairs, err
+}
+
+// GetBuckets 获取 [start, end) 内的小时桶（按时间升序）
+func (r *ExchangeFlowRepository) GetBuckets(entity, currency string, start, end time.Time) ([]*model.ExchangeFlow, error) {
+	var list []*model.ExchangeFlow
+	err := DB.Where("entity = ? AND currency = ?", entity, currency).
+		Where("bucket_start >= ? AND bucket_start < ?", start.UTC(), end.UTC()).
+		Order("bucket_start").Find(&list).Error
+	return list, err
+}
+
+// GetWindowStat 计算截至 end（桶边界）的滚动窗口净流量与 z-score
+// 历史为当前窗口之前 HistorySamples 个不重叠的同长度窗口，只统计首个小时桶之后的完整窗口
+func (r *ExchangeFlowRepository) GetWindowStat(entity, currency string, window config.FlowWindow, end time.Time) (*FlowWindowStat, error) {
+	end = end.UTC()
+	stat := &FlowWindowStat{
+		Entity:      entity,
+		Currency:    currency,
+		Window:      window.Name,
+		WindowStart: end.Add(-window.Duration),
+		WindowEnd:   end,
+	}
+
+	var first model.ExchangeFlow
+	err := DB.Where("entity = ? AND currency = ?", entity, currency).Order("bucket_start").First(&first).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return stat, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	start := end.Add(-window.Duration * time.Duration(window.HistorySamples+1))
+	buckets, err := r.GetBuckets(entity, currency, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// 下标 0 为当前窗口，i 为往前第 i 个历史窗口
+	history := make([]float64, window.HistorySamples+1)
+	for _, b := range buckets {
+		index := int((end.Sub(b.BucketStart.UTC()) - 1) / window.Duration)
+		if index < 0 || index > window.HistorySamples {
+			continue
+		}
+		history[index] += b.NetFlow
+		if index == 0 {
+			stat.Inflow += b.Inflow
+			stat.Outflow += b.Outflow
+			stat.InflowCount += b.InflowCount
+			stat.OutflowCount += b.OutflowCount
+		}
+	}
+	stat.NetFlow = history[0]
+
+	var samples []float64
+	for i := 1; i <= window.HistorySamples; i++ {
+		if end.Add(-window.Duration * time.Duration(i+1)).Before(first.BucketStart.UTC()) {
+			break
+		}
+		samples = append(samples, history[i])
+	}
+	stat.Samples = len(samples)
+	if stat.Samples == 0 {
+		return stat, nil
+	}
+
+	for _, v := range samples {
+		stat.Mean += v
+	}
+	stat.Mean /= float64(stat.Samples)
+	for _, v := range samples {
+		stat.StdDev += (v - stat.Mean) * (v - stat.Mean)
+	}
+	stat.StdDev = math.Sqrt(stat.StdDev / float64(stat.Samples))
+
+	if stat.Samples >= config.FlowMinHistorySamples && stat.StdDev > 0 {
+		stat.HasBaseline = true
+		stat.ZScore = (stat.NetFlow - stat.Mean) / stat.StdDev
+	}
+	return stat, nil
+}
+
+// GetSeries 获取 [start, end) 内的小时净流量序列，window 不为 nil 时附带截至每个桶的滚动窗口净流量
+func (r *ExchangeFlowRepository) GetSeries(entity, currency string, start, end time.Time, window *config.FlowWindow) ([]FlowSeriesPoint, error) {
+	from := start
+	if window != nil {
+		from = start.Add(-window.Duration)
+	}
+	buckets, err := r.GetBuckets(entity, currency, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]FlowSeriesPoint, 0, len(buckets))
+	rolling, tail := 0.0, 0
+	for _, b := range buckets {
+		if window != nil {
+			rolling += b.NetFlow
+			// 滚动窗口为 (t - Duration, t] 内的桶
+			for !buckets[tail].BucketStart.After(b.BucketStart.Add(-window.Duration)) {
+				rolling -= buckets[tail].NetFlow
+				tail++
+			}
+		}
+		if b.BucketStart.Before(start.UTC()) {
+			continue
+		}
+		points = append(points, FlowSeriesPoint{
+			BucketStart:    b.BucketStart,
+			Inflow:         b.Inflow,
+			Outflow:        b.Outflow,
+			NetFlow:        b.NetFlow,
+			RollingNetFlow: rolling,
+		})
+	}
+	return points, nil
+}
+
+// CreateAlert 保存净流量告警
+func (r *ExchangeFlowRepository) CreateAlert(alert *model.ExchangeFlowAlert) error {
+	return DB.Create(alert).Error
+}
+
+// HasRecentAlert 实体、币种、窗口在 since 之后是否已告警
+func (r *ExchangeFlowRepository) HasRecentAlert(entity, currency, window string, since time.Time) bool {
+	var count int64
+	DB.Model(&model.ExchangeFlowAlert{}).
+		Where("entity = ? AND currency = ? AND window_name = ? AND created_at >= ?", entity, currency, window, since).
+		Count(&count)
+	return count > 0
+}
+
+// GetAlerts 获取最近的净流量告警，entity 为空时不限
+func (r *ExchangeFlowRepository) GetAlerts(entity string, limit int) ([]*model.ExchangeFlowAlert, error) {
+	query := DB.Model(&model.ExchangeFlowAlert{})
+	if entity != "" {
+		query = query.Where("entity = ?", entity)
+	}
+	var list []*model.ExchangeFlowAlert
+	err := query.Order("created_at DESC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// FlowWindowEnd 滚动窗口的结束时间：当前小时桶的结束边界（当前小时已发生的流水计入窗口）
+func FlowWindowEnd(now time.Time) time.Time {
+	return now.UTC().Truncate(config.FlowBucketSize).Add(config.FlowBucketSize)
+}
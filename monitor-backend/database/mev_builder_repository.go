@@ -1,7 +1,11 @@
 package database
 
 import (
+	"errors"
+	"ethereum-monitor/config"
 	"ethereum-monitor/model"
+	"strings"
+
 	"gorm.io/gorm"
 )
 
@@ -15,15 +19,15 @@ func NewMevBuilderRepository() *MevBuilderRepository {
 	}
 }
 
-func (r *MevBuilderRepository) create(builder *model.MevBuilder) error {
+func (r *MevBuilderRepository) Create(builder *model.MevBuilder) error {
 	return r.db.Create(builder).Error
 }
 
-func (r *MevBuilderRepository) batchCreate(builders []model.MevBuilder) error {
+func (r *MevBuilderRepository) BatchCreate(builders []model.MevBuilder) error {
 	return r.db.CreateInBatches(builders, len(builders)).Error
 }
 
-func (r *MevBuilderRepository) update(builder *model.MevBuilder) error {
+func (r *MevBuilderRepository) Update(builder *model.MevBuilder) error {
 	return r.db.Save(builder).Error
 }
 
@@ -35,7 +39,7 @@ func (r *MevBuilderRepository) GetById(id uint) (*model.MevBuilder, error) {
 
 func (r *MevBuilderRepository) GetByAddress(address string) (*model.MevBuilder, error) {
 	var builder model.MevBuilder
-	err := r.db.Where("address = ?", address).First(&builder).Error
+	err := r.db.Where("address = ?", strings.ToLower(address)).First(&builder).Error
 	return &builder, err
 }
 
@@ -45,13 +49,113 @@ func (r *MevBuilderRepository) GetAll() ([]*model.MevBuilder, error) {
 	return builders, err
 }
 
-func (r *MevBuilderRepository) count() (int64, error) {
+// GetByCategory 按分类查询，category 为空时不限
+func (r *MevBuilderRepository) GetByCategory(category string, limit int) ([]*model.MevBuilder, error) {
+	query := r.db.Model(&model.MevBuilder{})
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	var builders []*model.MevBuilder
+	err := query.Order("category, name").Limit(limit).Find(&builders).Error
+	return builders, err
+}
+
+func (r *MevBuilderRepository) Count() (int64, error) {
 	var count int64
 	err := r.db.Model(&model.MevBuilder{}).Count(&count).Error
 	return count, err
 }
 
-func (r *MevBuilderRepository) deleteById(id uint) error {
+func (r *MevBuilderRepository) DeleteById(id uint) error {
 	err := r.db.Delete(&model.MevBuilder{}, id).Error
 	return err
 }
+
+// DeleteByAddress 删除地址标签
+func (r *MevBuilderRepository) DeleteByAddress(address string) error {
+	return r.db.Where("address = ?", strings.ToLower(address)).Delete(&model.MevBuilder{}).Error
+}
+
+// Upsert 按地址新增或覆盖标签
+func (r *MevBuilderRepository) Upsert(builder *model.MevBuilder) error {
+	builder.Address = strings.ToLower(builder.Address)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.MevBuilder
+		err := tx.Where("address = ?", builder.Address).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(builder).Error
+		}
+		if err != nil {
+			return err
+		}
+		builder.ID = existing.ID
+		builder.CreatedAt = existing.CreatedAt
+		return tx.Save(builder).Error
+	})
+}
+
+// SeedFromConfig 用配置中的已知地址初始化标签：新地址直接写入，来源为配置的已有记录随配置更新，
+// 通过 API 维护的记录保持不变
+func (r *MevBuilderRepository) SeedFromConfig() error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, label := range configAddressLabels() {
+			var existing model.MevBuilder
+			err := tx.Where("address = ?", label.Address).First(&existing).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if err := tx.Create(&label).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if existing.Source != model.LabelSourceConfig ||
+				(existing.Name == label.Name && existing.Category == label.Category) {
+				continue
+			}
+			if err := tx.Model(&existing).Updates(map[string]interface{}{
+				"name":     label.Name,
+				"category": label.Category,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// configAddressLabels 配置中的已知地址（同一地址出现在多个列表时以先出现的分类为准）
+func configAddressLabels() []model.MevBuilder {
+	sources := []struct {
+		category string
+		labels   map[string]string
+	}{
+		{model.AddressCategoryBuilder, config.KnownMevBuilders},
+		{model.AddressCategorySearcher, config.KnownMevBots},
+		{model.AddressCategoryExchange, config.KnownExchangeAddresses},
+		{model.AddressCategoryMixer, config.KnownMixerAddresses},
+		{model.AddressCategoryLocker, config.LiquidityLockerAddresses},
+		{model.AddressCategorySniperRouter, config.KnownSniperRouters},
+		{model.AddressCategoryRouter, config.KnownSwapRouters},
+	}
+
+	seen := make(map[string]bool)
+	var labels []model.MevBuilder
+	for _, source := range sources {
+		for address, name := range source.labels {
+			address = strings.ToLower(address)
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+			labels = append(labels, model.MevBuilder{
+				Name:     name,
+				Address:  address,
+				Category: source.category,
+				Source:   model.LabelSourceConfig,
+			})
+		}
+	}
+	return labels
+}
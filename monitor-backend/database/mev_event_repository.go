@@ -0,0 +1,82 @@
+package database
+
+import (
+	"ethereum-monitor/model"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MevEventRepository MEV 事件数据访问层
+type MevEventRepository struct{}
+
+// NewMevEventRepository 创建 Repository
+func NewMevEventRepository() *MevEventRepository {
+	return &MevEventRepository{}
+}
+
+// ReplaceBlock 用新的分析结果替换区块已有的 Builder 归属与事件（重复分析同一区块时保持幂等）
+func (r *MevEventRepository) ReplaceBlock(blockNumber uint64, builder *model.BlockBuilder, events []model.MevEvent) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := deleteBlockAnalysis(tx, blockNumber); err != nil {
+			return err
+		}
+		if builder != nil {
+			if err := tx.Create(builder).Error; err != nil {
+				return err
+			}
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(events, 100).Error
+	})
+}
+
+// DeleteByBlock 删除区块的 Builder 归属与事件（区块被重组移除时调用）
+func (r *MevEventRepository) DeleteByBlock(blockNumber uint64) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		return deleteBlockAnalysis(tx, blockNumber)
+	})
+}
+
+// deleteBlockAnalysis 删除区块的分析结果
+func deleteBlockAnalysis(tx *gorm.DB, blockNumber uint64) error {
+	if err := tx.Where("block_number = ?", blockNumber).Delete(&model.MevEvent{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("block_number = ?", blockNumber).Delete(&model.BlockBuilder{}).Error
+}
+
+// GetByTxHash 查询与交易相关的事件（主交易或关联交易）
+func (r *MevEventRepository) GetByTxHash(txHash string) ([]model.MevEvent, error) {
+	txHash = strings.ToLower(txHash)
+	var events []model.MevEvent
+	err := DB.Where("tx_hash = ? OR related_tx_hashes LIKE ?", txHash, "%"+txHash+"%").
+		Order("block_number DESC").
+		Find(&events).Error
+	return events, err
+}
+
+// Search 按事件类型、Bot（发起者或合约）与时间范围组合查询，空值/零值表示不限
+func (r *MevEventRepository) Search(eventType, bot string, start, end time.Time, limit int) ([]model.MevEvent, error) {
+	query := DB.Model(&model.MevEvent{})
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	if bot != "" {
+		bot = strings.ToLower(bot)
+		query = query.Where("bot_address = ? OR bot_contract = ?", bot, bot)
+	}
+	if !start.IsZero() {
+		query = query.Where("block_time >= ?", start)
+	}
+	if !end.IsZero() {
+		query = query.Where("block_time <= ?", end)
+	}
+
+	var events []model.MevEvent
+	err := query.Order("block_number DESC").Limit(limit).Find(&events).Error
+	return events, err
+}
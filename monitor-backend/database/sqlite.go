@@ -31,17 +31,52 @@ func NewSqlite(path string) error {
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
 	DB = db
-	return autoMigrate()
+	if err := autoMigrate(); err != nil {
+		return err
+	}
+
+	// 用配置中的已知地址初始化地址标签注册表
+	if err := NewMevBuilderRepository().SeedFromConfig(); err != nil {
+		return fmt.Errorf("failed to seed address labels: %w", err)
+	}
+	if err := GetAddressLabelRegistry().Reload(); err != nil {
+		return err
+	}
+
+	// 已知交易所热钱包与钱包监控地址作为地址实体的种子
+	if err := NewAddressClusterRepository().SeedFromConfig(); err != nil {
+		return fmt.Errorf("failed to seed address clusters: %w", err)
+	}
+	return GetAddressClusterRegistry().Reload()
 }
 
 // autoMigrate 自动创建表
 func autoMigrate() error {
+	// wechat_alters 的唯一键由 tx_hash 改为 (type, tx_hash)：同一交易可能同时有转账通知与 MEV 告警
+	if DB.Migrator().HasIndex(&model.WechatAlter{}, "idx_wechat_alters_tx_hash") {
+		if err := DB.Migrator().DropIndex(&model.WechatAlter{}, "idx_wechat_alters_tx_hash"); err != nil {
+			return fmt.Errorf("failed to drop legacy wechat_alters index: %w", err)
+		}
+	}
+
 	return DB.AutoMigrate(
 		&model.MevBuilder{},
 		&model.WechatAlter{},
 		&model.ContractDeployment{},
 		&model.TokenAnalysis{},
 		&model.TransferRecord{},
+		&model.TokenHolder{},
+		&model.DeployerProfile{},
+		&model.TokenSnapshot{},
+		&model.TokenStatusEvent{},
+		&model.TokenSecurityReport{},
+		&model.MevEvent{},
+		&model.BlockBuilder{},
+		&model.BlockFee{},
+		&model.AddressCluster{},
+		&model.AddressClusterMember{},
+		&model.ExchangeFlow{},
+		&model.ExchangeFlowAlert{},
 	)
 }
 
@@ -1,8 +1,13 @@
 package database
 
 import (
+	"errors"
 	"ethereum-monitor/model"
+	"fmt"
 	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TokenAnalysisRepository 代币分析数据访问层
@@ -13,14 +18,98 @@ func NewTokenAnalysisRepository() *TokenAnalysisRepository {
 	return &TokenAnalysisRepository{}
 }
 
-// Create 创建代币分析记录
+// ErrTokenExists 代币已有分析记录（部署监听与交易对监听可能同时写入同一代币）
+var ErrTokenExists = errors.New("token analysis already exists")
+
+// Create 创建代币分析记录，有初始状态时同时记录状态事件；记录已存在时返回 ErrTokenExists
 func (r *TokenAnalysisRepository) Create(analysis *model.TokenAnalysis) error {
-	return DB.Create(analysis).Error
+	return DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "token_address"}},
+			DoNothing: true,
+		}).Create(analysis)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("token %s: %w", analysis.TokenAddress, ErrTokenExists)
+		}
+		if analysis.Status == "" {
+			return nil
+		}
+		return tx.Create(&model.TokenStatusEvent{
+			TokenAddress: analysis.TokenAddress,
+			Kind:         model.StatusEventKindStatus,
+			ToState:      string(analysis.Status),
+			Reason:       "created",
+		}).Error
+	})
+}
+
+// ErrTokenStatusConflict 数据库中的状态已被其他任务修改，本次流转基于过期数据
+var ErrTokenStatusConflict = errors.New("token status changed concurrently")
+
+// Transition 校验并执行状态流转，在同一事务内写入状态列与状态事件
+// 只有数据库中的状态仍与 analysis 读到的一致时才写入（快照、安全、流动性扫描并发运行，可能已改为 RUGGED / REJECTED 等），
+// 否则返回 ErrTokenStatusConflict。只写状态列，其他字段的修改由调用方在流转成功后用 Update 保存
+func (r *TokenAnalysisRepository) Transition(analysis *model.TokenAnalysis, status model.TokenStatus, safetyStatus model.SafetyStatus, reason string) error {
+	if safetyStatus == "" {
+		safetyStatus = analysis.SafetyStatus
+	}
+	if err := model.ValidateTransition(analysis.Status, status, analysis.SafetyStatus, safetyStatus); err != nil {
+		return fmt.Errorf("token %s: %w", analysis.TokenAddress, err)
+	}
+
+	var events []model.TokenStatusEvent
+	if analysis.Status != status {
+		events = append(events, model.TokenStatusEvent{
+			TokenAddress: analysis.TokenAddress,
+			Kind:         model.StatusEventKindStatus,
+			FromState:    string(analysis.Status),
+			ToState:      string(status),
+			Reason:       reason,
+		})
+	}
+	if analysis.SafetyStatus != safetyStatus {
+		events = append(events, model.TokenStatusEvent{
+			TokenAddress: analysis.TokenAddress,
+			Kind:         model.StatusEventKindSafetyStatus,
+			FromState:    string(analysis.SafetyStatus),
+			ToState:      string(safetyStatus),
+			Reason:       reason,
+		})
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.TokenAnalysis{}).
+			Where("token_address = ? AND status = ? AND safety_status = ?", analysis.TokenAddress, analysis.Status, analysis.SafetyStatus).
+			Updates(map[string]interface{}{
+				"status":        status,
+				"safety_status": safetyStatus,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("token %s (%s/%s): %w", analysis.TokenAddress, analysis.Status, analysis.SafetyStatus, ErrTokenStatusConflict)
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		return tx.Create(&events).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	analysis.Status = status
+	analysis.SafetyStatus = safetyStatus
+	return nil
 }
 
-// Update 更新代币分析记录
+// Update 更新代币分析记录（状态列除外，状态只能经 Transition 修改）
 func (r *TokenAnalysisRepository) Update(analysis *model.TokenAnalysis) error {
-	return DB.Save(analysis).Error
+	return DB.Omit("status", "safety_status").Save(analysis).Error
 }
 
 // GetByAddress 根据代币地址查询
@@ -50,7 +139,7 @@ func (r *TokenAnalysisRepository) GetRecentAnalyses(limit int) ([]model.TokenAna
 }
 
 // GetByStatus 根据状态查询
-func (r *TokenAnalysisRepository) GetByStatus(status string, limit int) ([]model.TokenAnalysis, error) {
+func (r *TokenAnalysisRepository) GetByStatus(status model.TokenStatus, limit int) ([]model.TokenAnalysis, error) {
 	var tokens []model.TokenAnalysis
 	err := DB.Where("status = ?", status).
 		Order("pair_created_at DESC").
@@ -61,17 +150,24 @@ func (r *TokenAnalysisRepository) GetByStatus(status string, limit int) ([]model
 
 // GetPendingLiquidityTokens 获取待扫描流动性的代币
 func (r *TokenAnalysisRepository) GetPendingLiquidityTokens(limit int) ([]model.TokenAnalysis, error) {
-	// 查找状态为 PENDING_LIQUIDITY 且创建时间在 2 小时以内的
-	// 如果超过 2 小时还没加池，可能是死币，暂不优先扫描（后续由过期任务清理）
-	twoHoursAgo := time.Now().Add(-2 * time.Hour)
-
 	var tokens []model.TokenAnalysis
-	err := DB.Where("status = ? AND pair_created_at > ?", "PENDING_LIQUIDITY", twoHoursAgo).
+	err := DB.Scopes(pendingLiquidityScope).
 		Order("pair_created_at ASC"). // 按时间正序，优先处理最早的
 		Limit(limit).
 		Find(&tokens).Error
 	return tokens, err
 }
+
+// GetStalePendingPairTokens 获取部署时间早于 before 仍未建池的代币
+func (r *TokenAnalysisRepository) GetStalePendingPairTokens(before time.Time, limit int) ([]model.TokenAnalysis, error) {
+	var tokens []model.TokenAnalysis
+	err := DB.Where("status = ? AND created_at < ?", model.TokenStatusPendingPair, before).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&tokens).Error
+	return tokens, err
+}
+
 func (r *TokenAnalysisRepository) GetByRiskLevel(riskLevel string, limit int) ([]model.TokenAnalysis, error) {
 	var tokens []model.TokenAnalysis
 	err := DB.Where("risk_level = ?", riskLevel).
@@ -121,10 +217,77 @@ func (r *TokenAnalysisRepository) GetDailyStats(date time.Time) (map[string]inte
 // GetTokensForSafetyCheck 获取待安全检测的代币
 func (r *TokenAnalysisRepository) GetTokensForSafetyCheck(limit int) ([]model.TokenAnalysis, error) {
 	var tokens []model.TokenAnalysis
-	// 查找 ANALYZING 状态的任务，或者 MONITORING 状态但需要重试的任务
-	err := DB.Where("status = ? OR (status = ? AND safety_status = ?)", "ANALYZING", "MONITORING", "RETRY_NEEDED").
-		Order("pair_created_at DESC").
+	err := DB.Scopes(safetyCheckDueScope).
+		Order("next_safety_check_at ASC, pair_created_at DESC").
 		Limit(limit).
 		Find(&tokens).Error
 	return tokens, err
 }
+
+// GetTrackedTokens 获取需要定期快照的代币（监控中且已有交易对）
+func (r *TokenAnalysisRepository) GetTrackedTokens(limit int) ([]model.TokenAnalysis, error) {
+	var tokens []model.TokenAnalysis
+	err := DB.Where("status IN ? AND pair_address <> ''", []model.TokenStatus{model.TokenStatusMonitoring, model.TokenStatusPotential}).
+		Order("last_check_at ASC").
+		Limit(limit).
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// FindInBatches 分批遍历全部代币分析记录
+func (r *TokenAnalysisRepository) FindInBatches(batchSize int, fn func(batch []model.TokenAnalysis) error) error {
+	var batch []model.TokenAnalysis
+	return DB.Order("id ASC").FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// SaveSnapshotResult 只更新快照任务负责的流动性与检查时间（安全扫描器同时在写其余字段）
+func (r *TokenAnalysisRepository) SaveSnapshotResult(analysis *model.TokenAnalysis) error {
+	return DB.Model(analysis).Updates(map[string]interface{}{
+		"liquidity_usd": analysis.LiquidityUSD,
+		"last_check_at": analysis.LastCheckAt,
+	}).Error
+}
+
+// SaveRiskScores 在一个事务内批量更新风险评分字段（其余字段保持不变）
+func (r *TokenAnalysisRepository) SaveRiskScores(analyses []model.TokenAnalysis) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		for _, a := range analyses {
+			err := tx.Model(&model.TokenAnalysis{}).
+				Where("id = ?", a.ID).
+				Updates(map[string]interface{}{
+					"risk_score":       a.RiskScore,
+					"risk_level":       a.RiskLevel,
+					"risk_flags":       a.RiskFlags,
+					"rule_set_version": a.RuleSetVersion,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CountBacklog 统计扫描任务积压：待加池检查与已到期待安全检测的代币数
+func (r *TokenAnalysisRepository) CountBacklog() (pendingLiquidity int64, safetyCheckDue int64, err error) {
+	if err = DB.Model(&model.TokenAnalysis{}).Scopes(pendingLiquidityScope).Count(&pendingLiquidity).Error; err != nil {
+		return
+	}
+	err = DB.Model(&model.TokenAnalysis{}).Scopes(safetyCheckDueScope).Count(&safetyCheckDue).Error
+	return
+}
+
+// pendingLiquidityScope 状态为 PENDING_LIQUIDITY 且创建时间在 2 小时以内的代币
+// 如果超过 2 小时还没加池，可能是死币，暂不优先扫描（后续由过期任务清理）
+func pendingLiquidityScope(db *gorm.DB) *gorm.DB {
+	twoHoursAgo := time.Now().Add(-2 * time.Hour)
+	return db.Where("status = ? AND pair_created_at > ?", model.TokenStatusPendingLiquidity, twoHoursAgo)
+}
+
+// safetyCheckDueScope ANALYZING 状态的代币，或者 MONITORING 状态但需要重试的代币，且已到下次检测时间（旧记录为 NULL）
+func safetyCheckDueScope(db *gorm.DB) *gorm.DB {
+	return db.Where("(status = ? OR (status = ? AND safety_status = ?)) AND (next_safety_check_at IS NULL OR next_safety_check_at <= ?)",
+		model.TokenStatusAnalyzing, model.TokenStatusMonitoring, model.SafetyStatusRetryNeeded, time.Now())
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"ethereum-monitor/model"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TokenHolderRepository 代币持有者数据访问层
+type TokenHolderRepository struct{}
+
+// NewTokenHolderRepository 创建 Repository
+func NewTokenHolderRepository() *TokenHolderRepository {
+	return &TokenHolderRepository{}
+}
+
+// GetByToken 查询代币的全部持有者余额
+func (r *TokenHolderRepository) GetByToken(tokenAddress string) ([]model.TokenHolder, error) {
+	var holders []model.TokenHolder
+	err := DB.Where("token_address = ?", strings.ToLower(tokenAddress)).Find(&holders).Error
+	return holders, err
+}
+
+// DeleteByToken 删除代币的全部持有者余额（从创建区块重新回放前清理）
+func (r *TokenHolderRepository) DeleteByToken(tokenAddress string) error {
+	return DB.Where("token_address = ?", strings.ToLower(tokenAddress)).Delete(&model.TokenHolder{}).Error
+}
+
+// SaveProgress 保存一批余额变动并推进扫描游标（同一事务内完成）
+// 余额为 0 的持有者会被删除，避免表无限膨胀
+func (r *TokenHolderRepository) SaveProgress(tokenAddress string, holders []model.TokenHolder, scannedBlock uint64) error {
+	token := strings.ToLower(tokenAddress)
+
+	return DB.Transaction(func(tx *gorm.DB) error {
+		var upserts []model.TokenHolder
+		var zeroHolders []string
+		for _, h := range holders {
+			h.TokenAddress = token
+			h.HolderAddress = strings.ToLower(h.HolderAddress)
+			if h.Balance == "0" {
+				zeroHolders = append(zeroHolders, h.HolderAddress)
+				continue
+			}
+			upserts = append(upserts, h)
+		}
+
+		if len(zeroHolders) > 0 {
+			if err := tx.Where("token_address = ? AND holder_address IN ?", token, zeroHolders).
+				Delete(&model.TokenHolder{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(upserts) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "token_address"}, {Name: "holder_address"}},
+				DoUpdates: clause.AssignmentColumns([]string{"balance", "updated_at"}),
+			}).CreateInBatches(upserts, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&model.TokenAnalysis{}).
+			Where("token_address = ?", token).
+			Update("holder_scanned_block", scannedBlock).Error
+	})
+}
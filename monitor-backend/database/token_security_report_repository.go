@@ -0,0 +1,26 @@
+package database
+
+import "ethereum-monitor/model"
+
+// TokenSecurityReportRepository 安全检测报告数据访问层
+type TokenSecurityReportRepository struct{}
+
+// NewTokenSecurityReportRepository 创建 Repository
+func NewTokenSecurityReportRepository() *TokenSecurityReportRepository {
+	return &TokenSecurityReportRepository{}
+}
+
+// Create 创建检测报告
+func (r *TokenSecurityReportRepository) Create(report *model.TokenSecurityReport) error {
+	return DB.Create(report).Error
+}
+
+// GetByToken 获取代币最近的检测报告
+func (r *TokenSecurityReportRepository) GetByToken(tokenAddress string, limit int) ([]model.TokenSecurityReport, error) {
+	var reports []model.TokenSecurityReport
+	err := DB.Where("token_address = ?", tokenAddress).
+		Order("id DESC").
+		Limit(limit).
+		Find(&reports).Error
+	return reports, err
+}
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"ethereum-monitor/model"
+)
+
+// TokenSnapshotRepository 代币快照数据访问层
+type TokenSnapshotRepository struct{}
+
+// NewTokenSnapshotRepository 创建 Repository
+func NewTokenSnapshotRepository() *TokenSnapshotRepository {
+	return &TokenSnapshotRepository{}
+}
+
+// Create 创建快照
+func (r *TokenSnapshotRepository) Create(snapshot *model.TokenSnapshot) error {
+	return DB.Create(snapshot).Error
+}
+
+// GetByToken 按时间正序获取代币的全部快照
+func (r *TokenSnapshotRepository) GetByToken(tokenAddress string) ([]model.TokenSnapshot, error) {
+	var snapshots []model.TokenSnapshot
+	err := DB.Where("token_address = ?", tokenAddress).
+		Order("snapshot_at ASC").
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+// HasSnapshot 代币是否已有快照
+func (r *TokenSnapshotRepository) HasSnapshot(tokenAddress string) (bool, error) {
+	var count int64
+	err := DB.Model(&model.TokenSnapshot{}).Where("token_address = ?", tokenAddress).Count(&count).Error
+	return count > 0, err
+}
+
+// GetPeakLiquidityETH 获取代币历史最高的池子 ETH 数量
+func (r *TokenSnapshotRepository) GetPeakLiquidityETH(tokenAddress string) (float64, error) {
+	var peak float64
+	err := DB.Model(&model.TokenSnapshot{}).
+		Select("COALESCE(MAX(liquidity_eth), 0)").
+		Where("token_address = ?", tokenAddress).
+		Scan(&peak).Error
+	return peak, err
+}
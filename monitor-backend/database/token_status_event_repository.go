@@ -0,0 +1,93 @@
+package database
+
+import (
+	"ethereum-monitor/model"
+	"sort"
+	"time"
+)
+
+// TokenStatusEventRepository 代币状态事件数据访问层
+// 事件只在 TokenAnalysisRepository.Create / Transition 的事务中写入，这里只提供查询
+type TokenStatusEventRepository struct{}
+
+// NewTokenStatusEventRepository 创建 Repository
+func NewTokenStatusEventRepository() *TokenStatusEventRepository {
+	return &TokenStatusEventRepository{}
+}
+
+// StateDurationStat 各状态停留时长统计
+type StateDurationStat struct {
+	State      string  `json:"state"`
+	Count      int     `json:"count"`       // 进入该状态的次数
+	Current    int     `json:"current"`     // 当前仍处于该状态的代币数
+	AvgSeconds float64 `json:"avg_seconds"` // 平均停留时长（只统计已离开该状态的，终态不统计）
+	MaxSeconds float64 `json:"max_seconds"`
+}
+
+// GetByToken 按时间正序获取代币的全部状态事件
+func (r *TokenStatusEventRepository) GetByToken(tokenAddress string) ([]model.TokenStatusEvent, error) {
+	var events []model.TokenStatusEvent
+	err := DB.Where("token_address = ?", tokenAddress).
+		Order("id ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// GetRecent 获取最近的状态事件
+func (r *TokenStatusEventRepository) GetRecent(limit int) ([]model.TokenStatusEvent, error) {
+	var events []model.TokenStatusEvent
+	err := DB.Order("id DESC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// GetStateDurationStats 统计 since 之后进入各状态的代币的停留时长
+func (r *TokenStatusEventRepository) GetStateDurationStats(since time.Time) ([]StateDurationStat, error) {
+	var events []model.TokenStatusEvent
+	err := DB.Where("kind = ? AND created_at >= ?", model.StatusEventKindStatus, since).
+		Order("token_address ASC, id ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	statsByState := make(map[string]*StateDurationStat)
+	totals := make(map[string]float64)
+	completed := make(map[string]int)
+	for i, event := range events {
+		stat, ok := statsByState[event.ToState]
+		if !ok {
+			stat = &StateDurationStat{State: event.ToState}
+			statsByState[event.ToState] = stat
+		}
+		stat.Count++
+
+		// 停留到同一代币的下一个事件为止；没有下一个事件的仍在该状态，只计入 Current
+		if i+1 >= len(events) || events[i+1].TokenAddress != event.TokenAddress {
+			stat.Current++
+			continue
+		}
+		// 终态不会离开，停留时长没有意义
+		if model.TokenStatus(event.ToState).IsTerminal() {
+			continue
+		}
+
+		seconds := events[i+1].CreatedAt.Sub(event.CreatedAt).Seconds()
+		totals[event.ToState] += seconds
+		completed[event.ToState]++
+		if seconds > stat.MaxSeconds {
+			stat.MaxSeconds = seconds
+		}
+	}
+
+	stats := make([]StateDurationStat, 0, len(statsByState))
+	for state, stat := range statsByState {
+		if completed[state] > 0 {
+			stat.AvgSeconds = totals[state] / float64(completed[state])
+		}
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].State < stats[j].State })
+	return stats, nil
+}
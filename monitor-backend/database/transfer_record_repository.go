@@ -60,3 +60,63 @@ func (r *TransferRecordRepository) GetByDateRange(start, end time.Time) ([]*mode
 		Order("created_at DESC").Find(&list).Error
 	return list, err
 }
+
+// GetSinceBlockTime 查询出块时间（未能获取时按入库时间）不早于 since 的流水
+func (r *TransferRecordRepository) GetSinceBlockTime(since time.Time) ([]*model.TransferRecord, error) {
+	var list []*model.TransferRecord
+	err := r.db.Where("block_time >= ? OR created_at >= ?", since, since).
+		Order("block_number").Find(&list).Error
+	return list, err
+}
+
+// GetByCluster 按实体查询流水
+func (r *TransferRecordRepository) GetByCluster(clusterID uint, limit int) ([]*model.TransferRecord, error) {
+	var list []*model.TransferRecord
+	err := r.db.Where("cluster_id = ?", clusterID).
+		Order("created_at DESC").Limit(limit).Find(&list).Error
+	return list, err
+}
+
+// ClusterFlowStat 实体按币种、方向汇总的转账（不含实体内部调拨）
+type ClusterFlowStat struct {
+	Currency    string  `json:"currency"`
+	Direction   string  `json:"direction"`
+	Count       int     `json:"count"`
+	TotalAmount float64 `json:"total_amount"`
+}
+
+// GetClusterFlowStats 汇总实体在时间范围内的转入 / 转出
+func (r *TransferRecordRepository) GetClusterFlowStats(clusterID uint, start, end time.Time) ([]ClusterFlowStat, error) {
+	var stats []ClusterFlowStat
+	err := r.db.Model(&model.TransferRecord{}).
+		Select("currency, direction, COUNT(*) AS count, COALESCE(SUM(CAST(amount AS REAL)), 0) AS total_amount").
+		Where("cluster_id = ? AND intra_cluster = ?", clusterID, false).
+		Where("created_at BETWEEN ? AND ?", start, end).
+		Group("currency, direction").
+		Order("currency, direction").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// CoSpendPair 在同一区块向同一地址转账的一对发送方
+type CoSpendPair struct {
+	AddressA  string `json:"address_a"`
+	AddressB  string `json:"address_b"`
+	Recipient string `json:"recipient"` // 共同的收款地址
+	Blocks    int    `json:"blocks"`    // 共同出现的不同区块数
+}
+
+// GetCoSpendPairs 统计 since 之后在至少 minBlocks 个不同区块里向同一地址转账的发送方对（按收款地址分别统计）
+// 收款地址是否可作为关联依据（交易所热钱包等公共地址不可）由调用方判断
+func (r *TransferRecordRepository) GetCoSpendPairs(since time.Time, minBlocks int) ([]CoSpendPair, error) {
+	var pairs []CoSpendPair
+	err := r.db.Table("transfer_records AS a").
+		Select("a.from_address AS address_a, b.from_address AS address_b, a.to_address AS recipient, COUNT(DISTINCT a.block_number) AS blocks").
+		Joins("JOIN transfer_records AS b ON a.block_number = b.block_number AND a.to_address = b.to_address AND a.from_address < b.from_address").
+		Where("a.created_at >= ? AND b.created_at >= ?", since, since).
+		Group("a.from_address, b.from_address, a.to_address").
+		Having("COUNT(DISTINCT a.block_number) >= ?", minBlocks).
+		Order("blocks DESC").
+		Scan(&pairs).Error
+	return pairs, err
+}
@@ -41,6 +41,13 @@ func (r *WechatAlterRepository) ExistsByTxHash(txHash string) bool {
 	return count > 0
 }
 
+// ExistsByTypeAndTxHash 检查交易是否已有该类型的通知记录
+func (r *WechatAlterRepository) ExistsByTypeAndTxHash(notifType, txHash string) bool {
+	var count int64
+	r.db.Model(&model.WechatAlter{}).Where("type = ? AND tx_hash = ?", notifType, txHash).Count(&count)
+	return count > 0
+}
+
 // GetRecent 获取最近的通知记录
 func (r *WechatAlterRepository) GetRecent(limit int) ([]*model.WechatAlter, error) {
 	var alters []*model.WechatAlter
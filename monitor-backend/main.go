@@ -2,16 +2,22 @@ package main
 
 import (
 	"context"
+	"ethereum-monitor/analyzer"
 	"ethereum-monitor/api"
+	"ethereum-monitor/config"
 	"ethereum-monitor/database"
 	"ethereum-monitor/logger"
 	"ethereum-monitor/monitor"
 	"ethereum-monitor/scheduler"
 	"ethereum-monitor/utils"
 	"ethereum-monitor/wallet"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	"go.uber.org/zap"
 
@@ -49,6 +55,14 @@ func main() {
 		}
 	}
 
+	// ==================== 子命令（执行完即退出） ====================
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			logger.Log.Error("命令执行失败", zap.String("command", os.Args[1]), zap.Error(err))
+		}
+		return
+	}
+
 	// ==================== 启动 API 服务 ====================
 	apiPort := os.Getenv("API_PORT")
 	if apiPort == "" {
@@ -70,6 +84,35 @@ func main() {
 	scheduler.Start()
 	defer scheduler.Stop()
 
+	// 公开内存池监听：钱包转账与早期买入的私有订单流检测依赖它，需在各监控之前启动
+	if config.GetMempoolTrackingEnabled() {
+		if wsURL := config.GetEthereumWsUrl(); wsURL != "" {
+			utils.StartMempoolTracker(context.Background(), wsURL)
+		} else {
+			logger.Log.Warn("未配置 WebSocket 节点，内存池监听未启动")
+		}
+	}
+
+	// 地址聚类：定时把与已知实体关联的地址归并进来，实体整体监控时新成员自动纳入钱包监控
+	if config.GetAddressClusteringEnabled() {
+		clusterer := analyzer.NewAddressClusterer(config.GetExplorerAPIURL(), config.GetExplorerAPIKey())
+		if err := scheduler.RegisterTask("@every 1h", clusterer.Run); err != nil {
+			logger.Log.Error("注册地址聚类任务失败", zap.Error(err))
+		} else {
+			logger.Log.Info("✅ 地址聚类任务已启动 (每 1h)")
+		}
+	}
+
+	// 交易所净流量：把钱包流水汇总为小时桶，滚动窗口净流量偏离历史时告警
+	if config.GetFlowAnalyticsEnabled() {
+		flowAnalyzer := analyzer.NewExchangeFlowAnalyzer()
+		if err := scheduler.RegisterTask("@every 5m", flowAnalyzer.Run); err != nil {
+			logger.Log.Error("注册交易所净流量任务失败", zap.Error(err))
+		} else {
+			logger.Log.Info("✅ 交易所净流量统计已启动 (每 5m)")
+		}
+	}
+
 	// 方式 1: 启动地址监控（币安 + OKX）
 	// go startAddressMonitor() // Run in background
 
@@ -92,3 +135,136 @@ func main() {
 	// StartMemeMonitor 本身是阻塞的，所以不需要 select {}，除非 StartMemeMonitor 出错返回
 
 }
+
+// runCommand 执行子命令
+func runCommand(name string, args []string) error {
+	switch name {
+	case "rescore":
+		return runRescore(args)
+	case "backtest":
+		return runBacktest(args)
+	case "mev":
+		return runMevScan(args)
+	default:
+		return fmt.Errorf("unknown command %q (available: rescore, backtest, mev)", name)
+	}
+}
+
+// runRescore 用指定规则集重新评估已保存的代币
+// 用法: go run main.go rescore [-rules path/to/rules.json] [-dry-run]
+func runRescore(args []string) error {
+	fs := flag.NewFlagSet("rescore", flag.ContinueOnError)
+	rulesPath := fs.String("rules", config.GetRiskRulesPath(), "风险规则文件路径（为空时使用内置规则）")
+	dryRun := fs.Bool("dry-run", false, "只统计变化，不写回数据库")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ruleSet := analyzer.ActiveRiskRuleSet()
+	if *rulesPath != "" {
+		loaded, err := analyzer.LoadRiskRuleSetFile(*rulesPath)
+		if err != nil {
+			return err
+		}
+		ruleSet = loaded
+	}
+
+	summary, err := analyzer.RescoreTokens(ruleSet, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("规则集版本: %s\n", summary.Version)
+	fmt.Printf("重新评分: %d, 等级变化: %d (dry-run: %v)\n", summary.Total, summary.Changed, *dryRun)
+	for change, count := range summary.LevelChanges {
+		fmt.Printf("  %s: %d\n", change, count)
+	}
+	return nil
+}
+
+// runBacktest 用历史代币与快照回测筛选策略
+// 用法: go run main.go backtest [-rules a.json,b.json] [-thresholds 20,30,40]
+func runBacktest(args []string) error {
+	fs := flag.NewFlagSet("backtest", flag.ContinueOnError)
+	rulesArg := fs.String("rules", "", "逗号分隔的风险规则文件（为空时使用当前规则集）")
+	thresholdsArg := fs.String("thresholds", "20,30,40", "逗号分隔的风险分阈值")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ruleSets := []*analyzer.RiskRuleSet{analyzer.ActiveRiskRuleSet()}
+	if *rulesArg != "" {
+		ruleSets = nil
+		for _, path := range strings.Split(*rulesArg, ",") {
+			ruleSet, err := analyzer.LoadRiskRuleSetFile(strings.TrimSpace(path))
+			if err != nil {
+				return err
+			}
+			ruleSets = append(ruleSets, ruleSet)
+		}
+	}
+
+	var thresholds []float64
+	for _, value := range strings.Split(*thresholdsArg, ",") {
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("invalid threshold %q: %w", value, err)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+
+	samples, err := analyzer.LoadBacktestSamples()
+	if err != nil {
+		return err
+	}
+	outcomes := make(map[string]int)
+	for _, sample := range samples {
+		outcomes[sample.Outcome]++
+	}
+	fmt.Printf("样本: %d (10x: %d, rug: %d, dead: %d, alive: %d)\n", len(samples),
+		outcomes[analyzer.OutcomeMoon], outcomes[analyzer.OutcomeRugged], outcomes[analyzer.OutcomeDead], outcomes[analyzer.OutcomeAlive])
+
+	fmt.Printf("%-16s %8s %8s %10s %8s %8s %10s\n", "规则集", "阈值", "通过", "precision", "recall", "rug率", "rug拦截率")
+	for _, r := range analyzer.RunBacktest(samples, ruleSets, thresholds) {
+		fmt.Printf("%-16s %8.1f %8d %10.3f %8.3f %8.3f %10.3f\n",
+			r.Version, r.Threshold, r.Passed, r.Precision, r.Recall, r.RugRate, r.RugCatchRate)
+	}
+	return nil
+}
+
+// runMevScan 对历史区块区间执行区块级 MEV 分析，结果写入 mev_events 与 block_builders（重复执行会覆盖同一区块的结果）
+// 用法: go run main.go mev -from 19000000 [-to 19000100]
+func runMevScan(args []string) error {
+	fs := flag.NewFlagSet("mev", flag.ContinueOnError)
+	from := fs.Uint64("from", 0, "起始区块")
+	to := fs.Uint64("to", 0, "结束区块（包含，为 0 时只分析起始区块）")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from == 0 {
+		return fmt.Errorf("-from is required")
+	}
+	if *to == 0 {
+		*to = *from
+	}
+	if *to < *from {
+		return fmt.Errorf("invalid block range %d-%d", *from, *to)
+	}
+
+	plugin, err := monitor.NewMevBlockPlugin(config.GetEthereumRpcUrl())
+	if err != nil {
+		return err
+	}
+	defer plugin.Close()
+
+	total := 0
+	for number := *from; number <= *to; number++ {
+		count, err := plugin.ProcessBlock(number)
+		if err != nil {
+			return fmt.Errorf("block %d: %w", number, err)
+		}
+		total += count
+	}
+	fmt.Printf("分析区块 %d-%d，识别 MEV 事件 %d 个\n", *from, *to, total)
+	return nil
+}
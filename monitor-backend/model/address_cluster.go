@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// AddressCluster 地址实体：被认定由同一交易所 / 机构 / 个人控制的一组地址
+type AddressCluster struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"name"` // 实体名称，如 "Binance"
+	Category    string    `gorm:"type:varchar(20);index" json:"category"`             // AddressCategory* 常量
+	Watched     bool      `gorm:"index" json:"watched"`                               // 整个实体纳入钱包监控（所有成员地址的转账都告警）
+	Auto        bool      `json:"auto"`                                               // 由启发式自动创建（未命名），可与其他实体合并
+	MemberCount int       `json:"member_count"`                                       // 成员地址数
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (AddressCluster) TableName() string {
+	return "address_clusters"
+}
+
+// AddressClusterMember 实体成员地址，一个地址只属于一个实体
+type AddressClusterMember struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	ClusterID  uint       `gorm:"index;not null" json:"cluster_id"`
+	Address    string     `gorm:"type:varchar(42);uniqueIndex;not null" json:"address"`
+	Label      string     `gorm:"type:varchar(100)" json:"label"`          // 成员自身名称（如 "Binance 14"），可为空
+	Heuristic  string     `gorm:"type:varchar(30);index" json:"heuristic"` // ClusterHeuristic* 常量
+	Evidence   string     `gorm:"type:varchar(300)" json:"evidence"`       // 关联依据，如共同资金来源地址、归集目标热钱包
+	ExpandedAt *time.Time `json:"expanded_at"`                             // 最近一次以该地址为起点运行启发式的时间
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AddressClusterMember) TableName() string {
+	return "address_cluster_members"
+}
+
+// 成员地址的关联方式
+const (
+	ClusterHeuristicSeed         = "seed"          // 配置中的已知地址
+	ClusterHeuristicManual       = "manual"        // 通过 API 手动添加
+	ClusterHeuristicCommonFunder = "common_funder" // 与已有成员的第一笔资金来自同一地址
+	ClusterHeuristicDepositSweep = "deposit_sweep" // 充值地址：转出几乎全部归集到成员热钱包
+	ClusterHeuristicCoSpending   = "co_spending"   // 多次与成员在同一区块向同一私有地址转账
+)
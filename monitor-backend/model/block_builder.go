@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// BlockBuilder 区块的 Builder 归属（由区块级 MEV 分析写入）
+type BlockBuilder struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	BlockNumber uint64    `gorm:"uniqueIndex;not null" json:"block_number"`
+	BlockHash   string    `gorm:"type:varchar(66)" json:"block_hash"`
+	BlockTime   time.Time `gorm:"index" json:"block_time"`
+	BlockDate   string    `gorm:"type:varchar(10);index" json:"block_date"` // UTC 日期（2006-01-02），用于按天统计
+
+	// 归属
+	FeeRecipient      string `gorm:"type:varchar(42);index" json:"fee_recipient"` // 区块 coinbase
+	BuilderName       string `gorm:"type:varchar(255);index" json:"builder_name"` // 无法识别时为空
+	ExtraData         string `gorm:"type:varchar(100)" json:"extra_data"`
+	AttributionMethod string `gorm:"type:varchar(20)" json:"attribution_method"` // coinbase / extra_data / payment / unknown
+
+	// 提议者付款（最后一笔交易由 fee recipient 转给提议者）
+	ProposerFeeRecipient string `gorm:"type:varchar(42);index" json:"proposer_fee_recipient"`
+	ProposerPaymentWei   string `gorm:"type:varchar(78)" json:"proposer_payment_wei"`
+	PaymentTxHash        string `gorm:"type:varchar(66)" json:"payment_tx_hash"`
+
+	// 区块概况
+	TxCount       int    `json:"tx_count"`
+	GasUsed       uint64 `json:"gas_used"`
+	BaseFeeWei    string `gorm:"type:varchar(78)" json:"base_fee_wei"`
+	MevEventCount int    `gorm:"index" json:"mev_event_count"` // 区块内识别到的 MEV 事件数
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (BlockBuilder) TableName() string {
+	return "block_builders"
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// BlockFee 区块 EIP-1559 费用历史（base fee 与有效小费分布），金额单位为 Gwei
+type BlockFee struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	BlockNumber uint64    `gorm:"uniqueIndex;not null" json:"block_number"`
+	BlockTime   time.Time `gorm:"index" json:"block_time"`
+
+	// base fee
+	BaseFeeWei         string  `gorm:"type:varchar(78)" json:"base_fee_wei"`
+	BaseFeeGwei        float64 `json:"base_fee_gwei"`
+	RollingBaseFeeGwei float64 `json:"rolling_base_fee_gwei"` // 最近 FeeRollingWindowBlocks 个区块的平均值
+	NextBaseFeeGwei    float64 `json:"next_base_fee_gwei"`    // 按 EIP-1559 公式推算的下一区块 base fee
+	GasUsed            uint64  `json:"gas_used"`
+	GasLimit           uint64  `json:"gas_limit"`
+	GasUsedRatio       float64 `json:"gas_used_ratio"`
+	TxCount            int     `json:"tx_count"`
+
+	// 有效小费分布
+	TipP10Gwei   float64 `json:"tip_p10_gwei"`
+	TipP25Gwei   float64 `json:"tip_p25_gwei"`
+	TipP50Gwei   float64 `json:"tip_p50_gwei"`
+	TipP75Gwei   float64 `json:"tip_p75_gwei"`
+	TipP90Gwei   float64 `json:"tip_p90_gwei"`
+	TipFenceGwei float64 `json:"tip_fence_gwei"` // 离群上界，超过即为离群小费
+	OutlierCount int     `json:"outlier_count"`  // 小费离群的交易数
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TableName 指定表名
+func (BlockFee) TableName() string {
+	return "block_fees"
+}
@@ -7,6 +7,7 @@ type ContractDeployment struct {
 	ID              uint      `gorm:"primaryKey" json:"id"`
 	ContractAddress string    `gorm:"type:varchar(42);uniqueIndex;not null" json:"contract_address"`
 	DeployerAddress string    `gorm:"type:varchar(42);index" json:"deployer_address"`
+	FactoryAddress  string    `gorm:"type:varchar(42);index" json:"factory_address"` // 由工厂合约创建时的工厂地址，直接部署为空
 	TxHash          string    `gorm:"type:varchar(66);uniqueIndex;not null" json:"tx_hash"`
 	BlockNumber     uint64    `gorm:"index" json:"block_number"`
 	Timestamp       time.Time `gorm:"index" json:"timestamp"`
@@ -14,11 +15,34 @@ type ContractDeployment struct {
 	// 合约信息
 	IsToken      bool   `gorm:"default:false" json:"is_token"`
 	IsVerified   bool   `gorm:"default:false" json:"is_verified"`
-	ContractType string `gorm:"type:varchar(50)" json:"contract_type"` // "ERC20", "ERC721", "Other"
+	ContractType string `gorm:"type:varchar(50);index" json:"contract_type"` // ContractType* 常量
+
+	// 代理信息（非代理为空）
+	ProxyType             string `gorm:"type:varchar(20)" json:"proxy_type"` // ProxyType* 常量
+	ImplementationAddress string `gorm:"type:varchar(42)" json:"implementation_address"`
 
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
 }
 
+// 合约类型
+const (
+	ContractTypeERC20      = "ERC20"
+	ContractTypeERC721     = "ERC721"
+	ContractTypeERC1155    = "ERC1155"
+	ContractTypeGnosisSafe = "GnosisSafe"
+	ContractTypeRouter     = "Router"  // Uniswap V2 Router 接口（含分叉克隆）
+	ContractTypeFactory    = "Factory" // Uniswap V2 Factory 接口（含分叉克隆）
+	ContractTypeProxy      = "Proxy"   // 代理合约，实现合约类型无法识别
+	ContractTypeUnknown    = "Unknown"
+)
+
+// 代理类型
+const (
+	ProxyTypeEIP1167   = "EIP1167"   // 最小代理（克隆）
+	ProxyTypeEIP1967   = "EIP1967"   // 可升级代理
+	ProxyTypeSafeProxy = "SafeProxy" // Gnosis Safe 代理（实现地址存于 slot 0）
+)
+
 // TableName 指定表名
 func (ContractDeployment) TableName() string {
 	return "contract_deployments"
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// DeployerProfile 部署者画像
+// 汇总同一部署者发过的代币及其结局，并记录资金来源追踪结果
+type DeployerProfile struct {
+	ID              uint   `gorm:"primaryKey" json:"id"`
+	DeployerAddress string `gorm:"type:varchar(42);uniqueIndex;not null" json:"deployer_address"`
+
+	// 发币记录
+	TokenCount    int `json:"token_count"`    // 发币总数（不含最近一次评估的代币）
+	RuggedCount   int `json:"rugged_count"`   // 已 Rug 数量
+	HoneypotCount int `json:"honeypot_count"` // 蜜罐数量
+	SurvivedCount int `json:"survived_count"` // 存活超过观察期的数量
+
+	// GoPlus honeypot_with_same_creator 曾经命中
+	SameCreatorHoneypot bool `gorm:"default:false" json:"same_creator_honeypot"`
+
+	// 资金来源（向上追溯 1-2 跳）
+	FundingSource   string     `gorm:"type:varchar(42)" json:"funding_source"`   // 资金来源地址
+	FundingLabel    string     `gorm:"type:varchar(64)" json:"funding_label"`    // 已知来源标签，如 "Tornado Cash 10 ETH"
+	FundingCategory string     `gorm:"type:varchar(20)" json:"funding_category"` // mixer / cex，未识别为空
+	FundingHops     int        `json:"funding_hops"`                             // 命中已知来源的跳数
+	FundingTracedAt *time.Time `json:"funding_traced_at"`                        // 资金来源追溯时间（为空表示尚未追溯）
+	ReputationScore float64    `gorm:"index" json:"reputation_score"`            // 信誉风险分 0-100，越高越可疑
+	Summary         string     `gorm:"type:varchar(255)" json:"summary"`         // 画像摘要（用于告警展示）
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (DeployerProfile) TableName() string {
+	return "deployer_profiles"
+}
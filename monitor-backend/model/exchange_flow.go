@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// ExchangeFlow 交易所实体的小时净流量（由交易流水汇总，不含实体内部调拨）
+// 实体为交易流水中的地址实体名称，不属于任何实体时为监控地址标签
+type ExchangeFlow struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Entity       string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_exchange_flow_bucket,priority:1" json:"entity"`
+	Currency     string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_exchange_flow_bucket,priority:2" json:"currency"`
+	BucketStart  time.Time `gorm:"not null;index;uniqueIndex:idx_exchange_flow_bucket,priority:3" json:"bucket_start"` // 小时桶起点（UTC）
+	Inflow       float64   `json:"inflow"`                                                                             // 转入交易所的数量
+	Outflow      float64   `json:"outflow"`                                                                            // 转出交易所的数量
+	NetFlow      float64   `json:"net_flow"`                                                                           // Inflow - Outflow，正数为净流入
+	InflowCount  int       `json:"inflow_count"`
+	OutflowCount int       `json:"outflow_count"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ExchangeFlow) TableName() string {
+	return "exchange_flows"
+}
+
+// ExchangeFlowAlert 净流量偏离历史的告警记录
+type ExchangeFlowAlert struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Entity      string    `gorm:"type:varchar(100);not null;index:idx_exchange_flow_alert_key,priority:1" json:"entity"`
+	Currency    string    `gorm:"type:varchar(20);not null;index:idx_exchange_flow_alert_key,priority:2" json:"currency"`
+	Window      string    `gorm:"column:window_name;type:varchar(10);not null;index:idx_exchange_flow_alert_key,priority:3" json:"window"` // 1h / 24h / 7d
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+	NetFlow     float64   `json:"net_flow"`
+	Mean        float64   `json:"mean"`    // 历史窗口净流量均值
+	StdDev      float64   `json:"std_dev"` // 历史窗口净流量标准差
+	ZScore      float64   `json:"z_score"`
+	Threshold   float64   `json:"threshold"`
+	Samples     int       `json:"samples"` // 历史窗口数
+	Content     string    `gorm:"type:text" json:"content"`
+	Status      string    `gorm:"type:varchar(20)" json:"status"` // success / failed / skipped（未配置推送渠道）
+	ErrorMsg    string    `gorm:"type:text" json:"error_msg"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index:idx_exchange_flow_alert_key,priority:4" json:"created_at"`
+}
+
+// TableName 指定表名
+func (ExchangeFlowAlert) TableName() string {
+	return "exchange_flow_alerts"
+}
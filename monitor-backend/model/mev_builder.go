@@ -4,18 +4,54 @@ import (
 	"time"
 )
 
-// MevBuilder MEV Builder 信息模型
+// MevBuilder 地址标签模型（Builder、搜索者、交易所、锁仓合约、路由等）
+// 启动时由配置中的已知地址初始化，之后可通过 /api/labels 维护
 type MevBuilder struct {
 	ID         uint      `gorm:"primaryKey" json:"id"`
-	Name       string    `gorm:"type:varchar(255);not null" json:"name"`               // Builder 名称
+	Name       string    `gorm:"type:varchar(255);not null" json:"name"`               // 名称
 	Url        string    `gorm:"type:varchar(500)" json:"url"`                         // Builder URL
-	Address    string    `gorm:"type:varchar(42);uniqueIndex;not null" json:"address"` // Builder 地址（唯一）
+	Address    string    `gorm:"type:varchar(42);uniqueIndex;not null" json:"address"` // 地址（唯一，小写）
 	BotAddress string    `gorm:"type:varchar(42)" json:"bot_address"`                  // 关联的 Bot 地址
 	Ens        string    `gorm:"type:varchar(255)" json:"ens"`                         // ENS 域名
+	Category   string    `gorm:"type:varchar(20);index" json:"category"`               // AddressCategory* 常量
+	Source     string    `gorm:"type:varchar(20)" json:"source"`                       // LabelSource* 常量
 	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`                     // 创建时间
 	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`                     // 更新时间
 }
 
+// 地址分类
+const (
+	AddressCategoryBuilder      = "builder"       // 区块构建者
+	AddressCategorySearcher     = "searcher"      // MEV 搜索者 / Bot
+	AddressCategoryExchange     = "exchange"      // 中心化交易所
+	AddressCategoryMixer        = "mixer"         // 混币器
+	AddressCategoryLocker       = "locker"        // 流动性 / 代币锁仓合约
+	AddressCategoryRouter       = "router"        // DEX / 聚合器路由
+	AddressCategorySniperRouter = "sniper_router" // Telegram 狙击机器人路由
+	AddressCategoryOther        = "other"
+)
+
+// 标签来源
+const (
+	LabelSourceConfig = "config" // 配置中的已知地址，配置变化时随启动更新
+	LabelSourceManual = "manual" // 通过 API 维护，启动时不会被配置覆盖
+)
+
+// IsMevCategory 分类是否属于 MEV 参与者（Builder 或搜索者）
+func IsMevCategory(category string) bool {
+	return category == AddressCategoryBuilder || category == AddressCategorySearcher
+}
+
+// IsValidAddressCategory 是否为已知分类
+func IsValidAddressCategory(category string) bool {
+	switch category {
+	case AddressCategoryBuilder, AddressCategorySearcher, AddressCategoryExchange, AddressCategoryMixer,
+		AddressCategoryLocker, AddressCategoryRouter, AddressCategorySniperRouter, AddressCategoryOther:
+		return true
+	}
+	return false
+}
+
 // TableName 指定表名
 func (MevBuilder) TableName() string {
 	return "mev_builders"
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// MevEvent 区块级 MEV 分析识别到的事件
+type MevEvent struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	EventType   string    `gorm:"type:varchar(20);uniqueIndex:idx_mev_event_key;not null" json:"event_type"` // MevEventType* 常量
+	TxHash      string    `gorm:"type:varchar(66);uniqueIndex:idx_mev_event_key;not null" json:"tx_hash"`    // 主交易：三明治为前置交易，JIT 为添加流动性交易
+	Pool        string    `gorm:"type:varchar(42);uniqueIndex:idx_mev_event_key" json:"pool"`                // 池子；套利为首个池子，清算为借贷市场合约
+	LogIndex    uint      `gorm:"uniqueIndex:idx_mev_event_key" json:"log_index"`                            // 清算 / JIT 事件的日志序号，其他类型为 0
+	BlockNumber uint64    `gorm:"index" json:"block_number"`
+	BlockTime   time.Time `gorm:"index" json:"block_time"`
+	Protocol    string    `gorm:"type:varchar(30)" json:"protocol"` // uniswap_v2 / uniswap_v3 / aave / compound_v2 / compound_v3
+
+	// 发起方
+	BotAddress  string `gorm:"type:varchar(42);index" json:"bot_address"`  // 交易发起者（清算为事件中的清算人）
+	BotContract string `gorm:"type:varchar(42);index" json:"bot_contract"` // 交易调用的合约
+
+	// 关联交易与受影响地址（JSON 数组）
+	RelatedTxHashes string `gorm:"type:text" json:"related_tx_hashes"` // 三明治后置与受害交易、JIT 移除流动性与中间兑换交易
+	Victims         string `gorm:"type:text" json:"victims"`           // 三明治受害者、被清算的借款人
+	VictimCount     int    `json:"victim_count"`
+
+	// 收益（代币最小单位，无法计算时为空）
+	ProfitToken  string `gorm:"type:varchar(42)" json:"profit_token"`
+	Profit       string `gorm:"type:varchar(78)" json:"profit"`
+	GasCostWei   string `gorm:"type:varchar(78)" json:"gas_cost_wei"`
+	NetProfitWei string `gorm:"type:varchar(78)" json:"net_profit_wei"` // 利润代币为 WETH 时扣除 gas 的净利润
+
+	Detail string `gorm:"type:text" json:"detail"` // 完整检测结果 JSON
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// MEV 事件类型
+const (
+	MevEventTypeSandwich     = "sandwich"
+	MevEventTypeArbitrage    = "arbitrage"
+	MevEventTypeLiquidation  = "liquidation"
+	MevEventTypeJitLiquidity = "jit_liquidity"
+)
+
+// TableName 指定表名
+func (MevEvent) TableName() string {
+	return "mev_events"
+}
@@ -23,41 +23,98 @@ type TokenAnalysis struct {
 	IsVerified     bool   `gorm:"default:false" json:"is_verified"`
 	IsHoneypot     bool   `gorm:"default:false" json:"is_honeypot"`
 	HoneypotReason string `gorm:"type:text" json:"honeypot_reason"`
+	HoneypotSource string `gorm:"type:varchar(20)" json:"honeypot_source"` // goplus / honeypot.is / simulation / consensus
+	HoneypotVotes  string `gorm:"type:varchar(100)" json:"honeypot_votes"` // 各检测服务结论，如 "goplus=safe,simulation=honeypot"
+
+	// 检测服务结论不一致（按 SECURITY_CONSENSUS_POLICY 裁决）
+	SecurityProvidersDisagree bool `gorm:"default:false" json:"security_providers_disagree"`
+
+	// GoPlus 额外风险项
+	OwnerChangeBalance   bool `gorm:"default:false" json:"owner_change_balance"`    // owner 可修改持有者余额
+	HiddenOwner          bool `gorm:"default:false" json:"hidden_owner"`            // 存在隐藏的 owner
+	CanTakeBackOwnership bool `gorm:"default:false" json:"can_take_back_ownership"` // 放弃后可收回所有权
+	TradingCooldown      bool `gorm:"default:false" json:"trading_cooldown"`        // 交易冷却限制
+
+	// 源码验证（区块浏览器）
+	CompilerVersion string `gorm:"type:varchar(64)" json:"compiler_version"`
+	LicenseType     string `gorm:"type:varchar(64)" json:"license_type"`
+	SourceFindings  string `gorm:"type:text" json:"source_findings"` // JSON 数组，源码可疑模式，如 ["hidden_fee_setter"]
 
 	// 税率
 	BuyTax  float64 `json:"buy_tax"`
 	SellTax float64 `json:"sell_tax"`
 
 	// 持有者分析
-	HolderCount     int     `json:"holder_count"`
-	Top10HoldingPct float64 `json:"top10_holding_pct"` // 前10持有者占比
+	HolderCount           int     `json:"holder_count"`
+	Top10HoldingPct       float64 `json:"top10_holding_pct"`       // 前10持有者占比（排除交易对、销毁地址、锁仓合约）
+	DeployerHoldingPct    float64 `json:"deployer_holding_pct"`    // 部署者持仓占比
+	HolderScannedBlock    uint64  `json:"holder_scanned_block"`    // 持有者索引已回放到的区块（增量扫描游标）
+	HolderDataUnavailable bool    `json:"holder_data_unavailable"` // 无法定位创建区块，未做持有者统计（不完整的回放会得到错误的余额）
+
+	// 部署信息
+	DeployerAddress string `gorm:"type:varchar(42);index" json:"deployer_address"`
+	CreationBlock   uint64 `json:"creation_block"`
+
+	// 部署者信誉
+	CreatorHoneypotFlag     bool    `gorm:"default:false" json:"creator_honeypot_flag"` // GoPlus honeypot_with_same_creator
+	DeployerReputationScore float64 `json:"deployer_reputation_score"`                  // 部署者信誉风险分 0-100，越高越可疑
+	DeployerSummary         string  `gorm:"type:varchar(255)" json:"deployer_summary"`  // 部署者画像摘要
+
+	// 早期买家 / 狙击分析（加池后前 N 个区块）
+	LaunchBlock          uint64  `json:"launch_block"`                                // 首次加流动性的区块
+	EarlyBuyerCount      int     `json:"early_buyer_count"`                           // 早期买家数
+	SniperBuyerCount     int     `json:"sniper_buyer_count"`                          // MEV / 狙击机器人买家数
+	InsiderBuyerCount    int     `json:"insider_buyer_count"`                         // 部署者或其资助钱包的买家数
+	SniperSharePct       float64 `json:"sniper_share_pct"`                            // 机器人早期买入量占总供应比例
+	InsiderSharePct      float64 `json:"insider_share_pct"`                           // 部署者关联钱包早期买入量占总供应比例
+	BundledLaunch        bool    `gorm:"default:false" json:"bundled_launch"`         // 部署者关联钱包在加池区块内买入
+	PrivateBuyerCount    int     `json:"private_buyer_count"`                         // 买入交易未经公开内存池的早期买家数（LaunchMempoolCovered 时有效）
+	TipBuyerCount        int     `json:"tip_buyer_count"`                             // 买入交易直接向 Builder 支付小费的早期买家数
+	LaunchMempoolCovered bool    `gorm:"default:false" json:"launch_mempool_covered"` // 早期买入窗口在内存池监听覆盖期内
+	SniperScanned        bool    `gorm:"default:false" json:"sniper_scanned"`         // 早期买家窗口已完整分析
 
 	// 所有权
 	OwnerAddress         string `gorm:"type:varchar(42)" json:"owner_address"`
 	IsOwnershipRenounced bool   `gorm:"default:false" json:"is_ownership_renounced"`
 
+	// 合约权限能力
+	IsProxy             bool   `gorm:"default:false" json:"is_proxy"`                // 是否是可升级代理（EIP-1967）
+	ProxyImplementation string `gorm:"type:varchar(42)" json:"proxy_implementation"` // 代理实现合约地址
+	ProxyAdmin          string `gorm:"type:varchar(42)" json:"proxy_admin"`          // 代理管理员地址
+	DangerousFunctions  string `gorm:"type:text" json:"dangerous_functions"`         // JSON 数组，危险函数类别，如 ["mint","blacklist"]
+
 	// 风险评分
 	RiskScore float64 `gorm:"index" json:"risk_score"`                  // 0-100，越低越安全
 	RiskLevel string  `gorm:"type:varchar(20);index" json:"risk_level"` // "low", "medium", "high", "critical"
 	RiskFlags string  `gorm:"type:text" json:"risk_flags"`              // JSON 数组，危险信号列表
 
-	// 监控状态 (新策略核心)
-	// Status: PENDING_LIQUIDITY, ANALYZING, MONITORING, POTENTIAL, REJECTED, RUGGED, EXPIRED
-	Status string `gorm:"type:varchar(20);index;default:'PENDING_LIQUIDITY'" json:"status"`
+	RuleSetVersion string `gorm:"type:varchar(32);index" json:"rule_set_version"` // 评分所用规则集版本
+
+	// 监控状态 (新策略核心)，流转规则见 token_status.go，只能通过 TokenAnalysisRepository.Transition 修改
+	// Status: PENDING_PAIR, PENDING_LIQUIDITY, ANALYZING, MONITORING, POTENTIAL, REJECTED, RUGGED, EXPIRED
+	Status TokenStatus `gorm:"type:varchar(20);index;default:'PENDING_LIQUIDITY'" json:"status"`
 
-	// SafetyStatus: PENDING, COMPLETED, RETRY_NEEDED
-	SafetyStatus string `gorm:"type:varchar(20);default:'PENDING'" json:"safety_status"`
+	// SafetyStatus: PENDING, COMPLETED, RETRY_NEEDED, RETRY_EXHAUSTED
+	SafetyStatus SafetyStatus `gorm:"type:varchar(20);default:'PENDING'" json:"safety_status"`
+
+	// 安全检测重试：已尝试次数与下次检测时间（指数退避）
+	SafetyAttempts    int       `gorm:"default:0" json:"safety_attempts"`
+	NextSafetyCheckAt time.Time `gorm:"index" json:"next_safety_check_at"`
 
 	// 时间戳记录
 	PairCreatedAt    time.Time `json:"pair_created_at"`
 	LiquidityAddedAt time.Time `json:"liquidity_added_at"`
 	LastCheckAt      time.Time `json:"last_check_at"`
 
-	// 社交信息（可选）
+	// 社交信息（源码注释或链上元数据）
 	Website  string `gorm:"type:varchar(500)" json:"website"`
 	Twitter  string `gorm:"type:varchar(500)" json:"twitter"`
 	Telegram string `gorm:"type:varchar(500)" json:"telegram"`
 
+	// ENS 反向解析（经正向解析校验）
+	DeployerENS string `gorm:"type:varchar(255)" json:"deployer_ens"`
+	OwnerENS    string `gorm:"type:varchar(255)" json:"owner_ens"`
+
 	AnalyzedAt time.Time `gorm:"index" json:"analyzed_at"`
 	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
 }
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// TokenHolder 代币持有者余额（由 Transfer 日志回放得到）
+type TokenHolder struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TokenAddress  string    `gorm:"type:varchar(42);uniqueIndex:idx_token_holder;not null" json:"token_address"`
+	HolderAddress string    `gorm:"type:varchar(42);uniqueIndex:idx_token_holder;not null" json:"holder_address"`
+	Balance       string    `gorm:"type:varchar(100);not null" json:"balance"` // 十进制字符串，最小单位
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName 指定表名
+func (TokenHolder) TableName() string {
+	return "token_holders"
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// TokenSecurityReport 安全检测服务的原始检测报告
+// 每次实际请求服务（未命中缓存）记录一条，保留完整原始返回，便于复查各服务的分歧
+type TokenSecurityReport struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	TokenAddress string `gorm:"type:varchar(42);index;not null" json:"token_address"`
+	Provider     string `gorm:"type:varchar(20);index" json:"provider"` // goplus / honeypot.is / simulation
+
+	HasData    bool    `gorm:"default:false" json:"has_data"` // 服务是否收录该代币
+	IsHoneypot bool    `gorm:"default:false" json:"is_honeypot"`
+	BuyTax     float64 `json:"buy_tax"`
+	SellTax    float64 `json:"sell_tax"`
+	Error      string  `gorm:"type:text" json:"error"`       // 请求失败时的错误
+	RawPayload string  `gorm:"type:text" json:"raw_payload"` // 服务原始返回（JSON）
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (TokenSecurityReport) TableName() string {
+	return "token_security_reports"
+}
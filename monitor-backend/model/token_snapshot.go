@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// TokenSnapshot 代币状态快照
+// 定期记录监控中代币的池子与价格，用于 Rug 检测和策略回测的结局标注
+type TokenSnapshot struct {
+	ID           uint        `gorm:"primaryKey" json:"id"`
+	TokenAddress string      `gorm:"type:varchar(42);index;not null" json:"token_address"`
+	Status       TokenStatus `gorm:"type:varchar(20)" json:"status"` // 快照时的监控状态
+
+	LiquidityUSD    float64 `json:"liquidity_usd"`
+	LiquidityETH    float64 `json:"liquidity_eth"` // 池子中 WETH 数量
+	PriceETH        float64 `json:"price_eth"`     // 每枚代币的 ETH 价格
+	HolderCount     int     `json:"holder_count"`
+	Top10HoldingPct float64 `json:"top10_holding_pct"`
+
+	// DecisionInputs 仅首个快照记录：当时的代币分析记录（JSON），回测据此评分，避免用到上线后才更新的字段
+	DecisionInputs string `gorm:"type:text" json:"decision_inputs,omitempty"`
+
+	SnapshotAt time.Time `gorm:"index" json:"snapshot_at"`
+}
+
+// TableName 指定表名
+func (TokenSnapshot) TableName() string {
+	return "token_snapshots"
+}
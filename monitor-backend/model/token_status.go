@@ -0,0 +1,105 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// TokenStatus 代币监控状态
+type TokenStatus string
+
+const (
+	TokenStatusPendingPair      TokenStatus = "PENDING_PAIR"      // 已部署（识别为代币），等待创建交易对
+	TokenStatusPendingLiquidity TokenStatus = "PENDING_LIQUIDITY" // 已建池，等待加流动性
+	TokenStatusAnalyzing        TokenStatus = "ANALYZING"         // 流动性达标，等待安全分析
+	TokenStatusMonitoring       TokenStatus = "MONITORING"        // 通过初筛，持续监控
+	TokenStatusPotential        TokenStatus = "POTENTIAL"         // 潜力币
+	TokenStatusRejected         TokenStatus = "REJECTED"          // 被拒绝（蜜罐 / 高风险 / 超时未加池）
+	TokenStatusRugged           TokenStatus = "RUGGED"            // 已 Rug
+	TokenStatusExpired          TokenStatus = "EXPIRED"           // 超过监控期
+)
+
+// SafetyStatus 安全分析状态
+type SafetyStatus string
+
+const (
+	SafetyStatusPending        SafetyStatus = "PENDING"
+	SafetyStatusCompleted      SafetyStatus = "COMPLETED"
+	SafetyStatusRetryNeeded    SafetyStatus = "RETRY_NEEDED"    // 外部数据不足，需重试
+	SafetyStatusRetryExhausted SafetyStatus = "RETRY_EXHAUSTED" // 超过重试次数或最大时长仍无数据，停止重试
+)
+
+// tokenStatusTransitions 合法的状态流转（终态 REJECTED / RUGGED / EXPIRED 不再流转）
+var tokenStatusTransitions = map[TokenStatus][]TokenStatus{
+	"":                          {TokenStatusPendingPair, TokenStatusPendingLiquidity, TokenStatusAnalyzing, TokenStatusMonitoring},
+	TokenStatusPendingPair:      {TokenStatusPendingLiquidity, TokenStatusRejected, TokenStatusExpired},
+	TokenStatusPendingLiquidity: {TokenStatusAnalyzing, TokenStatusRejected, TokenStatusExpired},
+	TokenStatusAnalyzing:        {TokenStatusMonitoring, TokenStatusRejected},
+	TokenStatusMonitoring:       {TokenStatusPotential, TokenStatusRejected, TokenStatusRugged, TokenStatusExpired},
+	TokenStatusPotential:        {TokenStatusMonitoring, TokenStatusRejected, TokenStatusRugged, TokenStatusExpired},
+}
+
+// safetyStatusTransitions 合法的安全分析状态流转
+var safetyStatusTransitions = map[SafetyStatus][]SafetyStatus{
+	"":                      {SafetyStatusPending, SafetyStatusCompleted, SafetyStatusRetryNeeded, SafetyStatusRetryExhausted},
+	SafetyStatusPending:     {SafetyStatusCompleted, SafetyStatusRetryNeeded, SafetyStatusRetryExhausted},
+	SafetyStatusRetryNeeded: {SafetyStatusCompleted, SafetyStatusRetryExhausted},
+}
+
+// IsTerminal 是否为终态（REJECTED / RUGGED / EXPIRED）
+func (s TokenStatus) IsTerminal() bool {
+	_, ok := tokenStatusTransitions[s]
+	return s != "" && !ok
+}
+
+// CanTransitionTo 判断状态流转是否合法（状态不变视为合法）
+func (s TokenStatus) CanTransitionTo(to TokenStatus) bool {
+	return s == to || containsState(tokenStatusTransitions[s], to)
+}
+
+// CanTransitionTo 判断安全分析状态流转是否合法（状态不变视为合法）
+func (s SafetyStatus) CanTransitionTo(to SafetyStatus) bool {
+	return s == to || containsState(safetyStatusTransitions[s], to)
+}
+
+// ValidateTransition 校验代币状态与安全分析状态的流转
+func ValidateTransition(from TokenStatus, to TokenStatus, fromSafety SafetyStatus, toSafety SafetyStatus) error {
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("invalid status transition %s -> %s", from, to)
+	}
+	if !fromSafety.CanTransitionTo(toSafety) {
+		return fmt.Errorf("invalid safety status transition %s -> %s", fromSafety, toSafety)
+	}
+	return nil
+}
+
+func containsState[T comparable](states []T, target T) bool {
+	for _, s := range states {
+		if s == target {
+			return true
+		}
+	}
+	return false
+}
+
+// 状态事件类型
+const (
+	StatusEventKindStatus       = "status"
+	StatusEventKindSafetyStatus = "safety_status"
+)
+
+// TokenStatusEvent 代币状态变更审计记录
+type TokenStatusEvent struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	TokenAddress string    `gorm:"type:varchar(42);index;not null" json:"token_address"`
+	Kind         string    `gorm:"type:varchar(20);index" json:"kind"` // status / safety_status
+	FromState    string    `gorm:"type:varchar(20)" json:"from_state"`
+	ToState      string    `gorm:"type:varchar(20);index" json:"to_state"`
+	Reason       string    `gorm:"type:text" json:"reason"`
+	CreatedAt    time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (TokenStatusEvent) TableName() string {
+	return "token_status_events"
+}
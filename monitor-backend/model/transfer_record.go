@@ -2,23 +2,43 @@ package model
 
 import "time"
 
-// TransferRecord 钱包监控交易流水（仅记录会触发通知的转账）
+// TransferRecord 钱包监控交易流水
+// watcher 监控只记录超过通知阈值的转账，go-ethereum 监控记录全部相关转账，以 AboveThreshold 区分
 type TransferRecord struct {
 	ID uint `gorm:"primaryKey" json:"id"`
 
 	// 监控与交易
-	MonitorLabel string `gorm:"type:varchar(100);index" json:"monitor_label"` // 监控地址标签，如 "OKX钱包"
-	Direction    string `gorm:"type:varchar(20);not null" json:"direction"`   // 转入 / 转出
-	FromAddress  string `gorm:"type:varchar(42);index;not null" json:"from_address"`
-	ToAddress    string `gorm:"type:varchar(42);index;not null" json:"to_address"`
-	Amount       string `gorm:"type:varchar(100);not null" json:"amount"`
-	Currency     string `gorm:"type:varchar(20);not null;index" json:"currency"` // ETH, USDT, USDC 等
-	TxHash       string `gorm:"type:varchar(66);uniqueIndex;not null" json:"tx_hash"`
-	BlockNumber  int    `gorm:"index;not null" json:"block_number"`
+	MonitorLabel string    `gorm:"type:varchar(100);index" json:"monitor_label"` // 监控地址标签，如 "OKX钱包"
+	Direction    string    `gorm:"type:varchar(20);not null" json:"direction"`   // 转入 / 转出
+	FromAddress  string    `gorm:"type:varchar(42);index;not null" json:"from_address"`
+	ToAddress    string    `gorm:"type:varchar(42);index;not null" json:"to_address"`
+	Amount       string    `gorm:"type:varchar(100);not null" json:"amount"`
+	Currency     string    `gorm:"type:varchar(20);not null;index" json:"currency"` // ETH, USDT, USDC 等
+	TxHash       string    `gorm:"type:varchar(66);uniqueIndex;not null" json:"tx_hash"`
+	BlockNumber  int       `gorm:"index;not null" json:"block_number"`
+	BlockTime    time.Time `gorm:"index" json:"block_time"`               // 出块时间，未能获取时为零值
+	Counterparty string    `gorm:"type:varchar(300)" json:"counterparty"` // 对手方名称（地址标签注册表），未知时为空
+
+	// 地址实体（监控地址所属的聚类，不属于任何实体时为空）
+	ClusterID    uint   `gorm:"index" json:"cluster_id"`
+	ClusterName  string `gorm:"type:varchar(100)" json:"cluster_name"`
+	IntraCluster bool   `json:"intra_cluster"` // 对手方属于同一实体（内部调拨 / 充值归集），不通知、不计入实体净流量
 
 	// 通知状态（与 wechat_alters 对应，便于对账）
-	Notified     bool   `gorm:"default:true" json:"notified"`          // 是否已发送通知
-	NotifyStatus string `gorm:"type:varchar(20)" json:"notify_status"` // success / failed
+	AboveThreshold bool   `gorm:"index" json:"above_threshold"`          // 金额超过通知阈值（大额转账）
+	Notified       bool   `gorm:"default:true" json:"notified"`          // 是否已发送通知（MEV 交易为 false）
+	NotifyStatus   string `gorm:"type:varchar(20)" json:"notify_status"` // success / failed / mev_suppressed / intra_cluster
+
+	// MEV 检测结论（未检测时为空）
+	IsMev         bool    `gorm:"index" json:"is_mev"`              // 综合置信度达到阈值，未发送通知
+	MevType       string  `gorm:"type:varchar(50)" json:"mev_type"` // 贡献最大的信号对应的 MEV 类型
+	MevConfidence float64 `json:"mev_confidence"`                   // 综合置信度 (0-1)
+	MevEvidence   string  `gorm:"type:text" json:"mev_evidence"`    // 证据列表 JSON
+
+	// 私有订单流（MempoolCovered 为 false 时未做判断）
+	MempoolCovered   bool   `json:"mempool_covered"`                          // 出块时内存池监听在线
+	PrivateOrderflow bool   `gorm:"index" json:"private_orderflow"`           // 打包前从未在公开内存池出现
+	CoinbaseTipWei   string `gorm:"type:varchar(80)" json:"coinbase_tip_wei"` // 交易内直接支付给 Builder 的小费
 
 	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
 }
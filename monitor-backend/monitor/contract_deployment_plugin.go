@@ -1,58 +1,98 @@
 package monitor
 
 import (
+	"context"
+	"errors"
 	"ethereum-monitor/analyzer"
 	"ethereum-monitor/config"
 	"ethereum-monitor/database"
 	"ethereum-monitor/logger"
 	"ethereum-monitor/model"
 	"ethereum-monitor/utils"
+	"fmt"
+	"math/big"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/HydroProtocol/ethereum-watcher/blockchain"
 	"github.com/HydroProtocol/ethereum-watcher/structs"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"go.uber.org/zap"
 )
 
 // ContractDeploymentPlugin 合约部署监听插件
+// 直接部署通过合约创建交易的回执获取地址；工厂创建（内部 CREATE/CREATE2）通过区块追踪识别，
+// 节点不支持 debug 追踪时回退为检查区块内的铸币 Transfer 日志
 type ContractDeploymentPlugin struct {
 	deploymentRepo *database.ContractDeploymentRepository
+	tokenRepo      *database.TokenAnalysisRepository
 	analyzer       *analyzer.MemeTokenAnalyzer
 	pushPlus       *utils.PushPlusNotifier
-	tokenReader    *analyzer.TokenInfoReader
+	classifier     *analyzer.ContractClassifier
+	rpcClient      *rpc.Client
+	client         *ethclient.Client
+
+	// traceUnsupported 节点不支持 debug_traceBlockByNumber 时置位，之后直接走回执检查
+	traceUnsupported atomic.Bool
+}
+
+// traceFrame callTracer 返回的调用帧
+type traceFrame struct {
+	Type  string       `json:"type"`
+	From  string       `json:"from"`
+	To    string       `json:"to"`
+	Error string       `json:"error"`
+	Calls []traceFrame `json:"calls"`
+}
+
+// traceBlockResult debug_traceBlockByNumber 中单笔交易的追踪结果
+type traceBlockResult struct {
+	TxHash string     `json:"txHash"`
+	Result traceFrame `json:"result"`
 }
 
-// NewContractDeploymentPlugin 创建合约部署监听插件
-func NewContractDeploymentPlugin(rpcURL string) (*ContractDeploymentPlugin, error) {
-	// 创建代币信息读取器
-	tokenReader, err := analyzer.NewTokenInfoReader(rpcURL)
+// NewContractDeploymentPlugin 创建合约部署监听插件，memeAnalyzer 与安全扫描器共享，由调用方负责关闭
+func NewContractDeploymentPlugin(rpcURL string, memeAnalyzer *analyzer.MemeTokenAnalyzer) (*ContractDeploymentPlugin, error) {
+	// 创建合约分类器
+	classifier, err := analyzer.NewContractClassifier(rpcURL)
 	if err != nil {
 		return nil, err
 	}
 
+	rpcClient, err := utils.DialRateLimitedRPC(rpcURL)
+	if err != nil {
+		classifier.Close()
+		return nil, fmt.Errorf("failed to dial rpc: %w", err)
+	}
+
 	// 创建通知器
 	var pushPlus *utils.PushPlusNotifier
 	if token := os.Getenv("PUSHPLUS_TOKEN"); token != "" {
 		pushPlus = utils.NewPushPlusNotifier(token)
 	}
 
-	// 暂时不创建 Meme 币分析器（需要 GoPlus API Key）
-	// goPlusAPIKey := os.Getenv("GOPLUS_API_KEY")
-	// memeAnalyzer, err := analyzer.NewMemeTokenAnalyzer(rpcURL, goPlusAPIKey)
-	// if err != nil {
-	// 	return nil, err
-	// }
-
 	return &ContractDeploymentPlugin{
 		deploymentRepo: database.NewContractDeploymentRepository(),
-		analyzer:       nil, // 暂时设为 nil
+		tokenRepo:      database.NewTokenAnalysisRepository(),
+		analyzer:       memeAnalyzer,
 		pushPlus:       pushPlus,
-		tokenReader:    tokenReader,
+		classifier:     classifier,
+		rpcClient:      rpcClient,
+		client:         ethclient.NewClient(rpcClient),
 	}, nil
 }
 
+// IsContractCreation 交易回执过滤器：只拉取合约创建交易（to 为空）的回执
+func IsContractCreation(tx blockchain.Transaction) bool {
+	return tx.GetTo() == ""
+}
+
 // AcceptTx 处理交易（检测合约部署）
 func (p *ContractDeploymentPlugin) AcceptTx(tx structs.RemovableTx) {
 	// 检查是否是合约部署交易（to 地址为空）
@@ -71,6 +111,10 @@ func (p *ContractDeploymentPlugin) AcceptTx(tx structs.RemovableTx) {
 
 // Accept 处理交易和回执（获取合约地址并分析）
 func (p *ContractDeploymentPlugin) Accept(txAndReceipt *structs.RemovableTxAndReceipt) {
+	if txAndReceipt.IsRemoved {
+		return
+	}
+
 	tx := txAndReceipt.Tx
 	receipt := txAndReceipt.Receipt
 
@@ -86,71 +130,169 @@ func (p *ContractDeploymentPlugin) Accept(txAndReceipt *structs.RemovableTxAndRe
 		return // 交易失败
 	}
 
-	// 尝试从 Receipt 中直接获取合约地址（权威方式）
+	// 从 Receipt 中直接获取合约地址（权威方式）
 	var contractAddress string
-
-	// 尝试类型断言为 EthereumTransactionReceipt 以访问 ContractAddress 字段
 	if ethReceipt, ok := receipt.(*blockchain.EthereumTransactionReceipt); ok {
 		contractAddress = ethReceipt.ContractAddress
-		if contractAddress != "" {
-			logger.Log.Debug("从由 Receipt 获取合约地址",
-				zap.String("address", contractAddress),
-				zap.String("txHash", tx.GetHash()))
-		}
 	}
 
-	// 如果未能从 Receipt 获取（例如类型断言失败），回退到旧的方法：从日志尝试获取
 	if contractAddress == "" {
-		logs := receipt.GetLogs()
-		if len(logs) > 0 {
-			// 注意：这种方式不可靠，因为 logs[0] 未必是新合约产生的
-			contractAddress = logs[0].GetAddress()
-			logger.Log.Debug("无法从 Receipt 获取地址，回退到从日志推测",
-				zap.String("address", contractAddress),
-				zap.String("txHash", tx.GetHash()))
-		} else {
-			// 如果没有日志且无法从 Receipt 获取，则无法处理
-			logger.Log.Debug("合约部署无法获取地址（无 Log 且 Receipt 字段为空）",
-				zap.String("txHash", tx.GetHash()),
-				zap.String("from", tx.GetFrom()))
+		logger.Log.Debug("无法从 Receipt 获取合约地址，跳过",
+			zap.String("txHash", tx.GetHash()),
+			zap.String("from", tx.GetFrom()))
+		return
+	}
+
+	p.recordDeployment(contractAddress, tx.GetFrom(), "", tx.GetHash(), tx.GetBlockNumber(), txAndReceipt.TimeStamp)
+}
+
+// AcceptBlock 检测区块内由工厂合约创建的合约
+func (p *ContractDeploymentPlugin) AcceptBlock(block *structs.RemovableBlock) {
+	if block.IsRemoved {
+		return
+	}
+
+	if !p.traceUnsupported.Load() {
+		err := p.detectFactoryCreationsByTrace(block)
+		if err == nil {
 			return
 		}
+		if utils.IsMethodUnsupported(err) {
+			p.traceUnsupported.Store(true)
+			logger.Log.Warn("节点不支持 debug_traceBlockByNumber，工厂创建检测改为检查铸币日志", zap.Error(err))
+		} else {
+			logger.Log.Warn("区块追踪失败，本区块改为检查铸币日志",
+				zap.Uint64("block", block.Number()),
+				zap.Error(err))
+		}
 	}
 
-	if contractAddress == "" {
-		logger.Log.Debug("无法获取合约地址，跳过",
-			zap.String("txHash", tx.GetHash()))
-		return
+	if err := p.detectFactoryCreationsByLogs(block); err != nil {
+		logger.Log.Warn("检查区块铸币日志失败",
+			zap.Uint64("block", block.Number()),
+			zap.Error(err))
+	}
+}
+
+// detectFactoryCreationsByTrace 通过 callTracer 遍历内部 CREATE/CREATE2 调用帧
+func (p *ContractDeploymentPlugin) detectFactoryCreationsByTrace(block *structs.RemovableBlock) error {
+	var results []traceBlockResult
+	err := p.rpcClient.CallContext(context.Background(), &results, "debug_traceBlockByNumber",
+		hexutil.EncodeUint64(block.Number()), map[string]string{"tracer": "callTracer"})
+	if err != nil {
+		return err
+	}
+
+	txs := block.GetTransactions()
+	for i, result := range results {
+		txHash := result.TxHash
+		if txHash == "" && i < len(txs) {
+			txHash = txs[i].GetHash()
+		}
+		deployer := result.Result.From
+		// 顶层 CREATE 是直接部署，由回执插件处理；这里只关心内部创建
+		walkCreateFrames(result.Result.Calls, func(factory, created string) {
+			p.recordDeployment(created, deployer, factory, txHash, block.Number(), block.Timestamp())
+		})
+	}
+	return nil
+}
+
+// walkCreateFrames 递归查找成功的 CREATE/CREATE2 调用帧，失败的帧及其子调用都已回滚
+func walkCreateFrames(frames []traceFrame, fn func(factory, created string)) {
+	for _, frame := range frames {
+		if frame.Error != "" {
+			continue
+		}
+		if (frame.Type == "CREATE" || frame.Type == "CREATE2") && frame.To != "" {
+			fn(frame.From, frame.To)
+		}
+		walkCreateFrames(frame.Calls, fn)
+	}
+}
+
+// detectFactoryCreationsByLogs 回退检测：区块内发出铸币 Transfer（from 为零地址）且在父区块没有代码的合约，
+// 即为本区块新创建的代币；只能发现构造时铸币的代币
+func (p *ContractDeploymentPlugin) detectFactoryCreationsByLogs(block *structs.RemovableBlock) error {
+	ctx := context.Background()
+	number := new(big.Int).SetUint64(block.Number())
+	logs, err := p.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: number,
+		ToBlock:   number,
+		Topics: [][]common.Hash{
+			{common.HexToHash(config.ERC20TransferTopic)},
+			{common.Hash{}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to filter mint logs: %w", err)
+	}
+
+	txByHash := make(map[string]blockchain.Transaction, len(block.GetTransactions()))
+	for _, tx := range block.GetTransactions() {
+		txByHash[strings.ToLower(tx.GetHash())] = tx
+	}
+
+	parent := new(big.Int).Sub(number, big.NewInt(1))
+	seen := make(map[common.Address]struct{})
+	for _, l := range logs {
+		if _, ok := seen[l.Address]; ok {
+			continue
+		}
+		seen[l.Address] = struct{}{}
+
+		tx, ok := txByHash[strings.ToLower(l.TxHash.Hex())]
+		if !ok || tx.GetTo() == "" {
+			continue // 直接部署由回执插件处理
+		}
+		code, err := p.client.CodeAt(ctx, l.Address, parent)
+		if err != nil || len(code) > 0 {
+			continue // 父区块已存在，不是新建合约
+		}
+		p.recordDeployment(strings.ToLower(l.Address.Hex()), tx.GetFrom(), tx.GetTo(), tx.GetHash(), block.Number(), block.Timestamp())
+	}
+	return nil
+}
+
+// recordDeployment 保存部署记录；识别为 ERC20 时异步分析并提前进入代币流水线
+func (p *ContractDeploymentPlugin) recordDeployment(contractAddress, deployer, factory, txHash string, blockNumber, timestamp uint64) {
+	contractAddress = strings.ToLower(contractAddress)
+	if existing, err := p.deploymentRepo.GetByAddress(contractAddress); err == nil && existing != nil {
+		return // 已记录（同一区块重复处理）
 	}
 
 	logger.Log.Info("✅ 检测到合约部署",
 		zap.String("address", contractAddress),
-		zap.String("txHash", tx.GetHash()),
-		zap.String("deployer", tx.GetFrom()),
-		zap.Uint64("block", tx.GetBlockNumber()))
-
-	// 暂时跳过 ERC20 检测（会产生大量 RPC 请求）
-	// TODO: 优化 ERC20 检测逻辑，添加批量查询或缓存
-	//	isToken := false
-	isToken := p.tokenReader.IsERC20Token(contractAddress)
+		zap.String("txHash", txHash),
+		zap.String("deployer", deployer),
+		zap.String("factory", factory),
+		zap.Uint64("block", blockNumber))
 
 	// 保存部署记录
 	deployment := &model.ContractDeployment{
 		ContractAddress: contractAddress,
-		DeployerAddress: tx.GetFrom(),
-		TxHash:          tx.GetHash(),
-		BlockNumber:     tx.GetBlockNumber(),
-		Timestamp:       time.Unix(int64(txAndReceipt.TimeStamp), 0),
-		IsToken:         isToken,
-		ContractType:    "Unknown",
+		DeployerAddress: strings.ToLower(deployer),
+		FactoryAddress:  strings.ToLower(factory),
+		TxHash:          txHash,
+		BlockNumber:     blockNumber,
+		Timestamp:       time.Unix(int64(timestamp), 0),
+		ContractType:    model.ContractTypeUnknown,
+	}
+
+	// 基于代理解析、字节码指纹与 ERC165 分类，指纹结果按字节码哈希缓存
+	if classification, err := p.classifier.Classify(contractAddress); err != nil {
+		logger.Log.Warn("合约分类失败", zap.String("address", contractAddress), zap.Error(err))
+	} else {
+		deployment.ContractType = classification.ContractType
+		deployment.ProxyType = classification.ProxyType
+		deployment.ImplementationAddress = classification.ImplementationAddress
+		deployment.IsToken = classification.ContractType == model.ContractTypeERC20
 	}
 
-	// 暂时注释掉代币分析
-	if isToken {
-		deployment.ContractType = "ERC20"
+	if deployment.IsToken {
 		logger.Log.Info("🎯 检测到 ERC20 代币部署",
 			zap.String("address", contractAddress),
-			zap.String("txHash", tx.GetHash()))
+			zap.String("txHash", txHash))
 
 		// 异步分析代币
 		go p.analyzeNewToken(contractAddress)
@@ -166,16 +308,20 @@ func (p *ContractDeploymentPlugin) analyzeNewToken(tokenAddress string) {
 	// 等待一段时间，让合约初始化完成
 	time.Sleep(30 * time.Second)
 
-	logger.Log.Info("开始分析新代币", zap.String("address", tokenAddress))
+	// 等待期间可能已建池并被 PairCreated 监听记录
+	if existing, err := p.tokenRepo.GetByAddress(tokenAddress); err == nil && existing != nil && existing.TokenAddress != "" {
+		logger.Log.Debug("代币已在流水线中，跳过部署分析", zap.String("address", tokenAddress))
+		return
+	}
 
-	// 暂时跳过完整分析，只记录日志
-	// TODO: 实现完整的代币分析
-	logger.Log.Info("代币分析功能开发中",
-		zap.String("address", tokenAddress))
+	logger.Log.Info("开始分析新代币", zap.String("address", tokenAddress))
 
-	/* 完整分析代码（需要 GoPlus API Key）*/
 	analysis, err := p.analyzer.AnalyzeToken(tokenAddress)
 	if err != nil {
+		if errors.Is(err, database.ErrTokenExists) {
+			logger.Log.Debug("代币已在流水线中", zap.String("address", tokenAddress))
+			return
+		}
 		logger.Log.Error("代币分析失败", zap.String("address", tokenAddress), zap.Error(err))
 		return
 	}
@@ -186,7 +332,6 @@ func (p *ContractDeploymentPlugin) analyzeNewToken(tokenAddress string) {
 	} else if p.analyzer.IsLowRiskToken(analysis) {
 		p.sendLowRiskTokenAlert(analysis)
 	}
-
 }
 
 // sendPotentialGemAlert 发送潜力币告警
@@ -227,13 +372,13 @@ func (p *ContractDeploymentPlugin) sendLowRiskTokenAlert(analysis *model.TokenAn
 	*/
 }
 
-// Close 关闭资源
+// Close 关闭资源（共享的 Meme 币分析器由调用方关闭）
 func (p *ContractDeploymentPlugin) Close() {
-	// if p.analyzer != nil {
-	// 	p.analyzer.Close()
-	// }
-	if p.tokenReader != nil {
-		p.tokenReader.Close()
+	if p.classifier != nil {
+		p.classifier.Close()
+	}
+	if p.client != nil {
+		p.client.Close()
 	}
 }
 
@@ -292,9 +437,13 @@ func (p *PairCreatedPlugin) Accept(log *structs.RemovableReceiptLog) {
 		pairAddress = extractAddress(log.GetData()[0:66])
 	}
 
-	// 1. 避免重复记录
+	// 1. 避免重复记录；部署时已进入流水线（PENDING_PAIR）的代币推进到待加池
 	existing, _ := p.tokenRepo.GetByAddress(newTokenAddress)
 	if existing != nil && existing.TokenAddress != "" {
+		if existing.Status == model.TokenStatusPendingPair {
+			p.promotePendingPair(existing, pairAddress)
+			return
+		}
 		logger.Log.Debug("代币已存在，跳过", zap.String("token", newTokenAddress))
 		return
 	}
@@ -303,7 +452,7 @@ func (p *PairCreatedPlugin) Accept(log *structs.RemovableReceiptLog) {
 	analysis := &model.TokenAnalysis{
 		TokenAddress:  newTokenAddress,
 		PairAddress:   pairAddress,
-		Status:        "PENDING_LIQUIDITY", // 初始状态
+		Status:        model.TokenStatusPendingLiquidity, // 初始状态
 		PairCreatedAt: time.Now(),
 		AnalyzedAt:    time.Now(),
 		LastCheckAt:   time.Now(),
@@ -313,7 +462,7 @@ func (p *PairCreatedPlugin) Accept(log *structs.RemovableReceiptLog) {
 
 	// 3. 保存到数据库
 	if err := p.tokenRepo.Create(analysis); err != nil {
-		if !strings.Contains(err.Error(), "UNIQUE") && !strings.Contains(err.Error(), "duplicate") {
+		if !errors.Is(err, database.ErrTokenExists) {
 			logger.Log.Error("保存Token失败", zap.Error(err), zap.String("token", newTokenAddress))
 		}
 		return
@@ -324,6 +473,27 @@ func (p *PairCreatedPlugin) Accept(log *structs.RemovableReceiptLog) {
 		zap.String("pair", pairAddress))
 }
 
+// promotePendingPair 部署时已分析的代币建池后转入 PENDING_LIQUIDITY，由流动性扫描器接管
+func (p *PairCreatedPlugin) promotePendingPair(analysis *model.TokenAnalysis, pairAddress string) {
+	now := time.Now()
+	analysis.PairAddress = pairAddress
+	analysis.PairCreatedAt = now
+	analysis.LastCheckAt = now
+	if err := p.tokenRepo.Transition(analysis, model.TokenStatusPendingLiquidity, "", "pair_created"); err != nil {
+		logger.Log.Error("更新代币状态失败", zap.Error(err), zap.String("token", analysis.TokenAddress))
+		return
+	}
+	if err := p.tokenRepo.Update(analysis); err != nil {
+		logger.Log.Error("保存交易对信息失败", zap.Error(err), zap.String("token", analysis.TokenAddress))
+		return
+	}
+
+	logger.Log.Info("🆕 已部署代币创建交易对，加入观察队列",
+		zap.String("token", analysis.TokenAddress),
+		zap.String("symbol", analysis.Symbol),
+		zap.String("pair", pairAddress))
+}
+
 // FromContract 返回监听的合约地址
 func (p *PairCreatedPlugin) FromContract() string {
 	return config.UniswapV2FactoryAddress
@@ -0,0 +1,102 @@
+package monitor
+
+import (
+	"context"
+	"ethereum-monitor/config"
+	"ethereum-monitor/database"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"fmt"
+	"math/big"
+
+	"github.com/HydroProtocol/ethereum-watcher/structs"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// GasFeePlugin 区块费用历史插件：统计每个区块的 base fee 与有效小费分布，写入 block_fees
+type GasFeePlugin struct {
+	client   *ethclient.Client
+	feeModel *utils.FeeModel
+	repo     *database.BlockFeeRepository
+}
+
+// NewGasFeePlugin 创建区块费用历史插件，并用已保存的历史预热滚动 base fee
+func NewGasFeePlugin(rpcURL string) (*GasFeePlugin, error) {
+	client, err := utils.DialRateLimitedEthClient(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial rpc: %w", err)
+	}
+
+	p := &GasFeePlugin{
+		client:   client,
+		feeModel: utils.NewFeeModel(),
+		repo:     database.NewBlockFeeRepository(),
+	}
+
+	recent, err := p.repo.GetRecent(config.FeeRollingWindowBlocks)
+	if err != nil {
+		logger.Log.Warn("加载区块费用历史失败，滚动 base fee 从空窗口开始", zap.Error(err))
+	}
+	for _, fee := range recent {
+		if baseFee, ok := new(big.Int).SetString(fee.BaseFeeWei, 10); ok {
+			p.feeModel.Add(fee.BlockNumber, baseFee)
+		}
+	}
+	return p, nil
+}
+
+// AcceptBlock 记录新区块的费用统计；区块被重组移除时删除记录
+func (p *GasFeePlugin) AcceptBlock(block *structs.RemovableBlock) {
+	if block.IsRemoved {
+		if err := p.repo.DeleteByBlock(block.Number()); err != nil {
+			logger.Log.Error("删除重组区块费用失败", zap.Uint64("block", block.Number()), zap.Error(err))
+		}
+		return
+	}
+
+	ethBlock, err := p.client.BlockByNumber(context.Background(), new(big.Int).SetUint64(block.Number()))
+	if err != nil {
+		logger.Log.Warn("获取区块失败，跳过费用统计", zap.Uint64("block", block.Number()), zap.Error(err))
+		return
+	}
+
+	stats := utils.ComputeBlockFeeStats(ethBlock)
+	p.feeModel.Observe(stats)
+	if err := p.repo.Save(blockFeeFromStats(stats)); err != nil {
+		logger.Log.Error("保存区块费用失败", zap.Uint64("block", block.Number()), zap.Error(err))
+	}
+}
+
+// blockFeeFromStats 将区块费用统计转换为数据库记录
+func blockFeeFromStats(stats *utils.BlockFeeStats) *model.BlockFee {
+	var gasUsedRatio float64
+	if stats.GasLimit > 0 {
+		gasUsedRatio = float64(stats.GasUsed) / float64(stats.GasLimit)
+	}
+	return &model.BlockFee{
+		BlockNumber:        stats.BlockNumber,
+		BlockTime:          stats.BlockTime,
+		BaseFeeWei:         bigString(stats.BaseFee),
+		BaseFeeGwei:        utils.WeiToGweiFloat(stats.BaseFee),
+		RollingBaseFeeGwei: utils.WeiToGweiFloat(stats.RollingBaseFee),
+		NextBaseFeeGwei:    utils.WeiToGweiFloat(stats.NextBaseFee),
+		GasUsed:            stats.GasUsed,
+		GasLimit:           stats.GasLimit,
+		GasUsedRatio:       gasUsedRatio,
+		TxCount:            stats.TxCount,
+		TipP10Gwei:         utils.WeiToGweiFloat(stats.TipP10),
+		TipP25Gwei:         utils.WeiToGweiFloat(stats.TipP25),
+		TipP50Gwei:         utils.WeiToGweiFloat(stats.TipP50),
+		TipP75Gwei:         utils.WeiToGweiFloat(stats.TipP75),
+		TipP90Gwei:         utils.WeiToGweiFloat(stats.TipP90),
+		TipFenceGwei:       utils.WeiToGweiFloat(stats.TipFence),
+		OutlierCount:       stats.OutlierCount,
+	}
+}
+
+// Close 关闭客户端
+func (p *GasFeePlugin) Close() {
+	p.client.Close()
+}
@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"context"
+	"ethereum-monitor/analyzer"
 	"ethereum-monitor/config"
 	"ethereum-monitor/logger"
 	"ethereum-monitor/scheduler" // 新增
@@ -9,6 +10,7 @@ import (
 	"os"
 
 	ethereum "github.com/HydroProtocol/ethereum-watcher"
+	"github.com/HydroProtocol/ethereum-watcher/plugin"
 	"go.uber.org/zap"
 )
 
@@ -61,9 +63,16 @@ func StartMemeMonitor() error {
 		logger.Log.Info("✅ 流动性扫描器已启动 (每 30s)")
 	}
 
+	// Meme 币分析器由安全扫描器与部署监听插件共享
+	memeAnalyzer, err := analyzer.NewMemeTokenAnalyzer(config.GetEthereumRpcUrl(), os.Getenv("GOPLUS_API_KEY"))
+	if err != nil {
+		logger.Log.Error("创建 Meme 币分析器失败", zap.Error(err))
+		return err
+	}
+	defer memeAnalyzer.Close()
+
 	// 初始化安全扫描器
-	goPlusAPIKey := os.Getenv("GOPLUS_API_KEY")
-	safetyScanner, err := scheduler.NewSafetyScanner(config.GetEthereumRpcUrl(), goPlusAPIKey)
+	safetyScanner, err := scheduler.NewSafetyScanner(memeAnalyzer)
 	if err != nil {
 		logger.Log.Error("创建安全扫描器失败", zap.Error(err))
 		return err
@@ -77,12 +86,80 @@ func StartMemeMonitor() error {
 		logger.Log.Info("✅ 安全扫描器已启动 (每 1m)")
 	}
 
+	// 初始化代币快照任务（Rug 检测 + 回测数据）
+	tokenSnapshotter, err := scheduler.NewTokenSnapshotter(config.GetEthereumRpcUrl())
+	if err != nil {
+		logger.Log.Error("创建代币快照任务失败", zap.Error(err))
+		return err
+	}
+	defer tokenSnapshotter.Close()
+
+	// 注册代币快照任务 (每 10 分钟执行一次)
+	if err := scheduler.RegisterTask("@every 10m", tokenSnapshotter.Run); err != nil {
+		logger.Log.Error("注册代币快照任务失败", zap.Error(err))
+	} else {
+		logger.Log.Info("✅ 代币快照任务已启动 (每 10m)")
+	}
+
+	// 外部风险规则文件：启动时加载，之后每 30 秒检查一次是否有修改
+	if rulesPath := config.GetRiskRulesPath(); rulesPath != "" {
+		analyzer.ReloadRiskRules(rulesPath)
+		if err := scheduler.RegisterTask("@every 30s", func() { analyzer.ReloadRiskRules(rulesPath) }); err != nil {
+			logger.Log.Error("注册风险规则重载任务失败", zap.Error(err))
+		}
+	}
+	logger.Log.Info("✅ 风险评分规则集", zap.String("version", analyzer.ActiveRiskRuleSet().Version))
+
 	// 注册 PairCreated 事件监听插件
 	watcher.RegisterReceiptLogPlugin(pairCreatedPlugin)
 	logger.Log.Info("✅ Uniswap PairCreated 事件监听插件已注册",
 		zap.String("factory", config.UniswapV2FactoryAddress),
 		zap.String("topic", config.UniswapV2PairCreatedTopic))
 
+	// 合约部署监听：部署即分析，代币在建池前进入 PENDING_PAIR
+	if config.GetDeploymentMonitorEnabled() {
+		deploymentPlugin, err := NewContractDeploymentPlugin(config.GetEthereumRpcUrl(), memeAnalyzer)
+		if err != nil {
+			logger.Log.Error("创建合约部署监听插件失败", zap.Error(err))
+			return err
+		}
+		defer deploymentPlugin.Close()
+
+		// 注册值类型的过滤插件：watcher 只对值类型执行 NeedReceipt 过滤，避免为每笔交易拉取回执
+		watcher.RegisterTxReceiptPlugin(*plugin.NewTxReceiptPluginWithFilter(deploymentPlugin.Accept, IsContractCreation))
+		watcher.RegisterBlockPlugin(deploymentPlugin)
+		logger.Log.Info("✅ 合约部署监听插件已注册（含工厂创建检测）")
+	}
+
+	// 区块级 MEV 分析：每个区块拉取一次日志，识别三明治、原子套利、清算与 JIT 流动性
+	if config.GetMevBlockAnalysisEnabled() {
+		mevPlugin, err := NewMevBlockPlugin(config.GetEthereumRpcUrl())
+		if err != nil {
+			logger.Log.Error("创建区块 MEV 分析插件失败", zap.Error(err))
+			return err
+		}
+		defer mevPlugin.Close()
+
+		watcher.RegisterBlockPlugin(mevPlugin)
+		logger.Log.Info("✅ 区块级 MEV 分析插件已注册")
+		if mevPlugin.alerter != nil {
+			logger.Log.Info("✅ 三明治受害告警已启用", zap.Int("watched", len(mevPlugin.alerter.watched)))
+		}
+	}
+
+	// 区块费用历史：base fee 与有效小费分布
+	if config.GetGasTrackingEnabled() {
+		gasPlugin, err := NewGasFeePlugin(config.GetEthereumRpcUrl())
+		if err != nil {
+			logger.Log.Error("创建区块费用插件失败", zap.Error(err))
+			return err
+		}
+		defer gasPlugin.Close()
+
+		watcher.RegisterBlockPlugin(gasPlugin)
+		logger.Log.Info("✅ 区块费用历史插件已注册")
+	}
+
 	logger.Log.Info("⏳ 开始监听新区块...")
 	logger.Log.Info("💡 提示：")
 	logger.Log.Info("   - 监听 Uniswap 新交易对创建事件")
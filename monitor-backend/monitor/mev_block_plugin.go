@@ -0,0 +1,234 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"ethereum-monitor/config"
+	"ethereum-monitor/database"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"math/big"
+	"strings"
+
+	"github.com/HydroProtocol/ethereum-watcher/structs"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+// MevBlockPlugin 区块级 MEV 分析插件：每个新区块分析一次，事件写入 mev_events，Builder 归属写入 block_builders；
+// 配置了 MEV_WATCH_ADDRESSES 时，监控地址遭三明治攻击会告警
+type MevBlockPlugin struct {
+	analyzer *utils.BlockMevAnalyzer
+	repo     *database.MevEventRepository
+	alerter  *SandwichVictimAlerter // 未配置监控地址时为 nil
+}
+
+// NewMevBlockPlugin 创建区块级 MEV 分析插件
+func NewMevBlockPlugin(rpcURL string) (*MevBlockPlugin, error) {
+	analyzer, err := utils.NewBlockMevAnalyzer(rpcURL, database.GetAddressLabelRegistry())
+	if err != nil {
+		return nil, err
+	}
+
+	var alerter *SandwichVictimAlerter
+	if watched := config.GetMevWatchAddresses(); len(watched) > 0 {
+		alerter, err = NewSandwichVictimAlerter(rpcURL, watched)
+		if err != nil {
+			analyzer.Close()
+			return nil, err
+		}
+	}
+
+	return &MevBlockPlugin{
+		analyzer: analyzer,
+		repo:     database.NewMevEventRepository(),
+		alerter:  alerter,
+	}, nil
+}
+
+// AcceptBlock 分析新区块；区块被重组移除时删除其分析结果
+func (p *MevBlockPlugin) AcceptBlock(block *structs.RemovableBlock) {
+	if block.IsRemoved {
+		if err := p.repo.DeleteByBlock(block.Number()); err != nil {
+			logger.Log.Error("删除重组区块 MEV 事件失败", zap.Uint64("block", block.Number()), zap.Error(err))
+		}
+		return
+	}
+
+	report, _, err := p.processBlock(block.Number())
+	if err != nil {
+		logger.Log.Warn("区块 MEV 分析失败", zap.Uint64("block", block.Number()), zap.Error(err))
+		return
+	}
+
+	// 只对实时区块告警，历史回扫（ProcessBlock）不告警
+	if p.alerter != nil {
+		p.alerter.Alert(report)
+	}
+}
+
+// ProcessBlock 分析指定区块并保存结果，返回识别到的事件数
+func (p *MevBlockPlugin) ProcessBlock(number uint64) (int, error) {
+	_, count, err := p.processBlock(number)
+	return count, err
+}
+
+// processBlock 分析并保存区块，返回分析结果与事件数
+func (p *MevBlockPlugin) processBlock(number uint64) (*utils.BlockMevReport, int, error) {
+	report, err := p.analyzer.AnalyzeBlock(context.Background(), number)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	events := mevEventsFromReport(report)
+	if err := p.repo.ReplaceBlock(number, blockBuilderFromReport(report, len(events)), events); err != nil {
+		return nil, 0, err
+	}
+
+	if len(events) > 0 {
+		logger.Log.Info("🥪 区块 MEV 分析完成",
+			zap.Uint64("block", number),
+			zap.Int("sandwiches", len(report.Sandwiches)),
+			zap.Int("arbitrages", len(report.Arbitrages)),
+			zap.Int("liquidations", len(report.Liquidations)),
+			zap.Int("jit", len(report.JitLiquidity)))
+	}
+	return report, len(events), nil
+}
+
+// mevEventsFromReport 将区块分析结果转换为数据库记录
+func mevEventsFromReport(report *utils.BlockMevReport) []model.MevEvent {
+	var events []model.MevEvent
+	base := func(eventType string, txHash common.Hash, pool common.Address, detail interface{}) model.MevEvent {
+		return model.MevEvent{
+			EventType:   eventType,
+			TxHash:      txHash.Hex(),
+			Pool:        addressString(pool),
+			BlockNumber: report.BlockNumber,
+			BlockTime:   report.BlockTime,
+			Detail:      jsonString(detail),
+		}
+	}
+
+	for _, s := range report.Sandwiches {
+		e := base(model.MevEventTypeSandwich, s.FrontRunTx, s.Pool, s)
+		e.Protocol = s.Protocol
+		e.BotAddress = addressString(s.Attacker)
+		e.BotContract = addressString(s.AttackerContract)
+		related := []string{s.BackRunTx.Hex()}
+		var victims []string
+		for _, v := range s.Victims {
+			related = append(related, v.TxHash.Hex())
+			victims = append(victims, addressString(v.Address))
+		}
+		e.RelatedTxHashes = jsonString(related)
+		e.Victims = jsonString(victims)
+		e.VictimCount = len(victims)
+		e.ProfitToken = addressString(s.TokenIn)
+		e.Profit = bigString(s.GrossProfit)
+		e.GasCostWei = bigString(s.GasCostWei)
+		e.NetProfitWei = bigString(s.NetProfitWei)
+		events = append(events, e)
+	}
+
+	for _, a := range report.Arbitrages {
+		e := base(model.MevEventTypeArbitrage, a.TxHash, a.Pools[0], a)
+		e.BotAddress = addressString(a.From)
+		e.BotContract = addressString(a.To)
+		e.ProfitToken = addressString(a.Token)
+		e.Profit = bigString(a.Profit)
+		e.GasCostWei = bigString(a.GasCostWei)
+		e.NetProfitWei = bigString(a.NetProfitWei)
+		events = append(events, e)
+	}
+
+	for _, l := range report.Liquidations {
+		e := base(model.MevEventTypeLiquidation, l.TxHash, l.Market, l)
+		e.LogIndex = l.LogIndex
+		e.Protocol = l.Protocol
+		e.BotAddress = addressString(l.Liquidator)
+		e.BotContract = addressString(l.To)
+		e.Victims = jsonString([]string{addressString(l.Borrower)})
+		e.VictimCount = 1
+		events = append(events, e)
+	}
+
+	for _, j := range report.JitLiquidity {
+		e := base(model.MevEventTypeJitLiquidity, j.MintTx, j.Pool, j)
+		e.LogIndex = j.MintLogIndex
+		e.Protocol = utils.SwapProtocolUniswapV3
+		e.BotAddress = addressString(j.From)
+		e.BotContract = addressString(j.To)
+		related := []string{j.BurnTx.Hex()}
+		for _, h := range j.SwapTxs {
+			related = append(related, h.Hex())
+		}
+		e.RelatedTxHashes = jsonString(related)
+		events = append(events, e)
+	}
+	return events
+}
+
+// blockBuilderFromReport 将区块的 Builder 归属转换为数据库记录
+func blockBuilderFromReport(report *utils.BlockMevReport, mevEventCount int) *model.BlockBuilder {
+	b := report.Builder
+	return &model.BlockBuilder{
+		BlockNumber:          report.BlockNumber,
+		BlockHash:            report.BlockHash.Hex(),
+		BlockTime:            report.BlockTime,
+		BlockDate:            report.BlockTime.UTC().Format("2006-01-02"),
+		FeeRecipient:         addressString(b.FeeRecipient),
+		BuilderName:          b.BuilderName,
+		ExtraData:            b.ExtraData,
+		AttributionMethod:    b.Method,
+		ProposerFeeRecipient: addressString(b.ProposerFeeRecipient),
+		ProposerPaymentWei:   bigString(b.ProposerPayment),
+		PaymentTxHash:        hashString(b.PaymentTx),
+		TxCount:              report.TxCount,
+		GasUsed:              report.GasUsed,
+		BaseFeeWei:           bigString(report.BaseFee),
+		MevEventCount:        mevEventCount,
+	}
+}
+
+// addressString 地址转小写十六进制，零地址返回空
+func addressString(addr common.Address) string {
+	if addr == (common.Address{}) {
+		return ""
+	}
+	return strings.ToLower(addr.Hex())
+}
+
+// hashString 哈希转十六进制，零值返回空
+func hashString(hash common.Hash) string {
+	if hash == (common.Hash{}) {
+		return ""
+	}
+	return hash.Hex()
+}
+
+// bigString 大整数转十进制字符串，nil 返回空
+func bigString(v *big.Int) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// jsonString 序列化为 JSON 字符串，失败时返回空
+func jsonString(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Close 关闭资源
+func (p *MevBlockPlugin) Close() {
+	p.analyzer.Close()
+	if p.alerter != nil {
+		p.alerter.Close()
+	}
+}
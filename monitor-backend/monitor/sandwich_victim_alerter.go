@@ -0,0 +1,158 @@
+package monitor
+
+import (
+	"context"
+	"ethereum-monitor/database"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"ethereum-monitor/utils"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// SandwichVictimAlerter 被监控地址遭三明治攻击时告警
+// 受害交易来自区块级 MEV 分析结果，告警推送到 PushPlus / 企业微信，并以 MEV_DETECTION 类型写入 wechat_alters
+type SandwichVictimAlerter struct {
+	watched    map[string]string // 小写地址 -> 标签
+	client     *ethclient.Client
+	pricer     *utils.UsdPricer
+	pushPlus   *utils.PushPlusNotifier
+	wechat     *utils.WechatNotifier
+	wechatRepo *database.WechatAlterRepository
+}
+
+// NewSandwichVictimAlerter 创建三明治受害告警器，watched 为小写地址 -> 标签
+func NewSandwichVictimAlerter(rpcURL string, watched map[string]string) (*SandwichVictimAlerter, error) {
+	client, err := utils.DialRateLimitedEthClient(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial rpc: %w", err)
+	}
+
+	var pushPlus *utils.PushPlusNotifier
+	if token := os.Getenv("PUSHPLUS_TOKEN"); token != "" {
+		pushPlus = utils.NewPushPlusNotifier(token)
+	}
+	var wechat *utils.WechatNotifier
+	if webhook := os.Getenv("WECHAT_WEBHOOK_URL"); webhook != "" {
+		wechat = utils.NewWechatNotifier(webhook)
+	}
+
+	return &SandwichVictimAlerter{
+		watched:    watched,
+		client:     client,
+		pricer:     utils.NewUsdPricer(client),
+		pushPlus:   pushPlus,
+		wechat:     wechat,
+		wechatRepo: database.NewWechatAlterRepository(),
+	}, nil
+}
+
+// Alert 检查区块中的三明治，受害者是被监控地址时告警；同一受害交易只告警一次
+func (a *SandwichVictimAlerter) Alert(report *utils.BlockMevReport) {
+	for _, attack := range report.Sandwiches {
+		for _, victim := range attack.Victims {
+			label, ok := a.watched[strings.ToLower(victim.Address.Hex())]
+			if !ok {
+				continue
+			}
+			txHash := strings.ToLower(victim.TxHash.Hex())
+			if a.wechatRepo.ExistsByTypeAndTxHash(model.WechatAlterTypeMevDetection, txHash) {
+				continue
+			}
+			if err := a.alertVictim(attack, victim, label); err != nil {
+				logger.Log.Error("保存三明治受害告警失败", zap.String("tx", txHash), zap.Error(err))
+			}
+		}
+	}
+}
+
+// alertVictim 推送并记录一笔受害交易
+func (a *SandwichVictimAlerter) alertVictim(attack *utils.SandwichAttack, victim utils.SandwichVictim, label string) error {
+	confidence := utils.MevSignalConfidence(utils.MevSignalSandwich, utils.SandwichSignalLikelihood)
+	mevType := string(utils.MevTypeSandwich)
+
+	lossUSD, priced := a.pricer.SandwichVictimLossUSD(context.Background(), attack, victim)
+	amount := "无法估值"
+	amountUSD := ""
+	if priced {
+		amountUSD = fmt.Sprintf("%.2f", lossUSD)
+		amount = "约 $" + amountUSD + "（估算损失）"
+	}
+
+	victimName := victim.Address.Hex()
+	if label != "" {
+		victimName = fmt.Sprintf("%s (%s)", victim.Address.Hex(), label)
+	}
+	evidence := []string{
+		"攻击机器人: " + describeAddress(attack.AttackerContract) + "，发起者 " + describeAddress(attack.Attacker),
+		fmt.Sprintf("池子: %s (%s)", attack.Pool.Hex(), attack.Protocol),
+		fmt.Sprintf("少换到 %s 个 %s（最小单位）", victim.Loss.String(), attack.TokenOut.Hex()),
+		"前置交易: " + attack.FrontRunTx.Hex(),
+		"后置交易: " + attack.BackRunTx.Hex(),
+	}
+
+	logger.Log.Warn("🥪 监控地址遭三明治攻击",
+		zap.String("victim", victimName),
+		zap.String("attacker", attack.Attacker.Hex()),
+		zap.String("pool", attack.Pool.Hex()),
+		zap.String("lossUSD", amountUSD),
+		zap.String("tx", victim.TxHash.Hex()))
+
+	status := "success"
+	var errs []string
+	var channels []string
+	var publishToken string
+	if a.pushPlus != nil {
+		channels = append(channels, "pushplus")
+		publishToken = os.Getenv("PUSHPLUS_TOKEN")
+		if err := a.pushPlus.SendMEVDetection(mevType, attack.Attacker.Hex(), victimName, amount, victim.TxHash.Hex(), confidence, evidence); err != nil {
+			errs = append(errs, "pushplus: "+err.Error())
+		}
+	}
+	if a.wechat != nil {
+		channels = append(channels, "wechat")
+		if err := a.wechat.SendMEVDetection(mevType, attack.Attacker.Hex(), victimName, amount, victim.TxHash.Hex(), confidence, evidence); err != nil {
+			errs = append(errs, "wechat: "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		status = "failed"
+		logger.Log.Error("发送三明治受害告警失败", zap.Strings("errors", errs))
+	}
+
+	content := fmt.Sprintf("🥪 %s 遭三明治攻击，攻击者 %s，池子 %s，损失 %s", victimName, attack.Attacker.Hex(), attack.Pool.Hex(), amount)
+	return a.wechatRepo.Create(&model.WechatAlter{
+		Type:         model.WechatAlterTypeMevDetection,
+		FromAddress:  strings.ToLower(attack.Attacker.Hex()),
+		ToAddress:    strings.ToLower(victim.Address.Hex()),
+		Amount:       amountUSD,
+		Currency:     "USD",
+		TxHash:       strings.ToLower(victim.TxHash.Hex()),
+		BlockNum:     int(attack.BlockNumber),
+		MevType:      mevType,
+		Confidence:   confidence,
+		Content:      content,
+		Status:       status,
+		ErrorMsg:     strings.Join(errs, "; "),
+		PublishType:  strings.Join(channels, ","),
+		PublishToken: publishToken,
+	})
+}
+
+// describeAddress 地址附带注册表中的标签名
+func describeAddress(addr common.Address) string {
+	if name := database.GetAddressLabelRegistry().Name(addr.Hex()); name != "" {
+		return fmt.Sprintf("%s (%s)", addr.Hex(), name)
+	}
+	return addr.Hex()
+}
+
+// Close 关闭客户端
+func (a *SandwichVictimAlerter) Close() {
+	a.client.Close()
+}
@@ -15,6 +15,7 @@ type LiquidityScanner struct {
 	repo              *database.TokenAnalysisRepository
 	liquidityAnalyzer *analyzer.LiquidityAnalyzer
 	tokenReader       *analyzer.TokenInfoReader
+	pool              *WorkerPool
 }
 
 func NewLiquidityScanner(rpcURL string) (*LiquidityScanner, error) {
@@ -32,14 +33,17 @@ func NewLiquidityScanner(rpcURL string) (*LiquidityScanner, error) {
 		repo:              database.NewTokenAnalysisRepository(),
 		liquidityAnalyzer: la,
 		tokenReader:       tr,
+		pool:              NewWorkerPool("liquidity_scanner", config.GetLiquidityScanWorkers()),
 	}, nil
 }
 
 // Run 执行一次扫描
 func (s *LiquidityScanner) Run() {
+	s.expireStalePendingPairs()
+
 	// 获取待处理的代币
-	// 每次处理 20 个，避免 RPC 压力过大
-	tokens, err := s.repo.GetPendingLiquidityTokens(20)
+	// RPC 压力由限速器控制，并发数由 worker 数控制
+	tokens, err := s.repo.GetPendingLiquidityTokens(config.LiquidityScanBatchSize)
 	if err != nil {
 		logger.Log.Error("获取待扫描代币失败", zap.Error(err))
 		return
@@ -51,8 +55,30 @@ func (s *LiquidityScanner) Run() {
 
 	logger.Log.Info("开始扫描流动性", zap.Int("pending_count", len(tokens)))
 
-	for _, token := range tokens {
-		s.processToken(&token)
+	jobs := make([]func(), len(tokens))
+	for i := range tokens {
+		token := &tokens[i]
+		jobs[i] = func() { s.processToken(token) }
+	}
+	s.pool.RunBatch(jobs)
+}
+
+// expireStalePendingPairs 部署后超过时限仍未建池的代币标记为 EXPIRED
+func (s *LiquidityScanner) expireStalePendingPairs() {
+	before := time.Now().Add(-config.PendingPairExpireHours * time.Hour)
+	tokens, err := s.repo.GetStalePendingPairTokens(before, config.LiquidityScanBatchSize)
+	if err != nil {
+		logger.Log.Error("获取超时未建池代币失败", zap.Error(err))
+		return
+	}
+
+	for i := range tokens {
+		t := &tokens[i]
+		if err := s.repo.Transition(t, model.TokenStatusExpired, "", "no_pair_created"); err != nil {
+			logger.Log.Error("更新代币状态失败", zap.Error(err))
+			continue
+		}
+		logger.Log.Info("⌛ 代币部署后超时未建池，停止跟踪", zap.String("symbol", t.Symbol), zap.String("addr", t.TokenAddress))
 	}
 }
 
@@ -76,10 +102,13 @@ func (s *LiquidityScanner) processToken(t *model.TokenAnalysis) {
 
 		// 检查是否超时（例如 2 小时未加池）
 		if time.Since(t.PairCreatedAt) > 2*time.Hour {
-			t.Status = "REJECTED"
 			t.RiskFlags = `["timeout_no_liquidity"]`
 			logger.Log.Info("🗑️ 代币超时未加池，已丢弃", zap.String("symbol", t.Symbol), zap.String("addr", t.TokenAddress))
-			s.repo.Update(t)
+			if err := s.repo.Transition(t, model.TokenStatusRejected, "", "timeout_no_liquidity"); err != nil {
+				logger.Log.Error("更新代币状态失败", zap.Error(err))
+			} else if err := s.repo.Update(t); err != nil {
+				logger.Log.Error("保存代币信息失败", zap.Error(err))
+			}
 		} else {
 			// 还没超时，只更新 LastCheckAt，保持 PENDING 状态
 			if liqUSD > 100 {
@@ -108,20 +137,23 @@ func (s *LiquidityScanner) processToken(t *model.TokenAnalysis) {
 	}
 
 	// 5. 状态流转 -> ANALYZING
-	t.Status = "ANALYZING"
-
 	logger.Log.Info("💧 发现流动性达标代币",
 		zap.String("symbol", t.Symbol),
 		zap.Float64("liquidity", liqUSD),
 		zap.String("eth", "ETH")) // ethAmount undefined in logging context? No, valid var.
 
-	if err := s.repo.Update(t); err != nil {
+	if err := s.repo.Transition(t, model.TokenStatusAnalyzing, "", "liquidity_added"); err != nil {
 		logger.Log.Error("更新代币状态失败", zap.Error(err))
+		return
+	}
+	if err := s.repo.Update(t); err != nil {
+		logger.Log.Error("保存代币信息失败", zap.Error(err))
 	}
 }
 
 // Close 关闭资源
 func (s *LiquidityScanner) Close() {
+	s.pool.Close()
 	s.liquidityAnalyzer.Close()
 	s.tokenReader.Close()
 }
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"ethereum-monitor/analyzer"
+	"ethereum-monitor/config"
 	"ethereum-monitor/database"
 	"ethereum-monitor/logger"
 	"ethereum-monitor/model"
@@ -18,14 +19,11 @@ type SafetyScanner struct {
 	repo         *database.TokenAnalysisRepository
 	memeAnalyzer *analyzer.MemeTokenAnalyzer
 	notifier     *utils.PushPlusNotifier
+	pool         *WorkerPool
 }
 
-func NewSafetyScanner(rpcURL, goPlusKey string) (*SafetyScanner, error) {
-	ma, err := analyzer.NewMemeTokenAnalyzer(rpcURL, goPlusKey)
-	if err != nil {
-		return nil, err
-	}
-
+// NewSafetyScanner 创建安全扫描器，memeAnalyzer 与部署监听共享，由调用方负责关闭
+func NewSafetyScanner(memeAnalyzer *analyzer.MemeTokenAnalyzer) (*SafetyScanner, error) {
 	var notifier *utils.PushPlusNotifier
 	if token := os.Getenv("PUSHPLUS_TOKEN"); token != "" {
 		notifier = utils.NewPushPlusNotifier(token)
@@ -33,14 +31,15 @@ func NewSafetyScanner(rpcURL, goPlusKey string) (*SafetyScanner, error) {
 
 	return &SafetyScanner{
 		repo:         database.NewTokenAnalysisRepository(),
-		memeAnalyzer: ma,
+		memeAnalyzer: memeAnalyzer,
 		notifier:     notifier,
+		pool:         NewWorkerPool("safety_scanner", config.GetSafetyScanWorkers()),
 	}, nil
 }
 
 func (s *SafetyScanner) Run() {
 	// 获取待分析的代币 (ANALYZING 或 需要重试的 MONITORING)
-	tokens, err := s.repo.GetTokensForSafetyCheck(10)
+	tokens, err := s.repo.GetTokensForSafetyCheck(config.SafetyScanBatchSize)
 	if err != nil {
 		logger.Log.Error("获取待安全分析代币失败", zap.Error(err))
 		return
@@ -52,18 +51,48 @@ func (s *SafetyScanner) Run() {
 
 	logger.Log.Info("开始安全分析", zap.Int("count", len(tokens)))
 
-	for _, token := range tokens {
-		s.processToken(&token)
+	jobs := make([]func(), len(tokens))
+	for i := range tokens {
+		token := &tokens[i]
+		jobs[i] = func() { s.processToken(token) }
 	}
+	s.pool.RunBatch(jobs)
 }
 
 func (s *SafetyScanner) processToken(t *model.TokenAnalysis) {
 	oldStatus := t.Status
 	oldSafetyStatus := t.SafetyStatus
+	t.SafetyAttempts++
+	t.NextSafetyCheckAt = time.Now().Add(safetyRetryDelay(t.SafetyAttempts))
 
 	// 执行安全检测
 	if err := s.memeAnalyzer.AnalyzeSafetyOnly(t); err != nil {
-		// 如果 API 失败（网络错误），暂不改变状态，等待重试
+		if safetyRetryExhausted(t) {
+			// 持续失败同样受重试上限约束，停止重试，保持风险未知
+			t.RiskLevel = "unknown"
+			t.AnalyzedAt = time.Now()
+			logger.Log.Info("⌛ 代币安全检测持续失败，重试已用尽，停止重试",
+				zap.String("symbol", t.Symbol),
+				zap.Int("attempts", t.SafetyAttempts),
+				zap.Error(err))
+			if err := s.repo.Transition(t, model.TokenStatusMonitoring, model.SafetyStatusRetryExhausted, "safety_retry_exhausted"); err != nil {
+				logger.Log.Error("更新代币分析结果失败", zap.Error(err))
+				return
+			}
+			if err := s.repo.Update(t); err != nil {
+				logger.Log.Error("保存代币分析结果失败", zap.Error(err))
+			}
+			return
+		}
+
+		// 如果 API 失败（网络错误），暂不改变状态，按退避时间等待重试
+		logger.Log.Warn("安全检测失败，稍后重试",
+			zap.String("token", t.TokenAddress),
+			zap.Int("attempts", t.SafetyAttempts),
+			zap.Error(err))
+		if err := s.repo.Update(t); err != nil {
+			logger.Log.Error("更新代币重试计划失败", zap.Error(err))
+		}
 		return
 	}
 
@@ -74,38 +103,52 @@ func (s *SafetyScanner) processToken(t *model.TokenAnalysis) {
 	}
 
 	shouldNotify := false
+	verdictUpdated := false
+	var status model.TokenStatus
+	var safetyStatus model.SafetyStatus
+	var reason string
 
 	if isDataNotFound {
 		// 数据未找到，Token 太新
 		// 策略：放入 MONITORING 列表，但标记需要重试
-		t.Status = "MONITORING"
-		t.SafetyStatus = "RETRY_NEEDED"
+		status, safetyStatus, reason = model.TokenStatusMonitoring, model.SafetyStatusRetryNeeded, "safety_data_not_found"
 		t.RiskLevel = "unknown"
 
 		// 只有从 ANALYZING 变为 MONITORING 时才通知
-		if oldStatus == "ANALYZING" {
+		if oldStatus == model.TokenStatusAnalyzing {
 			shouldNotify = true
 		}
-		logger.Log.Info("⚠️ 代币安全数据未找到，暂时放行并标记重试", zap.String("symbol", t.Symbol))
+
+		if safetyRetryExhausted(t) {
+			// 超过最大次数或最大时长仍无数据，停止重试，保持风险未知
+			safetyStatus, reason = model.SafetyStatusRetryExhausted, "safety_retry_exhausted"
+			logger.Log.Info("⌛ 代币安全数据重试已用尽，停止重试",
+				zap.String("symbol", t.Symbol),
+				zap.Int("attempts", t.SafetyAttempts))
+		} else {
+			logger.Log.Info("⚠️ 代币安全数据未找到，暂时放行并标记重试",
+				zap.String("symbol", t.Symbol),
+				zap.Int("attempts", t.SafetyAttempts),
+				zap.Time("next_check_at", t.NextSafetyCheckAt))
+		}
 
 	} else if t.IsHoneypot || t.RiskLevel == "critical" {
-		t.Status = "REJECTED"
-		t.SafetyStatus = "COMPLETED"
+		status, safetyStatus, reason = model.TokenStatusRejected, model.SafetyStatusCompleted, "honeypot_or_critical"
+		// 之前因数据缺失放行的代币，延迟数据到达后判定为蜜罐/高风险
+		verdictUpdated = oldSafetyStatus == model.SafetyStatusRetryNeeded
 		logger.Log.Info("⛔ 拒绝高风险/蜜罐代币",
 			zap.String("symbol", t.Symbol),
 			zap.String("reason", t.HoneypotReason))
 	} else {
 		// 通过！
-		t.Status = "MONITORING"
-		t.SafetyStatus = "COMPLETED"
+		status, safetyStatus, reason = model.TokenStatusMonitoring, model.SafetyStatusCompleted, "safety_check_passed"
 
-		if oldStatus == "ANALYZING" {
+		if oldStatus == model.TokenStatusAnalyzing {
 			shouldNotify = true
-		} else if oldStatus == "MONITORING" && oldSafetyStatus == "RETRY_NEEDED" {
-			// 重试成功！
+		} else if oldStatus == model.TokenStatusMonitoring && oldSafetyStatus == model.SafetyStatusRetryNeeded {
+			// 重试成功！风险从未知变为有结论，发送判定更新通知
+			verdictUpdated = true
 			logger.Log.Info("✅ 代币重试检测通过", zap.String("symbol", t.Symbol))
-			// 可选：发送更新通知，或者单纯记录
-			// shouldNotify = true
 		}
 
 		logger.Log.Info("✅ 代币通过安全检测",
@@ -114,13 +157,44 @@ func (s *SafetyScanner) processToken(t *model.TokenAnalysis) {
 	}
 
 	t.AnalyzedAt = time.Now()
-	if err := s.repo.Update(t); err != nil {
+	if err := s.repo.Transition(t, status, safetyStatus, reason); err != nil {
 		logger.Log.Error("更新代币分析结果失败", zap.Error(err))
+		return
+	}
+	if err := s.repo.Update(t); err != nil {
+		logger.Log.Error("保存代币分析结果失败", zap.Error(err))
 	}
 
 	if shouldNotify {
 		s.sendNewTokenAlert(t)
 	}
+	if verdictUpdated {
+		s.sendVerdictUpdateAlert(t)
+	}
+}
+
+// safetyRetryDelay 第 attempts 次检测后到下次检测的间隔（指数退避，有上限）
+func safetyRetryDelay(attempts int) time.Duration {
+	delay := config.SafetyRetryBaseDelay
+	for i := 1; i < attempts && delay < config.SafetyRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > config.SafetyRetryMaxDelay {
+		delay = config.SafetyRetryMaxDelay
+	}
+	return delay
+}
+
+// safetyRetryExhausted 判断是否应停止重试：达到最大次数，或加池后超过最大时长
+func safetyRetryExhausted(t *model.TokenAnalysis) bool {
+	if t.SafetyAttempts >= config.SafetyRetryMaxAttempts {
+		return true
+	}
+	since := t.LiquidityAddedAt
+	if since.IsZero() {
+		since = t.PairCreatedAt
+	}
+	return !since.IsZero() && time.Since(since) > config.SafetyRetryMaxAgeHours*time.Hour
 }
 
 func (s *SafetyScanner) sendNewTokenAlert(t *model.TokenAnalysis) {
@@ -133,8 +207,11 @@ func (s *SafetyScanner) sendNewTokenAlert(t *model.TokenAnalysis) {
 	content += "**名称**: " + t.Name + "\n"
 	content += "**合约**: `" + t.TokenAddress + "`\n"
 	content += fmt.Sprintf("**流动性**: $%.0f\n", t.LiquidityUSD)
+	if t.DeployerSummary != "" {
+		content += fmt.Sprintf("**部署者**: %s (信誉风险 %.0f)\n", t.DeployerSummary, t.DeployerReputationScore)
+	}
 
-	if t.SafetyStatus == "RETRY_NEEDED" {
+	if t.SafetyStatus == model.SafetyStatusRetryNeeded {
 		content += "\n⚠️ **风险未知** (API未收录)\n"
 		content += "系统将持续扫描，请谨慎操作。\n"
 	} else {
@@ -150,7 +227,40 @@ func (s *SafetyScanner) sendNewTokenAlert(t *model.TokenAnalysis) {
 	go s.notifier.SendCustomAlert(title, content)
 }
 
-// Close 关闭资源
+// sendVerdictUpdateAlert 延迟的安全数据到达后发送判定更新通知
+func (s *SafetyScanner) sendVerdictUpdateAlert(t *model.TokenAnalysis) {
+	if s.notifier == nil {
+		return
+	}
+
+	title := "🔄 判定更新: " + t.Symbol
+	content := "### 安全判定已更新 (此前风险未知)\n\n"
+	content += "**名称**: " + t.Name + "\n"
+	content += "**合约**: `" + t.TokenAddress + "`\n"
+	content += fmt.Sprintf("**检测次数**: %d\n", t.SafetyAttempts)
+
+	if t.Status == model.TokenStatusRejected {
+		if t.IsHoneypot {
+			content += "\n⛔ **判定为蜜罐** - 请勿买入，持有者尽快评估\n"
+			if t.HoneypotReason != "" {
+				content += "**原因**: " + t.HoneypotReason + "\n"
+			}
+		} else {
+			content += fmt.Sprintf("\n⛔ **判定为高风险** - 风险分 %.1f (%s)\n", t.RiskScore, t.RiskLevel)
+		}
+	} else {
+		content += fmt.Sprintf("**风险分**: %.1f (%s)\n", t.RiskScore, t.RiskLevel)
+		if t.RiskLevel == "low" {
+			content += "\n✅ **低风险** - 值得关注!\n"
+		}
+	}
+
+	content += "\n[Etherscan](https://etherscan.io/address/" + t.TokenAddress + ")"
+
+	go s.notifier.SendCustomAlert(title, content)
+}
+
+// Close 关闭资源（共享的 Meme 币分析器由调用方关闭）
 func (s *SafetyScanner) Close() {
-	s.memeAnalyzer.Close()
+	s.pool.Close()
 }
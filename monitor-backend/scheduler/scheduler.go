@@ -11,7 +11,8 @@ var cronScheduler *cron.Cron
 // Init 初始化定时任务调度器
 func Init() {
 	// 创建一个支持秒级精度的 cron 调度器
-	cronScheduler = cron.New(cron.WithSeconds())
+	// 上一次执行未结束时跳过本次，避免扫描任务重叠
+	cronScheduler = cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger)))
 
 	logger.Log.Info("定时任务调度器初始化成功")
 }
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"ethereum-monitor/analyzer"
+	"ethereum-monitor/config"
+	"ethereum-monitor/database"
+	"ethereum-monitor/logger"
+	"ethereum-monitor/model"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TokenSnapshotter 代币快照任务
+// 定期记录监控中代币的池子与价格，池子 ETH 相对峰值大幅下降时标记为 RUGGED
+type TokenSnapshotter struct {
+	repo              *database.TokenAnalysisRepository
+	snapshotRepo      *database.TokenSnapshotRepository
+	liquidityAnalyzer *analyzer.LiquidityAnalyzer
+}
+
+func NewTokenSnapshotter(rpcURL string) (*TokenSnapshotter, error) {
+	la, err := analyzer.NewLiquidityAnalyzer(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenSnapshotter{
+		repo:              database.NewTokenAnalysisRepository(),
+		snapshotRepo:      database.NewTokenSnapshotRepository(),
+		liquidityAnalyzer: la,
+	}, nil
+}
+
+// Run 执行一次快照
+func (s *TokenSnapshotter) Run() {
+	tokens, err := s.repo.GetTrackedTokens(100)
+	if err != nil {
+		logger.Log.Error("获取待快照代币失败", zap.Error(err))
+		return
+	}
+
+	for _, token := range tokens {
+		s.processToken(&token)
+	}
+}
+
+func (s *TokenSnapshotter) processToken(t *model.TokenAnalysis) {
+	state, err := s.liquidityAnalyzer.GetPoolState(t.PairAddress, t.Decimals)
+	if err != nil {
+		logger.Log.Warn("读取池子状态失败", zap.String("token", t.TokenAddress), zap.Error(err))
+		return
+	}
+
+	// 峰值需在写入本次快照前读取
+	peak, err := s.snapshotRepo.GetPeakLiquidityETH(t.TokenAddress)
+	if err != nil {
+		logger.Log.Warn("读取池子峰值失败", zap.String("token", t.TokenAddress), zap.Error(err))
+		return
+	}
+
+	status, reason := t.Status, ""
+	if peak > 0 && state.LiquidityETH < peak*(1-config.RugLiquidityDropPct/100) {
+		status, reason = model.TokenStatusRugged, "liquidity_drop"
+		logger.Log.Info("💀 检测到 Rug",
+			zap.String("symbol", t.Symbol),
+			zap.Float64("peak_eth", peak),
+			zap.Float64("current_eth", state.LiquidityETH))
+	} else if !t.LiquidityAddedAt.IsZero() && time.Since(t.LiquidityAddedAt) > config.SnapshotTrackingHours*time.Hour {
+		status, reason = model.TokenStatusExpired, "tracking_window_elapsed"
+	}
+
+	t.LiquidityUSD = state.LiquidityUSD
+	t.LastCheckAt = time.Now()
+
+	snapshot := &model.TokenSnapshot{
+		TokenAddress:    t.TokenAddress,
+		Status:          status,
+		LiquidityUSD:    state.LiquidityUSD,
+		LiquidityETH:    state.LiquidityETH,
+		PriceETH:        state.PriceETH,
+		HolderCount:     t.HolderCount,
+		Top10HoldingPct: t.Top10HoldingPct,
+		SnapshotAt:      t.LastCheckAt,
+	}
+
+	// 首个快照保存决策时的分析记录，供回测评分
+	hasSnapshot, err := s.snapshotRepo.HasSnapshot(t.TokenAddress)
+	if err != nil {
+		logger.Log.Warn("读取代币快照失败", zap.String("token", t.TokenAddress), zap.Error(err))
+		return
+	}
+	if !hasSnapshot {
+		inputs, err := json.Marshal(t)
+		if err != nil {
+			logger.Log.Warn("序列化决策时分析记录失败", zap.String("token", t.TokenAddress), zap.Error(err))
+		} else {
+			snapshot.DecisionInputs = string(inputs)
+		}
+	}
+
+	if err := s.snapshotRepo.Create(snapshot); err != nil {
+		logger.Log.Error("保存代币快照失败", zap.Error(err))
+		return
+	}
+
+	if err := s.repo.SaveSnapshotResult(t); err != nil {
+		logger.Log.Error("保存代币快照结果失败", zap.Error(err))
+		return
+	}
+
+	// 状态不变时不流转：安全扫描器可能刚修改过 safety_status，无谓的流转会因状态冲突失败
+	if status == t.Status {
+		return
+	}
+	if err := s.repo.Transition(t, status, "", reason); err != nil {
+		logger.Log.Error("更新代币状态失败", zap.Error(err))
+	}
+}
+
+// Close 关闭资源
+func (s *TokenSnapshotter) Close() {
+	s.liquidityAnalyzer.Close()
+}
@@ -0,0 +1,169 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// WorkerPool 固定数量 worker 的任务队列
+// 扫描任务每次取一批代币提交，RunBatch 等待整批完成后返回，配合 cron 的 SkipIfStillRunning 避免重叠执行
+type WorkerPool struct {
+	name    string
+	workers int
+	jobs    chan queuedJob
+
+	mu          sync.Mutex
+	depth       int // 排队中（未开始）的任务数
+	running     int
+	processed   int64
+	totalWait   time.Duration // 累计排队时长
+	maxWait     time.Duration
+	totalRun    time.Duration // 累计执行时长
+	lastBatch   int
+	lastBatchAt time.Time
+	lastBatchMs int64
+}
+
+type queuedJob struct {
+	fn         func()
+	enqueuedAt time.Time
+	done       *sync.WaitGroup
+}
+
+// QueueStat 任务队列统计
+type QueueStat struct {
+	Name        string    `json:"name"`
+	Workers     int       `json:"workers"`
+	Depth       int       `json:"depth"`
+	Running     int       `json:"running"`
+	Processed   int64     `json:"processed"`
+	AvgWaitMs   int64     `json:"avg_wait_ms"` // 平均排队时长
+	MaxWaitMs   int64     `json:"max_wait_ms"`
+	AvgRunMs    int64     `json:"avg_run_ms"` // 平均执行时长
+	LastBatch   int       `json:"last_batch"`
+	LastBatchAt time.Time `json:"last_batch_at"`
+	LastBatchMs int64     `json:"last_batch_ms"`
+}
+
+var (
+	poolsMu sync.Mutex
+	pools   = make(map[string]*WorkerPool)
+)
+
+// NewWorkerPool 创建并启动任务队列，同名队列会被替换
+func NewWorkerPool(name string, workers int) *WorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &WorkerPool{
+		name:    name,
+		workers: workers,
+		jobs:    make(chan queuedJob),
+	}
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	poolsMu.Lock()
+	pools[name] = p
+	poolsMu.Unlock()
+	return p
+}
+
+// RunBatch 提交一批任务并等待全部完成
+func (p *WorkerPool) RunBatch(jobs []func()) {
+	if len(jobs) == 0 {
+		return
+	}
+	start := time.Now()
+
+	p.mu.Lock()
+	p.depth += len(jobs)
+	p.mu.Unlock()
+
+	var done sync.WaitGroup
+	done.Add(len(jobs))
+	for _, fn := range jobs {
+		p.jobs <- queuedJob{fn: fn, enqueuedAt: start, done: &done}
+	}
+	done.Wait()
+
+	p.mu.Lock()
+	p.lastBatch = len(jobs)
+	p.lastBatchAt = start
+	p.lastBatchMs = time.Since(start).Milliseconds()
+	p.mu.Unlock()
+}
+
+func (p *WorkerPool) work() {
+	for job := range p.jobs {
+		started := time.Now()
+		wait := started.Sub(job.enqueuedAt)
+
+		p.mu.Lock()
+		p.depth--
+		p.running++
+		p.totalWait += wait
+		if wait > p.maxWait {
+			p.maxWait = wait
+		}
+		p.mu.Unlock()
+
+		job.fn()
+
+		p.mu.Lock()
+		p.running--
+		p.processed++
+		p.totalRun += time.Since(started)
+		p.mu.Unlock()
+		job.done.Done()
+	}
+}
+
+// Stat 获取队列统计
+func (p *WorkerPool) Stat() QueueStat {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stat := QueueStat{
+		Name:        p.name,
+		Workers:     p.workers,
+		Depth:       p.depth,
+		Running:     p.running,
+		Processed:   p.processed,
+		MaxWaitMs:   p.maxWait.Milliseconds(),
+		LastBatch:   p.lastBatch,
+		LastBatchAt: p.lastBatchAt,
+		LastBatchMs: p.lastBatchMs,
+	}
+	if p.processed > 0 {
+		stat.AvgWaitMs = p.totalWait.Milliseconds() / p.processed
+		stat.AvgRunMs = p.totalRun.Milliseconds() / p.processed
+	}
+	return stat
+}
+
+// Close 停止 worker，需在不再提交任务后调用
+func (p *WorkerPool) Close() {
+	close(p.jobs)
+
+	poolsMu.Lock()
+	if pools[p.name] == p {
+		delete(pools, p.name)
+	}
+	poolsMu.Unlock()
+}
+
+// QueueStats 获取所有任务队列的统计
+func QueueStats() []QueueStat {
+	poolsMu.Lock()
+	stats := make([]QueueStat, 0, len(pools))
+	for _, p := range pools {
+		stats = append(stats, p.Stat())
+	}
+	poolsMu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
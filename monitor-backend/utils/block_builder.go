@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"ethereum-monitor/config"
+	"math/big"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Builder 归属依据
+const (
+	BuilderAttributionCoinbase  = "coinbase"   // fee recipient 为已知 Builder 地址
+	BuilderAttributionExtraData = "extra_data" // 按 extraData 标识识别
+	BuilderAttributionPayment   = "payment"    // 未知 Builder，但最后一笔交易向提议者付款（按 fee recipient 归属）
+	BuilderAttributionUnknown   = "unknown"    // 无法识别（可能为本地出块）
+)
+
+// BuilderAttribution 区块的 Builder 归属
+type BuilderAttribution struct {
+	FeeRecipient common.Address // 区块 coinbase
+	ExtraData    string         // extraData 中的可打印文本
+	BuilderName  string         // 无法识别时为空
+	Method       string         // BuilderAttribution*
+
+	// 提议者付款：最后一笔交易由 fee recipient 向提议者转账（MEV-Boost Builder 的常见做法）
+	ProposerFeeRecipient common.Address
+	ProposerPayment      *big.Int
+	PaymentTx            common.Hash
+}
+
+// AddressLabeler 地址标签查询，由调用方注入（如数据库中的地址标签注册表），为 nil 时不按标签识别
+type AddressLabeler interface {
+	// MevLabelName 地址是已知 Builder 或搜索者时返回其名称
+	MevLabelName(address string) (string, bool)
+	// BuilderName 地址是已知 Builder 时返回其名称
+	BuilderName(address string) (string, bool)
+}
+
+// AttributeBuilder 根据 fee recipient、extraData 与最后一笔交易识别区块的 Builder，labels 用于识别已知 Builder 地址
+func AttributeBuilder(block *types.Block, labels AddressLabeler) *BuilderAttribution {
+	attribution := &BuilderAttribution{
+		FeeRecipient: block.Coinbase(),
+		ExtraData:    printableExtraData(block.Extra()),
+		Method:       BuilderAttributionUnknown,
+	}
+
+	// 1. 最后一笔交易是否为 fee recipient 向提议者的付款
+	txs := block.Transactions()
+	if len(txs) > 0 {
+		last := txs[len(txs)-1]
+		from, _ := txSenderAndTarget(last)
+		if from == block.Coinbase() && last.To() != nil && last.Value().Sign() > 0 {
+			attribution.ProposerFeeRecipient = *last.To()
+			attribution.ProposerPayment = last.Value()
+			attribution.PaymentTx = last.Hash()
+		}
+	}
+
+	// 2. fee recipient 为已知 Builder 地址
+	if labels != nil {
+		if name, ok := labels.BuilderName(block.Coinbase().Hex()); ok {
+			attribution.BuilderName = name
+			attribution.Method = BuilderAttributionCoinbase
+			return attribution
+		}
+	}
+
+	// 3. extraData 标识
+	if name := builderFromExtraData(attribution.ExtraData); name != "" {
+		attribution.BuilderName = name
+		attribution.Method = BuilderAttributionExtraData
+		return attribution
+	}
+
+	// 4. 未知 Builder 但向提议者付款，按 fee recipient 归属
+	if attribution.ProposerPayment != nil {
+		attribution.BuilderName = strings.ToLower(block.Coinbase().Hex())
+		attribution.Method = BuilderAttributionPayment
+	}
+	return attribution
+}
+
+// builderFromExtraData 按已知标识匹配 Builder 名称
+func builderFromExtraData(extra string) string {
+	lower := strings.ToLower(extra)
+	if lower == "" {
+		return ""
+	}
+	for _, tag := range config.KnownBuilderExtraData {
+		if strings.Contains(lower, tag.Keyword) {
+			return tag.Name
+		}
+	}
+	return ""
+}
+
+// printableExtraData 提取 extraData 中的可打印字符
+func printableExtraData(extra []byte) string {
+	var b strings.Builder
+	for _, r := range string(extra) {
+		if r != unicode.ReplacementChar && unicode.IsPrint(r) {
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,486 @@
+package utils
+
+import (
+	"context"
+	"ethereum-monitor/config"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// 借贷协议
+const (
+	LendingProtocolAave       = "aave"        // Aave V2/V3 及其分叉（LiquidationCall）
+	LendingProtocolCompoundV2 = "compound_v2" // Compound V2 及其分叉（LiquidateBorrow）
+	LendingProtocolCompoundV3 = "compound_v3" // Compound V3 Comet（AbsorbDebt）
+)
+
+// ArbitrageTrade 原子套利：同一交易内首尾代币相同、换回数量大于投入的闭环兑换
+type ArbitrageTrade struct {
+	TxHash  common.Hash
+	TxIndex uint
+	From    common.Address // 交易发起者
+	To      common.Address // 交易调用的合约（套利机器人）
+
+	Pools []common.Address
+	Path  []common.Address // 代币路径，首尾相同
+
+	Token        common.Address // 利润代币（闭环起点）
+	AmountIn     *big.Int
+	AmountOut    *big.Int
+	Profit       *big.Int
+	GasCostWei   *big.Int
+	NetProfitWei *big.Int // 利润代币为 WETH 时扣除 gas 后的净利润，否则为 nil
+}
+
+// Liquidation 借贷协议清算
+type Liquidation struct {
+	TxHash   common.Hash
+	TxIndex  uint
+	LogIndex uint
+	From     common.Address // 交易发起者
+	To       common.Address // 交易调用的合约
+
+	Protocol         string         // LendingProtocol*
+	Market           common.Address // 发出事件的合约（Aave Pool / cToken / Comet）
+	Liquidator       common.Address // 事件中的清算人
+	Borrower         common.Address // 被清算的借款人
+	DebtAsset        common.Address // Compound V2 为借款 cToken，Compound V3 为 Comet
+	CollateralAsset  common.Address // Compound V2 为抵押 cToken，Compound V3 为空
+	DebtRepaid       *big.Int
+	CollateralSeized *big.Int // Compound V3 吸收时不直接扣押抵押品，为 nil
+}
+
+// JitLiquidity 即时流动性：在兑换之前添加、兑换之后移除同一区间的 V3 流动性，赚取手续费
+type JitLiquidity struct {
+	Pool   common.Address
+	Token0 common.Address
+	Token1 common.Address
+
+	Owner   common.Address // 头寸所有者
+	From    common.Address // 添加流动性交易的发起者
+	To      common.Address // 添加流动性交易调用的合约
+	MintTx  common.Hash
+	BurnTx  common.Hash
+	SwapTxs []common.Hash // 夹在中间的兑换交易
+
+	MintLogIndex uint
+	TickLower    int64
+	TickUpper    int64
+	Liquidity    *big.Int
+
+	// 手续费收入 = 移除交易中 Collect 数量 - Burn 返还的本金，未在同一交易中收取时为 nil
+	Fee0 *big.Int
+	Fee1 *big.Int
+}
+
+// BlockMevReport 单个区块的 MEV 分析结果
+type BlockMevReport struct {
+	BlockNumber  uint64
+	BlockHash    common.Hash
+	BlockTime    time.Time
+	TxCount      int
+	GasUsed      uint64
+	BaseFee      *big.Int // London 之前的区块为 nil
+	Builder      *BuilderAttribution
+	Sandwiches   []*SandwichAttack
+	Arbitrages   []*ArbitrageTrade
+	Liquidations []*Liquidation
+	JitLiquidity []*JitLiquidity
+}
+
+// BlockMevAnalyzer 区块级 MEV 分析器
+// 每个区块只拉取一次区块与相关日志，统一识别三明治、原子套利、清算与 JIT 流动性，并识别区块的 Builder
+type BlockMevAnalyzer struct {
+	client   *ethclient.Client
+	sandwich *SandwichDetector
+	labels   AddressLabeler
+}
+
+// NewBlockMevAnalyzer 创建区块级 MEV 分析器，labels 用于识别已知 Builder 地址（可为 nil）
+func NewBlockMevAnalyzer(rpcURL string, labels AddressLabeler) (*BlockMevAnalyzer, error) {
+	client, err := DialRateLimitedEthClient(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial rpc: %w", err)
+	}
+	return &BlockMevAnalyzer{client: client, sandwich: NewSandwichDetector(client), labels: labels}, nil
+}
+
+// AnalyzeBlock 分析指定区块
+func (a *BlockMevAnalyzer) AnalyzeBlock(ctx context.Context, number uint64) (*BlockMevReport, error) {
+	block, err := a.client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", number, err)
+	}
+
+	blockHash := block.Hash()
+	logs, err := a.client.FilterLogs(ctx, ethereum.FilterQuery{
+		BlockHash: &blockHash,
+		Topics: [][]common.Hash{{
+			common.HexToHash(config.UniswapV2SwapTopic),
+			common.HexToHash(config.UniswapV2SyncTopic),
+			common.HexToHash(config.UniswapV3SwapTopic),
+			common.HexToHash(config.UniswapV3MintTopic),
+			common.HexToHash(config.UniswapV3BurnTopic),
+			common.HexToHash(config.UniswapV3CollectTopic),
+			common.HexToHash(config.AaveLiquidationCallTopic),
+			common.HexToHash(config.CompoundLiquidateBorrowTopic),
+			common.HexToHash(config.CompoundAbsorbDebtTopic),
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter logs of block %d: %w", number, err)
+	}
+
+	txs := make(map[common.Hash]*types.Transaction, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		txs[tx.Hash()] = tx
+	}
+
+	report := &BlockMevReport{
+		BlockNumber: number,
+		BlockHash:   blockHash,
+		BlockTime:   time.Unix(int64(block.Time()), 0),
+		TxCount:     len(block.Transactions()),
+		GasUsed:     block.GasUsed(),
+		BaseFee:     block.BaseFee(),
+		Builder:     AttributeBuilder(block, a.labels),
+	}
+
+	// 1. 三明治
+	swaps := DecodePoolSwaps(logs, txs)
+	report.Sandwiches = a.sandwich.completeSandwiches(ctx, number, FindSandwiches(swaps))
+
+	// 2. 原子套利（已归入三明治的交易不再重复识别）
+	sandwichTxs := make(map[common.Hash]bool)
+	for _, attack := range report.Sandwiches {
+		sandwichTxs[attack.FrontRunTx], sandwichTxs[attack.BackRunTx] = true, true
+	}
+	report.Arbitrages = a.findArbitrages(ctx, swaps, sandwichTxs)
+
+	// 3. 清算
+	report.Liquidations = DecodeLiquidations(logs, txs)
+
+	// 4. JIT 流动性（读不到池子代币的跳过）
+	for _, jit := range FindJitLiquidity(logs, swaps, txs) {
+		tokens, err := a.sandwich.PoolTokens(ctx, jit.Pool)
+		if err != nil {
+			if Logger != nil {
+				Logger.Warn("读取池子代币失败，跳过该 JIT 流动性", zap.Uint64("block", number), zap.String("pool", jit.Pool.Hex()), zap.Error(err))
+			}
+			continue
+		}
+		jit.Token0, jit.Token1 = tokens[0], tokens[1]
+		report.JitLiquidity = append(report.JitLiquidity, jit)
+	}
+
+	return report, nil
+}
+
+// findArbitrages 按交易分组兑换，寻找代币首尾相接且有利润的闭环；路径上有池子读不到代币时跳过该交易
+func (a *BlockMevAnalyzer) findArbitrages(ctx context.Context, swaps []*PoolSwap, skip map[common.Hash]bool) []*ArbitrageTrade {
+	byTx := make(map[common.Hash][]*PoolSwap)
+	var order []common.Hash
+	for _, s := range swaps {
+		if skip[s.TxHash] {
+			continue
+		}
+		if _, ok := byTx[s.TxHash]; !ok {
+			order = append(order, s.TxHash)
+		}
+		byTx[s.TxHash] = append(byTx[s.TxHash], s)
+	}
+
+	var trades []*ArbitrageTrade
+	for _, hash := range order {
+		txSwaps := byTx[hash]
+		if len(txSwaps) < config.ArbitrageMinSwaps {
+			continue
+		}
+
+		path := make([]common.Address, 0, len(txSwaps)+1)
+		for i, s := range txSwaps {
+			tokens, err := a.sandwich.PoolTokens(ctx, s.Pool)
+			if err != nil {
+				if Logger != nil {
+					Logger.Warn("读取池子代币失败，跳过该交易的套利识别", zap.String("tx", hash.Hex()), zap.String("pool", s.Pool.Hex()), zap.Error(err))
+				}
+				path = nil
+				break
+			}
+			tokenIn, tokenOut := tokens[1], tokens[0]
+			if s.ZeroForOne {
+				tokenIn, tokenOut = tokens[0], tokens[1]
+			}
+			if i == 0 {
+				path = append(path, tokenIn)
+			}
+			path = append(path, tokenOut)
+			// 上一跳换出的代币必须是下一跳投入的代币
+			if path[i] != tokenIn {
+				path = nil
+				break
+			}
+		}
+
+		if trade := matchArbitrageCycle(txSwaps, path); trade != nil {
+			if cost, err := txGasCost(ctx, a.client, trade.TxHash); err == nil {
+				trade.GasCostWei = cost
+				if trade.Token == common.HexToAddress(config.WETHAddress) {
+					trade.NetProfitWei = new(big.Int).Sub(trade.Profit, cost)
+				}
+			}
+			trades = append(trades, trade)
+		}
+	}
+	return trades
+}
+
+// matchArbitrageCycle 代币路径首尾相同、至少经过两个不同池子且换回数量大于投入时构成原子套利
+func matchArbitrageCycle(txSwaps []*PoolSwap, path []common.Address) *ArbitrageTrade {
+	if len(path) < 2 || path[0] != path[len(path)-1] {
+		return nil
+	}
+
+	first, last := txSwaps[0], txSwaps[len(txSwaps)-1]
+	profit := new(big.Int).Sub(last.AmountOut, first.AmountIn)
+	if profit.Sign() <= 0 {
+		return nil
+	}
+
+	seen := make(map[common.Address]bool)
+	var pools []common.Address
+	for _, s := range txSwaps {
+		if !seen[s.Pool] {
+			seen[s.Pool] = true
+			pools = append(pools, s.Pool)
+		}
+	}
+	if len(pools) < 2 {
+		return nil
+	}
+
+	return &ArbitrageTrade{
+		TxHash:    first.TxHash,
+		TxIndex:   first.TxIndex,
+		From:      first.From,
+		To:        first.To,
+		Pools:     pools,
+		Path:      path,
+		Token:     path[0],
+		AmountIn:  first.AmountIn,
+		AmountOut: last.AmountOut,
+		Profit:    profit,
+	}
+}
+
+// DecodeLiquidations 解码 Aave LiquidationCall 与 Compound LiquidateBorrow / AbsorbDebt 事件
+func DecodeLiquidations(logs []types.Log, txs map[common.Hash]*types.Transaction) []*Liquidation {
+	aave := common.HexToHash(config.AaveLiquidationCallTopic)
+	compoundV2 := common.HexToHash(config.CompoundLiquidateBorrowTopic)
+	compoundV3 := common.HexToHash(config.CompoundAbsorbDebtTopic)
+
+	var liquidations []*Liquidation
+	for i := range logs {
+		l := &logs[i]
+		if len(l.Topics) == 0 || l.Removed {
+			continue
+		}
+
+		liq := &Liquidation{Market: l.Address}
+		switch l.Topics[0] {
+		case aave:
+			if len(l.Topics) < 4 || len(l.Data) < 96 {
+				continue
+			}
+			liq.Protocol = LendingProtocolAave
+			liq.CollateralAsset = common.BytesToAddress(l.Topics[1].Bytes())
+			liq.DebtAsset = common.BytesToAddress(l.Topics[2].Bytes())
+			liq.Borrower = common.BytesToAddress(l.Topics[3].Bytes())
+			liq.DebtRepaid = new(big.Int).SetBytes(l.Data[0:32])
+			liq.CollateralSeized = new(big.Int).SetBytes(l.Data[32:64])
+			liq.Liquidator = common.BytesToAddress(l.Data[64:96])
+		case compoundV2:
+			if len(l.Data) < 160 {
+				continue
+			}
+			liq.Protocol = LendingProtocolCompoundV2
+			liq.Liquidator = common.BytesToAddress(l.Data[0:32])
+			liq.Borrower = common.BytesToAddress(l.Data[32:64])
+			liq.DebtRepaid = new(big.Int).SetBytes(l.Data[64:96])
+			liq.CollateralAsset = common.BytesToAddress(l.Data[96:128])
+			liq.CollateralSeized = new(big.Int).SetBytes(l.Data[128:160])
+			liq.DebtAsset = l.Address
+		case compoundV3:
+			if len(l.Topics) < 3 || len(l.Data) < 32 {
+				continue
+			}
+			liq.Protocol = LendingProtocolCompoundV3
+			liq.Liquidator = common.BytesToAddress(l.Topics[1].Bytes())
+			liq.Borrower = common.BytesToAddress(l.Topics[2].Bytes())
+			liq.DebtRepaid = new(big.Int).SetBytes(l.Data[0:32])
+			liq.DebtAsset = l.Address
+		default:
+			continue
+		}
+
+		liq.TxHash = l.TxHash
+		liq.TxIndex = l.TxIndex
+		liq.LogIndex = l.Index
+		liq.From, liq.To = txSenderAndTarget(txs[l.TxHash])
+		liquidations = append(liquidations, liq)
+	}
+	return liquidations
+}
+
+// v3PositionLog 解码后的 V3 Mint / Burn / Collect 事件
+type v3PositionLog struct {
+	txHash    common.Hash
+	txIndex   uint
+	logIndex  uint
+	pool      common.Address
+	owner     common.Address
+	tickLower int64
+	tickUpper int64
+	liquidity *big.Int // Collect 为 nil
+	amount0   *big.Int
+	amount1   *big.Int
+}
+
+// FindJitLiquidity 寻找 JIT 流动性：同一池子、同一所有者与价格区间，
+// 在一笔交易中添加、在之后的交易中移除相同数量的流动性，且两者之间有其他交易在该池子兑换
+func FindJitLiquidity(logs []types.Log, swaps []*PoolSwap, txs map[common.Hash]*types.Transaction) []*JitLiquidity {
+	mintTopic := common.HexToHash(config.UniswapV3MintTopic)
+	burnTopic := common.HexToHash(config.UniswapV3BurnTopic)
+	collectTopic := common.HexToHash(config.UniswapV3CollectTopic)
+
+	var mints, burns, collects []*v3PositionLog
+	for i := range logs {
+		l := &logs[i]
+		if len(l.Topics) < 4 || l.Removed {
+			continue
+		}
+		p := &v3PositionLog{
+			txHash:    l.TxHash,
+			txIndex:   l.TxIndex,
+			logIndex:  l.Index,
+			pool:      l.Address,
+			owner:     common.BytesToAddress(l.Topics[1].Bytes()),
+			tickLower: signedInt256(l.Topics[2].Bytes()).Int64(),
+			tickUpper: signedInt256(l.Topics[3].Bytes()).Int64(),
+		}
+		switch l.Topics[0] {
+		case mintTopic:
+			// data: sender, amount, amount0, amount1
+			if len(l.Data) < 128 {
+				continue
+			}
+			p.liquidity = new(big.Int).SetBytes(l.Data[32:64])
+			p.amount0 = new(big.Int).SetBytes(l.Data[64:96])
+			p.amount1 = new(big.Int).SetBytes(l.Data[96:128])
+			mints = append(mints, p)
+		case burnTopic:
+			// data: amount, amount0, amount1
+			if len(l.Data) < 96 {
+				continue
+			}
+			p.liquidity = new(big.Int).SetBytes(l.Data[0:32])
+			p.amount0 = new(big.Int).SetBytes(l.Data[32:64])
+			p.amount1 = new(big.Int).SetBytes(l.Data[64:96])
+			burns = append(burns, p)
+		case collectTopic:
+			// data: recipient, amount0, amount1
+			if len(l.Data) < 96 {
+				continue
+			}
+			p.amount0 = new(big.Int).SetBytes(l.Data[32:64])
+			p.amount1 = new(big.Int).SetBytes(l.Data[64:96])
+			collects = append(collects, p)
+		}
+	}
+
+	var jits []*JitLiquidity
+	usedBurns := make(map[*v3PositionLog]bool)
+	for _, mint := range mints {
+		if mint.liquidity.Sign() == 0 {
+			continue
+		}
+		for _, burn := range burns {
+			if usedBurns[burn] || burn.txIndex <= mint.txIndex || !samePosition(mint, burn) ||
+				burn.liquidity.Cmp(mint.liquidity) != 0 {
+				continue
+			}
+
+			swapTxs := swapsBetween(swaps, mint.pool, mint.txIndex, burn.txIndex)
+			if len(swapTxs) == 0 {
+				continue
+			}
+
+			usedBurns[burn] = true
+			jit := &JitLiquidity{
+				Pool:         mint.pool,
+				Owner:        mint.owner,
+				MintTx:       mint.txHash,
+				BurnTx:       burn.txHash,
+				SwapTxs:      swapTxs,
+				MintLogIndex: mint.logIndex,
+				TickLower:    mint.tickLower,
+				TickUpper:    mint.tickUpper,
+				Liquidity:    mint.liquidity,
+			}
+			jit.From, jit.To = txSenderAndTarget(txs[mint.txHash])
+
+			for _, collect := range collects {
+				if collect.txHash == burn.txHash && samePosition(collect, burn) {
+					jit.Fee0 = new(big.Int).Sub(collect.amount0, burn.amount0)
+					jit.Fee1 = new(big.Int).Sub(collect.amount1, burn.amount1)
+					break
+				}
+			}
+			jits = append(jits, jit)
+			break
+		}
+	}
+	return jits
+}
+
+// samePosition 同一池子、所有者与价格区间
+func samePosition(a, b *v3PositionLog) bool {
+	return a.pool == b.pool && a.owner == b.owner && a.tickLower == b.tickLower && a.tickUpper == b.tickUpper
+}
+
+// swapsBetween 返回在 (fromIndex, toIndex) 之间于指定池子兑换的交易
+func swapsBetween(swaps []*PoolSwap, pool common.Address, fromIndex, toIndex uint) []common.Hash {
+	seen := make(map[common.Hash]bool)
+	var hashes []common.Hash
+	for _, s := range swaps {
+		if s.Pool == pool && s.TxIndex > fromIndex && s.TxIndex < toIndex && !seen[s.TxHash] {
+			seen[s.TxHash] = true
+			hashes = append(hashes, s.TxHash)
+		}
+	}
+	return hashes
+}
+
+// txSenderAndTarget 交易发起者与调用的合约，交易未知时返回零地址
+func txSenderAndTarget(tx *types.Transaction) (from, to common.Address) {
+	if tx == nil {
+		return
+	}
+	from, _ = types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if tx.To() != nil {
+		to = *tx.To()
+	}
+	return
+}
+
+// Close 关闭客户端连接
+func (a *BlockMevAnalyzer) Close() {
+	a.client.Close()
+}
@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"encoding/json"
+	"ethereum-monitor/config"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExplorerClient 区块浏览器 API 客户端（Etherscan V2 兼容）
+type ExplorerClient struct {
+	apiKey     string
+	baseURL    string
+	chainID    string
+	httpClient *http.Client
+}
+
+// ContractSource 合约源码验证信息
+type ContractSource struct {
+	IsVerified      bool
+	ContractName    string
+	CompilerVersion string
+	LicenseType     string
+	ABI             string // 已验证时为 ABI JSON
+	SourceCode      string // 多文件源码已合并为单个字符串
+	IsProxy         bool   // 浏览器标记为代理合约
+	Implementation  string // 代理实现合约地址
+}
+
+// ExplorerTx 浏览器返回的交易（普通交易、内部交易与代币转账共用）
+type ExplorerTx struct {
+	Hash            string `json:"hash"`
+	BlockNumber     string `json:"blockNumber"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"` // Wei / 代币最小单位，十进制字符串
+	IsError         string `json:"isError"`
+	ContractAddress string `json:"contractAddress"` // 仅代币转账：代币合约地址
+}
+
+// explorerResponse 浏览器 API 通用响应
+// status 为 "0" 时 result 是错误描述字符串
+type explorerResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// NewExplorerClient 创建区块浏览器客户端
+func NewExplorerClient(baseURL, apiKey string) *ExplorerClient {
+	return &ExplorerClient{
+		apiKey:  apiKey,
+		baseURL: baseURL,
+		chainID: config.ExplorerChainID,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetContractSource 获取合约源码验证信息
+func (c *ExplorerClient) GetContractSource(address string) (*ContractSource, error) {
+	params := url.Values{}
+	params.Set("module", "contract")
+	params.Set("action", "getsourcecode")
+	params.Set("address", address)
+
+	var items []struct {
+		SourceCode      string `json:"SourceCode"`
+		ABI             string `json:"ABI"`
+		ContractName    string `json:"ContractName"`
+		CompilerVersion string `json:"CompilerVersion"`
+		LicenseType     string `json:"LicenseType"`
+		Proxy           string `json:"Proxy"`
+		Implementation  string `json:"Implementation"`
+	}
+	if err := c.get(params, &items); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("empty source code result")
+	}
+
+	item := items[0]
+	source := &ContractSource{
+		IsVerified:      item.SourceCode != "",
+		ContractName:    item.ContractName,
+		CompilerVersion: item.CompilerVersion,
+		LicenseType:     item.LicenseType,
+		IsProxy:         item.Proxy == "1",
+		Implementation:  strings.ToLower(item.Implementation),
+	}
+	if source.IsVerified {
+		source.ABI = item.ABI
+		source.SourceCode = flattenSourceCode(item.SourceCode)
+	}
+
+	return source, nil
+}
+
+// GetTransactions 按区块升序获取地址的最早 limit 笔普通交易
+func (c *ExplorerClient) GetTransactions(address string, limit int) ([]ExplorerTx, error) {
+	return c.getTxList("txlist", address, limit, "asc")
+}
+
+// GetInternalTransactions 按区块升序获取地址的最早 limit 笔内部交易（如 Tornado Cash 提现）
+func (c *ExplorerClient) GetInternalTransactions(address string, limit int) ([]ExplorerTx, error) {
+	return c.getTxList("txlistinternal", address, limit, "asc")
+}
+
+// GetRecentTransactions 按区块降序获取地址最近的 limit 笔普通交易
+func (c *ExplorerClient) GetRecentTransactions(address string, limit int) ([]ExplorerTx, error) {
+	return c.getTxList("txlist", address, limit, "desc")
+}
+
+// GetRecentTokenTransfers 按区块降序获取地址最近的 limit 笔 ERC20 代币转账
+func (c *ExplorerClient) GetRecentTokenTransfers(address string, limit int) ([]ExplorerTx, error) {
+	return c.getTxList("tokentx", address, limit, "desc")
+}
+
+// getTxList 查询交易列表，order 为 asc / desc
+func (c *ExplorerClient) getTxList(action, address string, limit int, order string) ([]ExplorerTx, error) {
+	params := url.Values{}
+	params.Set("module", "account")
+	params.Set("action", action)
+	params.Set("address", address)
+	params.Set("startblock", "0")
+	params.Set("endblock", "99999999")
+	params.Set("page", "1")
+	params.Set("offset", fmt.Sprintf("%d", limit))
+	params.Set("sort", order)
+
+	var txs []ExplorerTx
+	if err := c.get(params, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// get 发送 GET 请求并解析 result 字段
+func (c *ExplorerClient) get(params url.Values, result interface{}) error {
+	params.Set("chainid", c.chainID)
+	if c.apiKey != "" {
+		params.Set("apikey", c.apiKey)
+	}
+
+	GetRateLimiter(config.RateLimiterExplorer).Wait()
+	resp, err := c.httpClient.Get(c.baseURL + "?" + params.Encode())
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API 返回错误状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp explorerResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+
+	if apiResp.Status != "1" {
+		// 无记录不是错误，交给调用方按空结果处理
+		if apiResp.Message == "No transactions found" || apiResp.Message == "No records found" {
+			return nil
+		}
+		var reason string
+		json.Unmarshal(apiResp.Result, &reason)
+		return fmt.Errorf("API 返回错误: %s (%s)", apiResp.Message, reason)
+	}
+
+	if err := json.Unmarshal(apiResp.Result, result); err != nil {
+		return fmt.Errorf("解析 result 失败: %w", err)
+	}
+	return nil
+}
+
+// flattenSourceCode 将多文件源码合并为单个字符串
+// Etherscan 对多文件/标准 JSON 输入返回 "{{...}}" 或 "{...}" 包裹的 JSON
+func flattenSourceCode(raw string) string {
+	trimmed := strings.TrimSpace(raw)
+	if !strings.HasPrefix(trimmed, "{") {
+		return raw
+	}
+	if strings.HasPrefix(trimmed, "{{") && strings.HasSuffix(trimmed, "}}") {
+		trimmed = trimmed[1 : len(trimmed)-1]
+	}
+
+	type sourceFile struct {
+		Content string `json:"content"`
+	}
+
+	// 标准 JSON 输入：{"language": ..., "sources": {...}}
+	var standardInput struct {
+		Sources map[string]sourceFile `json:"sources"`
+	}
+	sources := map[string]sourceFile{}
+	if err := json.Unmarshal([]byte(trimmed), &standardInput); err == nil && len(standardInput.Sources) > 0 {
+		sources = standardInput.Sources
+	} else if err := json.Unmarshal([]byte(trimmed), &sources); err != nil || len(sources) == 0 {
+		return raw
+	}
+
+	// 按文件路径排序，保证结果稳定
+	paths := make([]string, 0, len(sources))
+	for path := range sources {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var builder strings.Builder
+	for _, path := range paths {
+		builder.WriteString("// File: " + path + "\n")
+		builder.WriteString(sources[path].Content)
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
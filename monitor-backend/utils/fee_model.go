@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"ethereum-monitor/config"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// eip1559ElasticityMultiplier / eip1559BaseFeeChangeDenominator EIP-1559 参数：目标 gas 为上限的 1/2，每块 base fee 最多变化 1/8
+const (
+	eip1559ElasticityMultiplier     = 2
+	eip1559BaseFeeChangeDenominator = 8
+)
+
+// BlockFeeStats 单个区块的 EIP-1559 费用统计
+// 有效小费 = min(maxPriorityFee, maxFee - baseFee)，legacy 交易为 gasPrice - baseFee；
+// 只有小费反映交易愿意为排序支付的溢价，直接比较 GasPrice 会被 base fee 淹没
+type BlockFeeStats struct {
+	BlockNumber uint64
+	BlockTime   time.Time
+	BaseFee     *big.Int
+	NextBaseFee *big.Int // 按 EIP-1559 公式推算的下一区块 base fee
+	GasUsed     uint64
+	GasLimit    uint64
+	TxCount     int
+
+	// 有效小费分布（Wei）
+	TipP10 *big.Int
+	TipP25 *big.Int
+	TipP50 *big.Int
+	TipP75 *big.Int
+	TipP90 *big.Int
+	// TipFence 离群上界：P75 + k × IQR，不低于 FeeOutlierMinTipGwei
+	TipFence     *big.Int
+	OutlierCount int
+
+	// RollingBaseFee 最近 FeeRollingWindowBlocks 个区块的平均 base fee（由 FeeModel 填充）
+	RollingBaseFee *big.Int
+}
+
+// EffectiveTip 交易在给定 base fee 下的有效小费，base fee 为 nil（伦敦升级前）时即 GasPrice
+func EffectiveTip(tx *types.Transaction, baseFee *big.Int) *big.Int {
+	tip, err := tx.EffectiveGasTip(baseFee)
+	if err != nil {
+		// maxFee 低于 base fee 的交易不可能被打包，这里按 0 处理
+		return new(big.Int)
+	}
+	return tip
+}
+
+// ComputeBlockFeeStats 统计区块的 base fee 与有效小费分布
+func ComputeBlockFeeStats(block *types.Block) *BlockFeeStats {
+	baseFee := block.BaseFee()
+	if baseFee == nil {
+		baseFee = new(big.Int)
+	}
+
+	tips := make([]*big.Int, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		tips = append(tips, EffectiveTip(tx, baseFee))
+	}
+	sort.Slice(tips, func(i, j int) bool { return tips[i].Cmp(tips[j]) < 0 })
+
+	stats := &BlockFeeStats{
+		BlockNumber: block.NumberU64(),
+		BlockTime:   time.Unix(int64(block.Time()), 0),
+		BaseFee:     baseFee,
+		NextBaseFee: nextBaseFee(baseFee, block.GasUsed(), block.GasLimit()),
+		GasUsed:     block.GasUsed(),
+		GasLimit:    block.GasLimit(),
+		TxCount:     len(tips),
+		TipP10:      percentile(tips, 10),
+		TipP25:      percentile(tips, 25),
+		TipP50:      percentile(tips, 50),
+		TipP75:      percentile(tips, 75),
+		TipP90:      percentile(tips, 90),
+	}
+
+	// Tukey 上界：P75 + k × (P75 - P25)
+	iqr := new(big.Float).SetInt(new(big.Int).Sub(stats.TipP75, stats.TipP25))
+	spread, _ := iqr.Mul(iqr, big.NewFloat(config.FeeOutlierIQRMultiplier)).Int(nil)
+	stats.TipFence = new(big.Int).Add(stats.TipP75, spread)
+	if minFence := gweiToWei(config.FeeOutlierMinTipGwei); stats.TipFence.Cmp(minFence) < 0 {
+		stats.TipFence = minFence
+	}
+
+	for _, tip := range tips {
+		if stats.IsTipOutlier(tip) {
+			stats.OutlierCount++
+		}
+	}
+	return stats
+}
+
+// IsTipOutlier 有效小费是否超过区块离群上界
+func (s *BlockFeeStats) IsTipOutlier(tip *big.Int) bool {
+	return tip.Cmp(s.TipFence) > 0
+}
+
+// percentile 已排序切片的最近秩百分位，空切片返回 0
+func percentile(sorted []*big.Int, p int) *big.Int {
+	if len(sorted) == 0 {
+		return new(big.Int)
+	}
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 × n)
+	if rank < 1 {
+		rank = 1
+	}
+	return new(big.Int).Set(sorted[rank-1])
+}
+
+// nextBaseFee 按 EIP-1559 公式推算下一区块的 base fee
+func nextBaseFee(baseFee *big.Int, gasUsed, gasLimit uint64) *big.Int {
+	target := gasLimit / eip1559ElasticityMultiplier
+	if baseFee.Sign() == 0 || target == 0 || gasUsed == target {
+		return new(big.Int).Set(baseFee)
+	}
+
+	if gasUsed > target {
+		delta := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(gasUsed-target))
+		delta.Div(delta, new(big.Int).SetUint64(target))
+		delta.Div(delta, big.NewInt(eip1559BaseFeeChangeDenominator))
+		if delta.Sign() == 0 {
+			delta.SetInt64(1)
+		}
+		return delta.Add(delta, baseFee)
+	}
+
+	delta := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(target-gasUsed))
+	delta.Div(delta, new(big.Int).SetUint64(target))
+	delta.Div(delta, big.NewInt(eip1559BaseFeeChangeDenominator))
+	return delta.Sub(baseFee, delta)
+}
+
+// WeiToGweiFloat 将 Wei 转换为 Gwei 浮点数，nil 返回 0
+func WeiToGweiFloat(wei *big.Int) float64 {
+	if wei == nil {
+		return 0
+	}
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e9)).Float64()
+	return gwei
+}
+
+// gweiToWei 将 Gwei 转换为 Wei
+func gweiToWei(gwei int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(gwei), big.NewInt(1e9))
+}
+
+// FeeModel 滚动 base fee 模型：保留最近 FeeRollingWindowBlocks 个区块的 base fee
+type FeeModel struct {
+	mu       sync.Mutex
+	baseFees map[uint64]*big.Int
+}
+
+// NewFeeModel 创建滚动 base fee 模型
+func NewFeeModel() *FeeModel {
+	return &FeeModel{baseFees: make(map[uint64]*big.Int)}
+}
+
+// Add 记录区块 base fee（启动时用历史数据预热），重组时同一区块号会被覆盖
+func (m *FeeModel) Add(blockNumber uint64, baseFee *big.Int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.baseFees[blockNumber] = baseFee
+}
+
+// Observe 记录区块 base fee，并为 stats 填充截至该区块的滚动平均值
+func (m *FeeModel) Observe(stats *BlockFeeStats) {
+	m.Add(stats.BlockNumber, stats.BaseFee)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sum, count := new(big.Int), int64(0)
+	for number, baseFee := range m.baseFees {
+		if number+config.FeeRollingWindowBlocks <= stats.BlockNumber {
+			delete(m.baseFees, number)
+			continue
+		}
+		if number > stats.BlockNumber {
+			continue
+		}
+		sum.Add(sum, baseFee)
+		count++
+	}
+	stats.RollingBaseFee = sum.Div(sum, big.NewInt(count))
+}
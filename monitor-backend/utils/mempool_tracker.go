@@ -0,0 +1,344 @@
+package utils
+
+import (
+	"context"
+	"ethereum-monitor/config"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethclient/gethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// MempoolTracker 公开内存池监听器
+// 通过 WebSocket 订阅 pending 交易，记录看到过的交易哈希（完整交易订阅时同时记录发送方与 nonce），
+// 区块中的交易若在监听覆盖期内从未出现过，即为经私有通道（Bundle / 防夹 RPC）直接提交给 Builder 的交易。
+// 哈希保留到交易上链（上链后再保留 MempoolIncludedTTL 供后续分析查询），同一发送方相同或更高 nonce 的交易上链后，
+// 被替换或失效的交易随之清理。单个节点看到的内存池并不完整，结论只作为辅助信号
+type MempoolTracker struct {
+	wsURL string
+
+	mu   sync.RWMutex
+	seen map[common.Hash]*mempoolEntry
+	// bySender 发送方 -> 未上链交易哈希 -> nonce，用于清理被替换的交易（完整交易订阅时才有）
+	bySender map[common.Address]map[common.Hash]uint64
+	// coveredSince 当前这次订阅建立的时间，订阅断开时清零；断开期间出块的交易无法判断
+	coveredSince time.Time
+	// pendingAtStart 覆盖开始时节点交易池中各发送方 pending / queued 的最大 nonce，
+	// 这些 nonce 的交易在覆盖前就已广播，订阅不一定看得到；为 nil 表示节点不支持 txpool_content，无法排除
+	pendingAtStart map[common.Address]uint64
+
+	// hashOnly 节点不支持完整交易订阅时置位，之后只订阅哈希
+	hashOnly bool
+}
+
+// mempoolEntry 看到过的 pending 交易
+type mempoolEntry struct {
+	seenAt     time.Time
+	includedAt time.Time // 上链时间，零值表示仍未上链
+}
+
+// txpoolTx txpool_content 返回的交易（只取需要的字段）
+type txpoolTx struct {
+	Hash  common.Hash    `json:"hash"`
+	From  common.Address `json:"from"`
+	Nonce hexutil.Uint64 `json:"nonce"`
+}
+
+var (
+	mempoolTrackerMu      sync.RWMutex
+	defaultMempoolTracker *MempoolTracker
+)
+
+// NewMempoolTracker 创建公开内存池监听器
+func NewMempoolTracker(wsURL string) *MempoolTracker {
+	return &MempoolTracker{
+		wsURL:    wsURL,
+		seen:     make(map[common.Hash]*mempoolEntry),
+		bySender: make(map[common.Address]map[common.Hash]uint64),
+	}
+}
+
+// StartMempoolTracker 启动全局内存池监听器，断线后自动重连，ctx 取消时退出
+func StartMempoolTracker(ctx context.Context, wsURL string) *MempoolTracker {
+	tracker := NewMempoolTracker(wsURL)
+	mempoolTrackerMu.Lock()
+	defaultMempoolTracker = tracker
+	mempoolTrackerMu.Unlock()
+
+	go tracker.Run(ctx)
+	return tracker
+}
+
+// GetMempoolTracker 获取全局内存池监听器，未启动时返回 nil
+func GetMempoolTracker() *MempoolTracker {
+	mempoolTrackerMu.RLock()
+	defer mempoolTrackerMu.RUnlock()
+	return defaultMempoolTracker
+}
+
+// Run 持续订阅 pending 交易，断开后按 MempoolReconnectDelay 重连
+func (t *MempoolTracker) Run(ctx context.Context) {
+	pruneTicker := time.NewTicker(time.Minute)
+	defer pruneTicker.Stop()
+
+	for {
+		err := t.subscribe(ctx, pruneTicker.C)
+		t.setCoveredSince(time.Time{})
+		if ctx.Err() != nil {
+			return
+		}
+		if Logger != nil {
+			Logger.Warn("内存池订阅中断，稍后重连", zap.Duration("delay", config.MempoolReconnectDelay), zap.Error(err))
+		}
+
+		select {
+		case <-time.After(config.MempoolReconnectDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// subscribe 建立一次订阅并持续接收 pending 交易与新区块，直到订阅出错或 ctx 取消
+func (t *MempoolTracker) subscribe(ctx context.Context, prune <-chan time.Time) error {
+	rpcClient, err := rpc.DialContext(ctx, t.wsURL)
+	if err != nil {
+		return err
+	}
+	defer rpcClient.Close()
+	client := ethclient.NewClient(rpcClient)
+
+	// 优先订阅完整交易（可得到发送方与 nonce），节点不支持时退回只订阅哈希
+	fullTxs := make(chan *types.Transaction, 1024)
+	hashes := make(chan common.Hash, 1024)
+	var sub *rpc.ClientSubscription
+	if !t.hashOnly {
+		sub, err = gethclient.New(rpcClient).SubscribeFullPendingTransactions(ctx, fullTxs)
+		if err != nil {
+			t.useHashOnly(err)
+		}
+	}
+	if t.hashOnly {
+		sub, err = gethclient.New(rpcClient).SubscribePendingTransactions(ctx, hashes)
+		if err != nil {
+			return err
+		}
+	}
+	defer sub.Unsubscribe()
+
+	heads := make(chan *types.Header, 16)
+	headSub, err := client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return err
+	}
+	defer headSub.Unsubscribe()
+
+	t.setCoveredSince(time.Now())
+	t.snapshotTxpool(ctx, rpcClient)
+	if Logger != nil {
+		Logger.Info("✅ 内存池订阅成功，开始记录 pending 交易", zap.Bool("full_transactions", !t.hashOnly))
+	}
+
+	received := false
+	for {
+		select {
+		case tx := <-fullTxs:
+			received = true
+			from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+			t.addPending(tx.Hash(), from, tx.Nonce(), err == nil)
+		case hash := <-hashes:
+			t.addPending(hash, common.Address{}, 0, false)
+		case header := <-heads:
+			block, err := client.BlockByHash(ctx, header.Hash())
+			if err != nil {
+				if Logger != nil {
+					Logger.Warn("获取新区块失败，本区块交易暂不清理", zap.Uint64("block", header.Number.Uint64()), zap.Error(err))
+				}
+				continue
+			}
+			t.markIncluded(block)
+		case <-prune:
+			t.prune()
+		case err := <-sub.Err():
+			// 完整交易订阅在收到第一笔交易前就出错，多半是节点按哈希推送导致解码失败
+			if !t.hashOnly && !received {
+				t.useHashOnly(err)
+			}
+			return err
+		case err := <-headSub.Err():
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// useHashOnly 节点不支持完整交易订阅，之后只订阅哈希
+func (t *MempoolTracker) useHashOnly(err error) {
+	t.hashOnly = true
+	if Logger != nil {
+		Logger.Warn("节点不支持完整 pending 交易订阅，改为只订阅哈希（被替换的交易只能按最长保留时间清理）", zap.Error(err))
+	}
+}
+
+// snapshotTxpool 记录覆盖开始时节点交易池中已有的交易：它们是公开交易，且所属 nonce 在覆盖前就已 pending
+func (t *MempoolTracker) snapshotTxpool(ctx context.Context, rpcClient *rpc.Client) {
+	var content map[string]map[string]map[string]txpoolTx
+	if err := rpcClient.CallContext(ctx, &content, "txpool_content"); err != nil {
+		t.mu.Lock()
+		t.pendingAtStart = nil
+		t.mu.Unlock()
+		if Logger != nil {
+			Logger.Warn("节点不支持 txpool_content，无法排除覆盖前已 pending 的交易，私有订单流暂不判断", zap.Error(err))
+		}
+		return
+	}
+
+	pendingAtStart := make(map[common.Address]uint64)
+	for _, senders := range content {
+		for _, txs := range senders {
+			for _, tx := range txs {
+				nonce := uint64(tx.Nonce)
+				if current, ok := pendingAtStart[tx.From]; !ok || nonce > current {
+					pendingAtStart[tx.From] = nonce
+				}
+				t.addPending(tx.Hash, tx.From, nonce, true)
+			}
+		}
+	}
+
+	t.mu.Lock()
+	t.pendingAtStart = pendingAtStart
+	t.mu.Unlock()
+}
+
+// addPending 记录一笔 pending 交易，hasSender 为 false 时只记录哈希
+func (t *MempoolTracker) addPending(hash common.Hash, from common.Address, nonce uint64, hasSender bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.seen[hash]; ok {
+		return
+	}
+	t.seen[hash] = &mempoolEntry{seenAt: time.Now()}
+	if !hasSender {
+		return
+	}
+	if t.bySender[from] == nil {
+		t.bySender[from] = make(map[common.Hash]uint64)
+	}
+	t.bySender[from][hash] = nonce
+}
+
+// markIncluded 标记区块中的交易已上链，并清理同一发送方被相同或更高 nonce 取代的未上链交易
+func (t *MempoolTracker) markIncluded(block *types.Block) {
+	type included struct {
+		hash  common.Hash
+		from  common.Address
+		nonce uint64
+		ok    bool
+	}
+	txs := block.Transactions()
+	list := make([]included, len(txs))
+	for i, tx := range txs {
+		from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+		list[i] = included{hash: tx.Hash(), from: from, nonce: tx.Nonce(), ok: err == nil}
+	}
+
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, tx := range list {
+		if entry, ok := t.seen[tx.hash]; ok && entry.includedAt.IsZero() {
+			entry.includedAt = now
+		}
+		if !tx.ok {
+			continue
+		}
+		pending := t.bySender[tx.from]
+		for hash, nonce := range pending {
+			if hash != tx.hash && nonce <= tx.nonce {
+				delete(t.seen, hash)
+			}
+			if nonce <= tx.nonce {
+				delete(pending, hash)
+			}
+		}
+		if len(pending) == 0 {
+			delete(t.bySender, tx.from)
+		}
+	}
+}
+
+// setCoveredSince 更新覆盖起始时间
+func (t *MempoolTracker) setCoveredSince(since time.Time) {
+	t.mu.Lock()
+	t.coveredSince = since
+	t.mu.Unlock()
+}
+
+// prune 清理上链超过 MempoolIncludedTTL、或一直未上链超过 MempoolPendingMaxAge 的哈希
+func (t *MempoolTracker) prune() {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for hash, entry := range t.seen {
+		if !entry.includedAt.IsZero() && now.Sub(entry.includedAt) > config.MempoolIncludedTTL ||
+			now.Sub(entry.seenAt) > config.MempoolPendingMaxAge {
+			delete(t.seen, hash)
+		}
+	}
+	for from, pending := range t.bySender {
+		for hash := range pending {
+			if _, ok := t.seen[hash]; !ok {
+				delete(pending, hash)
+			}
+		}
+		if len(pending) == 0 {
+			delete(t.bySender, from)
+		}
+	}
+}
+
+// Covers 判断该时间出块的这笔交易能否做私有订单流判断：
+// 订阅在线且已过预热期，区块仍在哈希保留期内，且发送方的该 nonce 在覆盖开始前不在交易池中
+// （覆盖前就已广播的交易订阅不一定看得到，节点不支持 txpool_content 时一律不判断）
+func (t *MempoolTracker) Covers(tx *types.Transaction, blockTime time.Time) bool {
+	if t == nil {
+		return false
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return false
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.coveredSince.IsZero() || blockTime.Before(t.coveredSince.Add(config.MempoolWarmup)) {
+		return false
+	}
+	if time.Since(blockTime) >= config.MempoolIncludedTTL {
+		return false
+	}
+	if t.pendingAtStart == nil {
+		return false
+	}
+	maxNonce, ok := t.pendingAtStart[from]
+	return !ok || tx.Nonce() > maxNonce
+}
+
+// Seen 交易是否在公开内存池中出现过
+func (t *MempoolTracker) Seen(hash common.Hash) bool {
+	if t == nil {
+		return false
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	_, ok := t.seen[hash]
+	return ok
+}
@@ -2,9 +2,11 @@ package utils
 
 import (
 	"context"
+	"ethereum-monitor/config"
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -14,7 +16,21 @@ import (
 
 // MevDetector MEV 攻击检测器
 type MevDetector struct {
-	client *ethclient.Client
+	client    *ethclient.Client
+	sandwich  *SandwichDetector
+	orderflow *PrivateOrderflowDetector
+	labels    AddressLabeler // 已知 MEV Bot / Builder 地址，为 nil 时只按地址模式识别
+
+	// sandwichCache 按区块哈希缓存整块的三明治识别结果，同一区块的多笔监控转账只分析一次
+	sandwichMu    sync.Mutex
+	sandwichCache map[common.Hash]*sandwichCacheEntry
+}
+
+// sandwichCacheEntry 单个区块的三明治识别结果，once 保证并发请求同一区块时只分析一次
+type sandwichCacheEntry struct {
+	once    sync.Once
+	attacks []*SandwichAttack
+	err     error
 }
 
 // MevType MEV 攻击类型
@@ -26,25 +42,68 @@ const (
 	MevTypeBackRun     MevType = "尾随交易"
 	MevTypeLiquidation MevType = "清算攻击"
 	MevTypeHighGas     MevType = "异常高Gas"
+	MevTypeKnownBot    MevType = "已知MEV Bot"
+	MevTypeBundle      MevType = "私有Bundle"
 	MevTypeNone        MevType = "正常交易"
 )
 
+// MEV 信号名称（对应 config.MevSignalWeights 的键）
+const (
+	MevSignalKnownBot          = "known_bot"
+	MevSignalBotPattern        = "bot_pattern"
+	MevSignalSandwich          = "sandwich"
+	MevSignalFrontRun          = "front_run"
+	MevSignalHighGas           = "high_gas"
+	MevSignalFailedHighGas     = "failed_high_gas"
+	MevSignalInternalTransfers = "internal_transfers"
+	MevSignalCoinbaseTip       = "coinbase_tip"
+	MevSignalPrivateOrderflow  = "private_orderflow"
+)
+
+// SandwichSignalLikelihood 交易是三明治前置/后置交易时的 MEV 可能性
+const SandwichSignalLikelihood = 0.9
+
+// MevSignal 单条 MEV 证据
+type MevSignal struct {
+	Name       string  // MevSignal*
+	Type       MevType // 信号指向的 MEV 类型，辅助信号为空
+	Likelihood float64 // 该信号单独成立时是 MEV 的可能性 (0-1)
+	Weight     float64 // 信号权重（config.MevSignalWeights）
+}
+
 // MevDetectionResult MEV 检测结果
+// 各项检查只追加信号，最后按 noisy-OR 合并为综合置信度，达到阈值判定为 MEV
 type MevDetectionResult struct {
-	IsMev       bool     // 是否是 MEV 攻击
-	MevType     MevType  // MEV 类型
-	Confidence  float64  // 置信度 (0-1)
-	Description string   // 详细描述
-	Evidence    []string // 证据列表
+	IsMev       bool        // 综合置信度是否达到阈值
+	MevType     MevType     // 贡献最大的信号对应的 MEV 类型
+	Confidence  float64     // 综合置信度 (0-1)
+	Description string      // 详细描述
+	Evidence    []string    // 证据列表
+	Signals     []MevSignal // 参与合并的信号
+
+	// 交易所在区块中识别到的三明治（交易是攻击方或受害方时非空）
+	Sandwich     *SandwichAttack
+	SandwichRole string // SandwichRole*
+
+	// 私有订单流：MempoolCovered 为 false 时（未开启内存池监听或不在覆盖期内）PrivateOrderflow 无意义
+	MempoolCovered   bool
+	PrivateOrderflow bool     // 打包前从未在公开内存池出现
+	CoinbaseTipWei   *big.Int // 交易内直接支付给区块 coinbase 的小费，没有时为 nil
 }
 
-// NewMevDetector 创建 MEV 检测器
-func NewMevDetector(rpcUrl string) (*MevDetector, error) {
-	client, err := ethclient.Dial(rpcUrl)
+// NewMevDetector 创建 MEV 检测器，labels 用于识别已知 MEV Bot / Builder 地址（可为 nil）
+func NewMevDetector(rpcUrl string, labels AddressLabeler) (*MevDetector, error) {
+	client, err := DialRateLimitedEthClient(rpcUrl)
 	if err != nil {
 		return nil, err
 	}
-	return &MevDetector{client: client}, nil
+	return &MevDetector{
+		client:        client,
+		sandwich:      NewSandwichDetector(client),
+		orderflow:     NewPrivateOrderflowDetector(client),
+		labels:        labels,
+		sandwichCache: make(map[common.Hash]*sandwichCacheEntry),
+	}, nil
 }
 
 // DetectMev 检测交易是否为 MEV 攻击
@@ -75,17 +134,70 @@ func (m *MevDetector) DetectMev(txHash string) (*MevDetectionResult, error) {
 		Evidence: []string{},
 	}
 
+	// 区块只拉取一次，供小费分布、三明治与抢跑检测共用
+	block, err := m.client.BlockByNumber(context.Background(), receipt.BlockNumber)
+	if err != nil {
+		block = nil
+	}
+
 	// 检测各种 MEV 特征
 	m.checkKnownMevBots(tx, result) // 优先检查已知 Bot
-	m.checkHighGasPrice(tx, result)
-	m.checkSandwichAttack(tx, receipt, result)
-	m.checkFrontRunning(tx, receipt, result)
+	if block != nil {
+		fees := ComputeBlockFeeStats(block)
+		m.checkHighGasPrice(tx, fees, result)
+		m.checkSandwichAttack(tx, block, result)
+		m.checkFrontRunning(tx, receipt, block, fees, result)
+		m.checkPrivateOrderflow(tx, block, result)
+	}
 	m.checkInternalTransfers(receipt, result) // 检查内部转账
 	m.checkFailedButExecuted(receipt, result) // 检查失败但执行的交易
 
+	result.finalize(config.GetMevConfidenceThreshold())
 	return result, nil
 }
 
+// addSignal 追加一条证据信号
+func (r *MevDetectionResult) addSignal(name string, mevType MevType, likelihood float64, evidence string) {
+	r.Signals = append(r.Signals, MevSignal{Name: name, Type: mevType, Likelihood: likelihood, Weight: mevSignalWeight(name)})
+	r.Evidence = append(r.Evidence, evidence)
+}
+
+// mevSignalWeight 信号权重，未配置时为 1
+func mevSignalWeight(name string) float64 {
+	weight, ok := config.MevSignalWeights[name]
+	if !ok {
+		return 1
+	}
+	return weight
+}
+
+// MevSignalConfidence 单个信号的置信度（权重 × 可能性），供只有一条证据的告警使用
+func MevSignalConfidence(name string, likelihood float64) float64 {
+	return mevSignalWeight(name) * likelihood
+}
+
+// finalize 按 noisy-OR 合并信号：score = 1 - Π(1 - weight × likelihood)，
+// 独立信号相互加强，弱信号不会拉低强信号的结论
+func (r *MevDetectionResult) finalize(threshold float64) {
+	notMev := 1.0
+	strongest := 0.0
+	for _, signal := range r.Signals {
+		contribution := signal.Weight * signal.Likelihood
+		notMev *= 1 - contribution
+		if signal.Type != "" && contribution > strongest {
+			strongest = contribution
+			r.MevType = signal.Type
+		}
+	}
+
+	r.Confidence = 1 - notMev
+	r.IsMev = r.Confidence >= threshold
+	if !r.IsMev {
+		r.MevType = MevTypeNone
+	}
+	r.Description = fmt.Sprintf("综合置信度 %.2f（阈值 %.2f，信号 %d 条）", r.Confidence, threshold, len(r.Signals))
+}
+
 func (m *MevDetector) getReceiptWithRetry(hash common.Hash) (*types.Receipt, error) {
 	const maxAttempts = 3
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
@@ -109,126 +221,128 @@ func (m *MevDetector) getReceiptWithRetry(hash common.Hash) (*types.Receipt, err
 	return nil, fmt.Errorf("GetTransactionReceipt failed after retries")
 }
 
-// checkHighGasPrice 检测异常高的 Gas Price
-func (m *MevDetector) checkHighGasPrice(tx *types.Transaction, result *MevDetectionResult) {
-	gasPrice := tx.GasPrice()
-	if gasPrice == nil {
+// checkHighGasPrice 检测有效小费是否为区块内的离群值
+// 只比较有效小费：type-2 交易的 GasPrice 是 maxFee 上限，包含随区块波动的 base fee
+func (m *MevDetector) checkHighGasPrice(tx *types.Transaction, fees *BlockFeeStats, result *MevDetectionResult) {
+	tip := EffectiveTip(tx, fees.BaseFee)
+	if !fees.IsTipOutlier(tip) {
 		return
 	}
-
-	// 如果 Gas Price 超过 500 Gwei，可能是 MEV
-	highGasThreshold := big.NewInt(500000000000) // 500 Gwei
-	if gasPrice.Cmp(highGasThreshold) > 0 {
-		result.IsMev = true
-		result.MevType = MevTypeHighGas
-		result.Confidence = 0.6
-		result.Evidence = append(result.Evidence,
-			"Gas Price 异常高: "+weiToGwei(gasPrice)+" Gwei")
-	}
+	result.addSignal(MevSignalHighGas, MevTypeHighGas, 0.6,
+		fmt.Sprintf("有效小费 %s Gwei 为区块离群值（中位数 %s Gwei，P90 %s Gwei，离群上界 %s Gwei）",
+			weiToGwei(tip), weiToGwei(fees.TipP50), weiToGwei(fees.TipP90), weiToGwei(fees.TipFence)))
 }
 
 // checkSandwichAttack 检测三明治攻击
-func (m *MevDetector) checkSandwichAttack(tx *types.Transaction, receipt *types.Receipt, result *MevDetectionResult) {
-	// 三明治攻击特征：
-	// 1. 在同一个区块内
-	// 2. 有相同的交易对
-	// 3. 前后各有一笔交易来自同一地址
-
-	blockNumber := receipt.BlockNumber
-	block, err := m.client.BlockByNumber(context.Background(), blockNumber)
+// 解码整个区块的 V2/V3 Swap 事件，交易是前置/后置交易时判定为 MEV；
+// 交易是受害者时只记录三明治信息，不判定为 MEV
+func (m *MevDetector) checkSandwichAttack(tx *types.Transaction, block *types.Block, result *MevDetectionResult) {
+	attacks, err := m.blockSandwiches(context.Background(), block)
 	if err != nil {
 		return
 	}
 
-	txIndex := receipt.TransactionIndex
-	transactions := block.Transactions()
+	for _, attack := range attacks {
+		role := attack.Role(tx.Hash())
+		if role == "" {
+			continue
+		}
+		result.Sandwich = attack
+		result.SandwichRole = role
 
-	if txIndex == 0 || int(txIndex) >= len(transactions)-1 {
+		summary := fmt.Sprintf("池子 %s (%s -> %s)，受害交易 %d 笔，攻击者毛利润 %s (%s)，受害者损失合计 %s (%s)",
+			attack.Pool.Hex(), attack.TokenIn.Hex(), attack.TokenOut.Hex(), len(attack.Victims),
+			attack.GrossProfit.String(), attack.TokenIn.Hex(), attack.TotalVictimLoss().String(), attack.TokenOut.Hex())
+
+		if role == SandwichRoleVictim {
+			result.Evidence = append(result.Evidence, "交易被三明治攻击，攻击者 "+attack.Attacker.Hex()+": "+summary)
+			return
+		}
+
+		position := "前置"
+		if role == SandwichRoleBackRun {
+			position = "后置"
+		}
+		result.addSignal(MevSignalSandwich, MevTypeSandwich, SandwichSignalLikelihood, "三明治攻击"+position+"交易: "+summary)
 		return
 	}
+}
+
+// blockSandwiches 识别区块中的三明治攻击（按区块哈希缓存，失败的结果不缓存）
+func (m *MevDetector) blockSandwiches(ctx context.Context, block *types.Block) ([]*SandwichAttack, error) {
+	hash := block.Hash()
 
-	// 检查前一笔和后一笔交易
-	prevTx := transactions[txIndex-1]
-	nextTx := transactions[txIndex+1]
-
-	prevFrom, _ := types.Sender(types.LatestSignerForChainID(prevTx.ChainId()), prevTx)
-	nextFrom, _ := types.Sender(types.LatestSignerForChainID(nextTx.ChainId()), nextTx)
-
-	// 如果前后交易来自同一地址，且都与目标交易交互相同合约
-	if prevFrom == nextFrom && prevTx.To() != nil && nextTx.To() != nil && tx.To() != nil {
-		if *prevTx.To() == *tx.To() && *nextTx.To() == *tx.To() {
-			result.IsMev = true
-			result.MevType = MevTypeSandwich
-			result.Confidence = 0.8
-			result.Evidence = append(result.Evidence,
-				"检测到三明治攻击模式: 前后交易来自同一地址 "+prevFrom.Hex())
+	m.sandwichMu.Lock()
+	entry, ok := m.sandwichCache[hash]
+	if !ok {
+		if len(m.sandwichCache) >= config.MevSandwichCacheBlocks {
+			// 缓存满时整体清空，监控转账集中在最新的几个区块
+			m.sandwichCache = make(map[common.Hash]*sandwichCacheEntry)
 		}
+		entry = &sandwichCacheEntry{}
+		m.sandwichCache[hash] = entry
 	}
+	m.sandwichMu.Unlock()
+
+	entry.once.Do(func() {
+		entry.attacks, entry.err = m.sandwich.DetectBlock(ctx, block)
+	})
+	if entry.err != nil {
+		m.sandwichMu.Lock()
+		if m.sandwichCache[hash] == entry {
+			delete(m.sandwichCache, hash)
+		}
+		m.sandwichMu.Unlock()
+	}
+	return entry.attacks, entry.err
 }
 
 // checkFrontRunning 检测抢跑交易
-func (m *MevDetector) checkFrontRunning(tx *types.Transaction, receipt *types.Receipt, result *MevDetectionResult) {
-	// 抢跑特征：
-	// 1. Gas Price 明显高于平均值
-	// 2. 在目标交易之前执行
-	// 3. 与目标交易交互相同合约
-
-	blockNumber := receipt.BlockNumber
-	block, err := m.client.BlockByNumber(context.Background(), blockNumber)
-	if err != nil {
+// 抢跑特征：有效小费为区块离群值，且同一区块中排在其后的交易调用了相同合约、小费更低
+func (m *MevDetector) checkFrontRunning(tx *types.Transaction, receipt *types.Receipt, block *types.Block, fees *BlockFeeStats, result *MevDetectionResult) {
+	if tx.To() == nil {
 		return
 	}
-
-	txIndex := receipt.TransactionIndex
-	if txIndex == 0 {
+	tip := EffectiveTip(tx, fees.BaseFee)
+	if !fees.IsTipOutlier(tip) {
 		return
 	}
 
-	// 计算区块内平均 Gas Price
-	var totalGasPrice big.Int
-	txCount := 0
-	for _, blockTx := range block.Transactions() {
-		if blockTx.GasPrice() != nil {
-			totalGasPrice.Add(&totalGasPrice, blockTx.GasPrice())
-			txCount++
+	txs := block.Transactions()
+	for i := int(receipt.TransactionIndex) + 1; i < len(txs); i++ {
+		later := txs[i]
+		if later.To() == nil || *later.To() != *tx.To() {
+			continue
+		}
+		if EffectiveTip(later, fees.BaseFee).Cmp(tip) < 0 {
+			result.addSignal(MevSignalFrontRun, MevTypeFrontRun, 0.7,
+				fmt.Sprintf("有效小费 %s Gwei 为区块离群值，且排在同样调用 %s 的交易 %s 之前",
+					weiToGwei(tip), tx.To().Hex(), later.Hash().Hex()))
+			return
 		}
 	}
+}
 
-	if txCount == 0 {
-		return
-	}
-
-	avgGasPrice := new(big.Int).Div(&totalGasPrice, big.NewInt(int64(txCount)))
-	txGasPrice := tx.GasPrice()
-
-	if txGasPrice == nil {
-		return
+// checkPrivateOrderflow 检测私有订单流与直接支付给 Builder 的小费
+// 防夹 RPC 的普通用户同样不经公开内存池，私有订单流只作辅助信号；coinbase 小费是 Bundle 的典型特征
+func (m *MevDetector) checkPrivateOrderflow(tx *types.Transaction, block *types.Block, result *MevDetectionResult) {
+	inspection := m.orderflow.Inspect(context.Background(), tx, block.Header())
+	result.MempoolCovered = inspection.MempoolCovered
+	result.PrivateOrderflow = inspection.PrivateOrderflow
+	result.CoinbaseTipWei = inspection.CoinbaseTipWei
+
+	if inspection.CoinbaseTipWei != nil {
+		result.addSignal(MevSignalCoinbaseTip, MevTypeBundle, 0.7,
+			"交易内直接向区块 coinbase 支付 "+weiToEther(inspection.CoinbaseTipWei)+" ETH 小费（Bundle 特征）")
 	}
-
-	// 如果 Gas Price 是平均值的 2 倍以上
-	threshold := new(big.Int).Mul(avgGasPrice, big.NewInt(2))
-	if txGasPrice.Cmp(threshold) > 0 {
-		result.IsMev = true
-		result.MevType = MevTypeFrontRun
-		result.Confidence = 0.7
-		result.Evidence = append(result.Evidence,
-			"Gas Price 是区块平均值的 2 倍以上")
+	if inspection.PrivateOrderflow {
+		result.addSignal(MevSignalPrivateOrderflow, "", 0.5,
+			"交易打包前未在公开内存池出现（私有订单流，辅助信号）")
 	}
 }
 
-// checkKnownMevBots 检测已知的 MEV Bot 地址
+// checkKnownMevBots 检测已知的 MEV Bot / Builder 地址（地址标签注册表）
 func (m *MevDetector) checkKnownMevBots(tx *types.Transaction, result *MevDetectionResult) {
-	// 已知的 MEV Bot 地址列表（更新和扩展）
-	knownMevBots := map[string]string{
-		"0xa69babef1ca67a37ffaf7a485dfff3382056e78c": "Flashbots",
-		"0x00000000000007736e2f9af06b8f5f3b6d0e8f13": "MEV Bot",
-		"0x000000000000084e91743124a982076c59f10084": "Sandwich Bot",
-		"0xd2269f890854a8c5f03e8ea091e3d5a2e0e0f890": "MEV Bot",
-		"0x6b75d8af000000e20b7a7ddf000ba900b4009a80": "MEV Bot",
-		"0x51c72848c68a965f66fa7a88855f9f7784502a7f": "jaredfromsubway.eth",
-		"0x00000000003b3cc22af3ae1eac0440bcee416b40": "MEV Bot",
-	}
-
 	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
 	if err != nil {
 		return
@@ -237,36 +351,29 @@ func (m *MevDetector) checkKnownMevBots(tx *types.Transaction, result *MevDetect
 	fromAddr := strings.ToLower(from.Hex())
 
 	// 检查完整地址匹配
-	for botAddr, botName := range knownMevBots {
-		if strings.ToLower(botAddr) == fromAddr {
-			result.IsMev = true
-			result.Confidence = 0.95
-			result.Evidence = append(result.Evidence,
-				"交易来自已知 MEV Bot: "+botName)
+	if m.labels != nil {
+		if name, ok := m.labels.MevLabelName(fromAddr); ok {
+			result.addSignal(MevSignalKnownBot, MevTypeKnownBot, 0.95,
+				"交易来自已知 MEV Bot: "+name)
 			return
 		}
 	}
 
 	// 检查地址前缀模式（MEV Bot 常用模式）
-	if strings.HasPrefix(fromAddr, "0x000000000000") ||
-		strings.HasPrefix(fromAddr, "0x00000000") {
-		result.IsMev = true
-		result.Confidence = 0.75
-		result.Evidence = append(result.Evidence,
-			"地址符合 MEV Bot 特征模式（前缀为多个0）")
+	for _, pattern := range config.MevBotAddressPatterns {
+		if strings.HasPrefix(fromAddr, pattern) {
+			result.addSignal(MevSignalBotPattern, MevTypeKnownBot, 0.75,
+				"地址符合 MEV Bot 特征模式（前缀为多个0）")
+			break
+		}
 	}
 
 	// 检查 to 地址
-	if tx.To() != nil {
-		toAddr := strings.ToLower(tx.To().Hex())
-		for botAddr, botName := range knownMevBots {
-			if strings.ToLower(botAddr) == toAddr {
-				result.IsMev = true
-				result.Confidence = 0.9
-				result.Evidence = append(result.Evidence,
-					"交易发送到已知 MEV Bot: "+botName)
-				return
-			}
+	if tx.To() != nil && m.labels != nil {
+		if name, ok := m.labels.MevLabelName(tx.To().Hex()); ok {
+			result.addSignal(MevSignalKnownBot, MevTypeKnownBot, 0.9,
+				"交易发送到已知 MEV Bot: "+name)
+			return
 		}
 	}
 }
@@ -279,6 +386,12 @@ func weiToGwei(wei *big.Int) string {
 	return result.Text('f', 2)
 }
 
+// weiToEther 将 Wei 转换为 ETH
+func weiToEther(wei *big.Int) string {
+	eth := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18))
+	return eth.Text('f', 6)
+}
+
 // Close 关闭客户端连接
 func (m *MevDetector) Close() {
 	m.client.Close()
@@ -297,15 +410,11 @@ func (m *MevDetector) checkInternalTransfers(receipt *types.Receipt, result *Mev
 		}
 	}
 
-	// 如果有 3 次以上的 Transfer 事件，可能是三明治攻击
+	// 普通路由兑换也会产生多次 Transfer，只作为辅助证据，不单独判定为 MEV
+	// 三明治由 checkSandwichAttack 基于 Swap 事件判定
 	if transferCount >= 3 {
-		result.IsMev = true
-		if result.MevType == MevTypeNone {
-			result.MevType = MevTypeSandwich
-		}
-		result.Confidence = 0.7
-		result.Evidence = append(result.Evidence,
-			fmt.Sprintf("检测到 %d 次 Transfer 事件，符合 MEV 攻击模式", transferCount))
+		result.addSignal(MevSignalInternalTransfers, "", 0.3,
+			fmt.Sprintf("检测到 %d 次 Transfer 事件（辅助信号）", transferCount))
 	}
 }
 
@@ -316,9 +425,7 @@ func (m *MevDetector) checkFailedButExecuted(receipt *types.Receipt, result *Mev
 		gasUsed := receipt.GasUsed
 		// 如果消耗了超过 100,000 Gas 但失败了，可能是 MEV 尝试
 		if gasUsed > 100000 {
-			result.IsMev = true
-			result.Confidence = 0.65
-			result.Evidence = append(result.Evidence,
+			result.addSignal(MevSignalFailedHighGas, "", 0.65,
 				fmt.Sprintf("交易失败但消耗了 %d Gas，可能是 MEV 攻击尝试", gasUsed))
 		}
 	}
@@ -8,7 +8,7 @@ import (
 // ExampleUsage MEV 检测器使用示例
 func ExampleUsage() {
 	// 创建 MEV 检测器
-	detector, err := NewMevDetector("https://eth.llamarpc.com")
+	detector, err := NewMevDetector("https://eth.llamarpc.com", nil)
 	if err != nil {
 		log.Fatal("创建检测器失败:", err)
 	}
@@ -35,6 +35,20 @@ func ExampleUsage() {
 		}
 	}
 
+	if len(result.Signals) > 0 {
+		fmt.Println("\n信号:")
+		for _, signal := range result.Signals {
+			fmt.Printf("  - %s: 可能性 %.2f × 权重 %.2f\n", signal.Name, signal.Likelihood, signal.Weight)
+		}
+	}
+
+	if result.MempoolCovered {
+		fmt.Printf("\n私有订单流: %v\n", result.PrivateOrderflow)
+	}
+	if result.CoinbaseTipWei != nil {
+		fmt.Printf("Builder 小费: %s ETH\n", weiToEther(result.CoinbaseTipWei))
+	}
+
 	if result.Description != "" {
 		fmt.Printf("\n描述: %s\n", result.Description)
 	}
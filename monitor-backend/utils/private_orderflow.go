@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// OrderflowInspection 单笔交易的订单流检查结果
+type OrderflowInspection struct {
+	MempoolCovered   bool     // 内存池监听覆盖该区块，PrivateOrderflow 结论有效
+	PrivateOrderflow bool     // 覆盖期内从未在公开内存池出现
+	CoinbaseTipWei   *big.Int // 交易内直接转给区块 coinbase 的 ETH（Builder 小费），没有时为 nil
+}
+
+// callFrame callTracer 返回的调用帧
+type callFrame struct {
+	Type  string       `json:"type"`
+	From  string       `json:"from"`
+	To    string       `json:"to"`
+	Value *hexutil.Big `json:"value"`
+	Error string       `json:"error"`
+	Calls []callFrame  `json:"calls"`
+}
+
+// PrivateOrderflowDetector 私有订单流检测器
+// 对照公开内存池监听结果判断交易是否经私有通道提交，并通过 callTracer 识别交易内直接支付给 coinbase 的小费；
+// 节点不支持 debug_traceTransaction 时只检查顶层转账
+type PrivateOrderflowDetector struct {
+	client *ethclient.Client
+
+	// traceUnsupported 节点不支持 debug_traceTransaction 时置位，之后只检查顶层转账
+	traceUnsupported atomic.Bool
+}
+
+// NewPrivateOrderflowDetector 创建私有订单流检测器
+func NewPrivateOrderflowDetector(client *ethclient.Client) *PrivateOrderflowDetector {
+	return &PrivateOrderflowDetector{client: client}
+}
+
+// Inspect 检查区块中的一笔交易
+func (d *PrivateOrderflowDetector) Inspect(ctx context.Context, tx *types.Transaction, header *types.Header) *OrderflowInspection {
+	inspection := &OrderflowInspection{}
+
+	tracker := GetMempoolTracker()
+	if tracker.Covers(tx, time.Unix(int64(header.Time), 0)) {
+		inspection.MempoolCovered = true
+		inspection.PrivateOrderflow = !tracker.Seen(tx.Hash())
+	}
+
+	inspection.CoinbaseTipWei = d.coinbaseTip(ctx, tx, header.Coinbase)
+	return inspection
+}
+
+// coinbaseTip 汇总交易内转给 coinbase 的 ETH（含内部调用与自毁），没有时返回 nil
+func (d *PrivateOrderflowDetector) coinbaseTip(ctx context.Context, tx *types.Transaction, coinbase common.Address) *big.Int {
+	tip := new(big.Int)
+	traced := false
+
+	if !d.traceUnsupported.Load() {
+		var frame callFrame
+		err := d.client.Client().CallContext(ctx, &frame, "debug_traceTransaction",
+			tx.Hash(), map[string]string{"tracer": "callTracer"})
+		switch {
+		case err == nil:
+			sumCoinbaseTransfers(frame, strings.ToLower(coinbase.Hex()), tip)
+			traced = true
+		case IsMethodUnsupported(err):
+			d.traceUnsupported.Store(true)
+			if Logger != nil {
+				Logger.Warn("节点不支持 debug_traceTransaction，coinbase 小费只检查顶层转账", zap.Error(err))
+			}
+		}
+	}
+
+	// 回退：只能识别直接转给 coinbase 的顶层交易
+	if !traced && tx.To() != nil && *tx.To() == coinbase && tx.Value().Sign() > 0 {
+		tip.Set(tx.Value())
+	}
+
+	if tip.Sign() == 0 {
+		return nil
+	}
+	return tip
+}
+
+// sumCoinbaseTransfers 递归累加转给 coinbase 的调用帧金额，失败的帧及其子调用都已回滚
+func sumCoinbaseTransfers(frame callFrame, coinbase string, total *big.Int) {
+	if frame.Error != "" {
+		return
+	}
+	if strings.EqualFold(frame.To, coinbase) && frame.Value != nil && frame.Type != "DELEGATECALL" {
+		total.Add(total, frame.Value.ToInt())
+	}
+	for _, call := range frame.Calls {
+		sumCoinbaseTransfers(call, coinbase, total)
+	}
+}
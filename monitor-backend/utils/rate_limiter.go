@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"ethereum-monitor/config"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RateLimiter 令牌桶限速器
+type RateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+
+	waits     int64         // 需要等待的请求数
+	totalWait time.Duration // 累计等待时长
+	requests  int64
+}
+
+// RateLimiterStat 限速器统计
+type RateLimiterStat struct {
+	Name        string  `json:"name"`
+	PerSecond   float64 `json:"per_second"`
+	Burst       int     `json:"burst"`
+	Available   float64 `json:"available"` // 当前可用令牌
+	Requests    int64   `json:"requests"`
+	Waits       int64   `json:"waits"`
+	TotalWaitMs int64   `json:"total_wait_ms"`
+}
+
+var rateLimiters = newRateLimiters()
+
+// newRateLimiters 按配置创建各外部依赖的限速器
+func newRateLimiters() map[string]*RateLimiter {
+	limiters := make(map[string]*RateLimiter, len(config.RateLimits))
+	for name, limit := range config.RateLimits {
+		limiters[name] = NewRateLimiter(limit.PerSecond, limit.Burst)
+	}
+	return limiters
+}
+
+// NewRateLimiter 创建令牌桶，初始为满桶
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		perSecond: perSecond,
+		burst:     float64(burst),
+		tokens:    float64(burst),
+		last:      time.Now(),
+	}
+}
+
+// GetRateLimiter 获取指定外部依赖的限速器，未配置时返回 nil（Wait 不限速）
+func GetRateLimiter(name string) *RateLimiter {
+	return rateLimiters[name]
+}
+
+// RateLimiterStats 获取所有限速器的统计
+func RateLimiterStats() []RateLimiterStat {
+	stats := make([]RateLimiterStat, 0, len(rateLimiters))
+	for name, l := range rateLimiters {
+		stats = append(stats, l.stat(name))
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// Wait 阻塞直到取得一个令牌
+func (l *RateLimiter) Wait() {
+	if l == nil || l.perSecond <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.refill(now)
+	l.tokens--
+	l.requests++
+	var delay time.Duration
+	if l.tokens < 0 {
+		// 预占令牌，按欠缺量计算等待时间，后来者排在后面
+		delay = time.Duration(-l.tokens / l.perSecond * float64(time.Second))
+		l.waits++
+		l.totalWait += delay
+	}
+	l.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// refill 按时间补充令牌，调用方需持有锁
+func (l *RateLimiter) refill(now time.Time) {
+	l.tokens += now.Sub(l.last).Seconds() * l.perSecond
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+func (l *RateLimiter) stat(name string) RateLimiterStat {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	return RateLimiterStat{
+		Name:        name,
+		PerSecond:   l.perSecond,
+		Burst:       int(l.burst),
+		Available:   l.tokens,
+		Requests:    l.requests,
+		Waits:       l.waits,
+		TotalWaitMs: l.totalWait.Milliseconds(),
+	}
+}
+
+// rateLimitedTransport 每个请求发出前先取令牌
+// 底层使用发送时的 http.DefaultTransport，以便 SetGlobalProxy 之后创建的请求也走代理
+type rateLimitedTransport struct {
+	limiter *RateLimiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.limiter.Wait()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// DialRateLimitedRPC 连接 RPC 节点，HTTP 节点的请求受 RPC 限速器约束
+func DialRateLimitedRPC(rpcURL string) (*rpc.Client, error) {
+	if !strings.HasPrefix(rpcURL, "http://") && !strings.HasPrefix(rpcURL, "https://") {
+		return rpc.Dial(rpcURL)
+	}
+	httpClient := &http.Client{
+		Transport: &rateLimitedTransport{limiter: GetRateLimiter(config.RateLimiterRPC)},
+	}
+	return rpc.DialOptions(context.Background(), rpcURL, rpc.WithHTTPClient(httpClient))
+}
+
+// DialRateLimitedEthClient 创建受 RPC 限速器约束的 ethclient
+func DialRateLimitedEthClient(rpcURL string) (*ethclient.Client, error) {
+	rpcClient, err := DialRateLimitedRPC(rpcURL)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(rpcClient), nil
+}
+
+// IsMethodUnsupported 判断 RPC 错误是否表示节点不支持该方法
+func IsMethodUnsupported(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32601 {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "does not exist") || strings.Contains(msg, "not supported") || strings.Contains(msg, "not available")
+}
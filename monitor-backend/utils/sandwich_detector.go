@@ -0,0 +1,491 @@
+package utils
+
+import (
+	"context"
+	"ethereum-monitor/config"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// 兑换协议
+const (
+	SwapProtocolUniswapV2 = "uniswap_v2"
+	SwapProtocolUniswapV3 = "uniswap_v3"
+)
+
+// 交易在三明治中的角色
+const (
+	SandwichRoleFrontRun = "front_run"
+	SandwichRoleBackRun  = "back_run"
+	SandwichRoleVictim   = "victim"
+)
+
+// PoolSwap 解码后的单次池子兑换（V2/V3 Swap 事件）
+type PoolSwap struct {
+	TxHash   common.Hash
+	TxIndex  uint
+	LogIndex uint
+	From     common.Address // 交易发起者
+	To       common.Address // 交易调用的合约
+
+	Pool       common.Address
+	Protocol   string
+	ZeroForOne bool // true: token0 换 token1
+	AmountIn   *big.Int
+	AmountOut  *big.Int
+
+	// V2 兑换前的池子储备（由紧邻的 Sync 事件反推），V3 或未知时为 nil
+	ReserveIn  *big.Int
+	ReserveOut *big.Int
+}
+
+// SandwichVictim 三明治受害交易
+type SandwichVictim struct {
+	TxHash    common.Hash
+	Address   common.Address // 受害交易发起者
+	AmountIn  *big.Int
+	AmountOut *big.Int
+	Loss      *big.Int // 少换到的输出代币数量（估算）
+}
+
+// SandwichAttack 三明治攻击
+type SandwichAttack struct {
+	BlockNumber uint64
+	Pool        common.Address
+	Protocol    string
+	Token0      common.Address
+	Token1      common.Address
+
+	Attacker         common.Address // 前置交易发起者
+	AttackerContract common.Address // 前置交易调用的合约（攻击机器人）
+	FrontRunTx       common.Hash
+	BackRunTx        common.Hash
+
+	// 受害者用 TokenIn 换 TokenOut，与前置交易方向一致
+	TokenIn  common.Address
+	TokenOut common.Address
+	Victims  []SandwichVictim
+
+	// 攻击者利润：后置交易换回的 TokenIn 减去前置交易投入的 TokenIn
+	GrossProfit  *big.Int
+	GasCostWei   *big.Int // 前后两笔交易的 gas 费
+	NetProfitWei *big.Int // TokenIn 为 WETH 时扣除 gas 后的净利润，否则为 nil
+
+	zeroForOne bool // 前置交易方向
+}
+
+// Role 返回交易在该三明治中的角色，无关时返回空
+func (a *SandwichAttack) Role(txHash common.Hash) string {
+	switch txHash {
+	case a.FrontRunTx:
+		return SandwichRoleFrontRun
+	case a.BackRunTx:
+		return SandwichRoleBackRun
+	}
+	for _, v := range a.Victims {
+		if v.TxHash == txHash {
+			return SandwichRoleVictim
+		}
+	}
+	return ""
+}
+
+// TotalVictimLoss 全部受害者的损失合计（TokenOut）
+func (a *SandwichAttack) TotalVictimLoss() *big.Int {
+	total := new(big.Int)
+	for _, v := range a.Victims {
+		total.Add(total, v.Loss)
+	}
+	return total
+}
+
+// SandwichDetector 基于 Swap 事件解码的三明治检测器
+// 解码整个区块的 Uniswap V2/V3 Swap 事件，在同一池子上寻找同一攻击者方向相反的前置/后置交易，
+// 两者之间与前置交易同方向的其他交易即为受害者（不要求前后紧邻）
+type SandwichDetector struct {
+	client *ethclient.Client
+
+	poolMu     sync.Mutex
+	poolTokens map[common.Address][2]common.Address // 池子 token0/token1 缓存
+}
+
+// NewSandwichDetector 创建三明治检测器（复用调用方的客户端，不负责关闭）
+func NewSandwichDetector(client *ethclient.Client) *SandwichDetector {
+	return &SandwichDetector{
+		client:     client,
+		poolTokens: make(map[common.Address][2]common.Address),
+	}
+}
+
+// DetectBlock 检测区块内的全部三明治攻击
+func (d *SandwichDetector) DetectBlock(ctx context.Context, block *types.Block) ([]*SandwichAttack, error) {
+	swaps, err := d.BlockSwaps(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.completeSandwiches(ctx, block.NumberU64(), FindSandwiches(swaps)), nil
+}
+
+// completeSandwiches 补充区块号、池子代币与 gas 费，读不到池子代币的攻击跳过（不影响区块内其他结果）
+func (d *SandwichDetector) completeSandwiches(ctx context.Context, blockNumber uint64, attacks []*SandwichAttack) []*SandwichAttack {
+	completed := attacks[:0]
+	for _, attack := range attacks {
+		attack.BlockNumber = blockNumber
+		if err := d.fillTokens(ctx, attack); err != nil {
+			if Logger != nil {
+				Logger.Warn("读取池子代币失败，跳过该三明治",
+					zap.Uint64("block", blockNumber),
+					zap.String("pool", attack.Pool.Hex()),
+					zap.Error(err))
+			}
+			continue
+		}
+		d.fillGasCost(ctx, attack)
+		completed = append(completed, attack)
+	}
+	return completed
+}
+
+// BlockSwaps 解码区块内全部 V2/V3 Swap 事件，按日志顺序返回
+func (d *SandwichDetector) BlockSwaps(ctx context.Context, block *types.Block) ([]*PoolSwap, error) {
+	blockHash := block.Hash()
+	logs, err := d.client.FilterLogs(ctx, ethereum.FilterQuery{
+		BlockHash: &blockHash,
+		Topics: [][]common.Hash{{
+			common.HexToHash(config.UniswapV2SwapTopic),
+			common.HexToHash(config.UniswapV3SwapTopic),
+			common.HexToHash(config.UniswapV2SyncTopic),
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter swap logs: %w", err)
+	}
+
+	txs := make(map[common.Hash]*types.Transaction, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		txs[tx.Hash()] = tx
+	}
+
+	return DecodePoolSwaps(logs, txs), nil
+}
+
+// DecodePoolSwaps 解码 Swap 日志（需包含 V2 Sync 日志用于反推储备），txs 用于补充交易发起者
+func DecodePoolSwaps(logs []types.Log, txs map[common.Hash]*types.Transaction) []*PoolSwap {
+	v2Swap := common.HexToHash(config.UniswapV2SwapTopic)
+	v3Swap := common.HexToHash(config.UniswapV3SwapTopic)
+	v2Sync := common.HexToHash(config.UniswapV2SyncTopic)
+
+	// V2 池子在 Swap 之前发出 Sync（兑换后的储备）
+	lastSync := make(map[common.Address][2]*big.Int)
+	var swaps []*PoolSwap
+	for i := range logs {
+		l := &logs[i]
+		if len(l.Topics) == 0 || l.Removed {
+			continue
+		}
+
+		var swap *PoolSwap
+		switch l.Topics[0] {
+		case v2Sync:
+			if len(l.Data) >= 64 {
+				lastSync[l.Address] = [2]*big.Int{
+					new(big.Int).SetBytes(l.Data[0:32]),
+					new(big.Int).SetBytes(l.Data[32:64]),
+				}
+			}
+			continue
+		case v2Swap:
+			swap = decodeV2Swap(l, lastSync[l.Address])
+		case v3Swap:
+			swap = decodeV3Swap(l)
+		}
+		if swap == nil {
+			continue
+		}
+
+		swap.TxHash = l.TxHash
+		swap.TxIndex = l.TxIndex
+		swap.LogIndex = l.Index
+		swap.Pool = l.Address
+		swap.From, swap.To = txSenderAndTarget(txs[l.TxHash])
+		swaps = append(swaps, swap)
+	}
+	return swaps
+}
+
+// decodeV2Swap 解码 V2 Swap(sender, amount0In, amount1In, amount0Out, amount1Out, to)
+func decodeV2Swap(l *types.Log, postReserves [2]*big.Int) *PoolSwap {
+	if len(l.Data) < 128 {
+		return nil
+	}
+	amount0In := new(big.Int).SetBytes(l.Data[0:32])
+	amount1In := new(big.Int).SetBytes(l.Data[32:64])
+	amount0Out := new(big.Int).SetBytes(l.Data[64:96])
+	amount1Out := new(big.Int).SetBytes(l.Data[96:128])
+
+	swap := &PoolSwap{Protocol: SwapProtocolUniswapV2}
+	inIdx, outIdx := 0, 1
+	switch {
+	case amount0In.Sign() > 0 && amount1Out.Sign() > 0:
+		swap.ZeroForOne, swap.AmountIn, swap.AmountOut = true, amount0In, amount1Out
+	case amount1In.Sign() > 0 && amount0Out.Sign() > 0:
+		swap.ZeroForOne, swap.AmountIn, swap.AmountOut = false, amount1In, amount0Out
+		inIdx, outIdx = 1, 0
+	default:
+		return nil // 闪电贷归还等非常规兑换
+	}
+
+	// 兑换前储备 = 兑换后储备 - 投入 / + 换出
+	if postReserves[0] != nil {
+		swap.ReserveIn = new(big.Int).Sub(postReserves[inIdx], swap.AmountIn)
+		swap.ReserveOut = new(big.Int).Add(postReserves[outIdx], swap.AmountOut)
+		if swap.ReserveIn.Sign() <= 0 {
+			swap.ReserveIn, swap.ReserveOut = nil, nil
+		}
+	}
+	return swap
+}
+
+// decodeV3Swap 解码 V3 Swap(sender, recipient, amount0, amount1, ...)，正数为流入池子
+func decodeV3Swap(l *types.Log) *PoolSwap {
+	if len(l.Data) < 64 {
+		return nil
+	}
+	amount0 := signedInt256(l.Data[0:32])
+	amount1 := signedInt256(l.Data[32:64])
+
+	swap := &PoolSwap{Protocol: SwapProtocolUniswapV3}
+	switch {
+	case amount0.Sign() > 0 && amount1.Sign() < 0:
+		swap.ZeroForOne, swap.AmountIn, swap.AmountOut = true, amount0, new(big.Int).Neg(amount1)
+	case amount1.Sign() > 0 && amount0.Sign() < 0:
+		swap.ZeroForOne, swap.AmountIn, swap.AmountOut = false, amount1, new(big.Int).Neg(amount0)
+	default:
+		return nil
+	}
+	return swap
+}
+
+// signedInt256 解析补码表示的 int256
+func signedInt256(word []byte) *big.Int {
+	value := new(big.Int).SetBytes(word)
+	if len(word) == 32 && word[0]&0x80 != 0 {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+	return value
+}
+
+// FindSandwiches 在解码后的兑换中寻找三明治：同一池子上，同一攻击者先买后卖（方向相反），
+// 中间夹着与前置交易同方向的其他交易；每笔交易最多参与一次
+func FindSandwiches(swaps []*PoolSwap) []*SandwichAttack {
+	byPool := make(map[common.Address][]*PoolSwap)
+	var pools []common.Address
+	for _, s := range swaps {
+		if _, ok := byPool[s.Pool]; !ok {
+			pools = append(pools, s.Pool)
+		}
+		byPool[s.Pool] = append(byPool[s.Pool], s)
+	}
+
+	var attacks []*SandwichAttack
+	for _, pool := range pools {
+		poolSwaps := byPool[pool]
+		used := make(map[common.Hash]bool)
+
+		for i, front := range poolSwaps {
+			if used[front.TxHash] {
+				continue
+			}
+			for k := i + 1; k < len(poolSwaps); k++ {
+				back := poolSwaps[k]
+				if back.TxIndex <= front.TxIndex || used[back.TxHash] {
+					continue
+				}
+				if back.ZeroForOne == front.ZeroForOne || !sameSandwichActor(front, back) || !backRunMatches(front, back) {
+					continue
+				}
+
+				var victims []*PoolSwap
+				for _, v := range poolSwaps[i+1 : k] {
+					if v.TxIndex > front.TxIndex && v.TxIndex < back.TxIndex &&
+						v.ZeroForOne == front.ZeroForOne && !sameSandwichActor(front, v) && !used[v.TxHash] {
+						victims = append(victims, v)
+					}
+				}
+				if len(victims) == 0 {
+					continue
+				}
+
+				attacks = append(attacks, buildSandwich(front, back, victims))
+				used[front.TxHash], used[back.TxHash] = true, true
+				for _, v := range victims {
+					used[v.TxHash] = true
+				}
+				break
+			}
+		}
+	}
+
+	sort.Slice(attacks, func(i, j int) bool { return attacks[i].FrontRunTx.Hex() < attacks[j].FrontRunTx.Hex() })
+	return attacks
+}
+
+// sameSandwichActor 同一发起者，或调用同一个非公共路由合约（攻击者常用多个 EOA 调用同一机器人合约）
+func sameSandwichActor(a, b *PoolSwap) bool {
+	if a.From != (common.Address{}) && a.From == b.From {
+		return true
+	}
+	if a.To == (common.Address{}) || a.To != b.To {
+		return false
+	}
+	_, isRouter := config.KnownSwapRouters[strings.ToLower(a.To.Hex())]
+	return !isRouter
+}
+
+// backRunMatches 后置交易卖出的数量应与前置交易买到的数量相近
+func backRunMatches(front, back *PoolSwap) bool {
+	bought := new(big.Float).SetInt(front.AmountOut)
+	sold := new(big.Float).SetInt(back.AmountIn)
+	lower := new(big.Float).Mul(bought, big.NewFloat(1-config.SandwichBackRunTolerance))
+	upper := new(big.Float).Mul(bought, big.NewFloat(1+config.SandwichBackRunTolerance))
+	return sold.Cmp(lower) >= 0 && sold.Cmp(upper) <= 0
+}
+
+// buildSandwich 计算攻击者利润与受害者损失
+func buildSandwich(front, back *PoolSwap, victims []*PoolSwap) *SandwichAttack {
+	attack := &SandwichAttack{
+		Pool:             front.Pool,
+		Protocol:         front.Protocol,
+		Attacker:         front.From,
+		AttackerContract: front.To,
+		FrontRunTx:       front.TxHash,
+		BackRunTx:        back.TxHash,
+		GrossProfit:      new(big.Int).Sub(back.AmountOut, front.AmountIn),
+		zeroForOne:       front.ZeroForOne,
+	}
+
+	totalVictimIn := new(big.Int)
+	for _, v := range victims {
+		totalVictimIn.Add(totalVictimIn, v.AmountIn)
+	}
+
+	for _, v := range victims {
+		victim := SandwichVictim{
+			TxHash:    v.TxHash,
+			Address:   v.From,
+			AmountIn:  v.AmountIn,
+			AmountOut: v.AmountOut,
+			Loss:      new(big.Int),
+		}
+
+		if v.ReserveIn != nil && front.Protocol == SwapProtocolUniswapV2 {
+			// V2：去掉前置交易对储备的影响，按恒定乘积重新计算受害者本应换到的数量
+			reserveIn := new(big.Int).Sub(v.ReserveIn, front.AmountIn)
+			reserveOut := new(big.Int).Add(v.ReserveOut, front.AmountOut)
+			if reserveIn.Sign() > 0 {
+				expected := uniswapV2AmountOut(v.AmountIn, reserveIn, reserveOut)
+				if expected.Cmp(v.AmountOut) > 0 {
+					victim.Loss.Sub(expected, v.AmountOut)
+				}
+			}
+		} else if attack.GrossProfit.Sign() > 0 && totalVictimIn.Sign() > 0 {
+			// V3：按投入占比分摊攻击者毛利润，再按受害者成交价折算为输出代币
+			victim.Loss.Mul(attack.GrossProfit, v.AmountOut)
+			victim.Loss.Quo(victim.Loss, totalVictimIn)
+		}
+
+		attack.Victims = append(attack.Victims, victim)
+	}
+	return attack
+}
+
+// uniswapV2AmountOut 恒定乘积公式（含手续费）计算换出数量
+func uniswapV2AmountOut(amountIn, reserveIn, reserveOut *big.Int) *big.Int {
+	amountInWithFee := new(big.Int).Mul(amountIn, big.NewInt(10000-config.UniswapV2FeeBps))
+	numerator := new(big.Int).Mul(amountInWithFee, reserveOut)
+	denominator := new(big.Int).Add(new(big.Int).Mul(reserveIn, big.NewInt(10000)), amountInWithFee)
+	return numerator.Quo(numerator, denominator)
+}
+
+// fillTokens 补充池子代币与兑换方向对应的代币
+func (d *SandwichDetector) fillTokens(ctx context.Context, attack *SandwichAttack) error {
+	tokens, err := d.PoolTokens(ctx, attack.Pool)
+	if err != nil {
+		return err
+	}
+	attack.Token0, attack.Token1 = tokens[0], tokens[1]
+
+	// 受害者方向与前置交易一致
+	if attack.zeroForOne {
+		attack.TokenIn, attack.TokenOut = tokens[0], tokens[1]
+	} else {
+		attack.TokenIn, attack.TokenOut = tokens[1], tokens[0]
+	}
+	return nil
+}
+
+// PoolTokens 读取池子的 token0/token1（带缓存）
+func (d *SandwichDetector) PoolTokens(ctx context.Context, pool common.Address) ([2]common.Address, error) {
+	d.poolMu.Lock()
+	tokens, ok := d.poolTokens[pool]
+	d.poolMu.Unlock()
+	if ok {
+		return tokens, nil
+	}
+
+	for i, sig := range []string{"token0()", "token1()"} {
+		result, err := d.client.CallContract(ctx, ethereum.CallMsg{
+			To:   &pool,
+			Data: crypto.Keccak256([]byte(sig))[:4],
+		}, nil)
+		if err != nil || len(result) < 32 {
+			return tokens, fmt.Errorf("failed to read %s of pool %s: %v", sig, pool.Hex(), err)
+		}
+		tokens[i] = common.BytesToAddress(result[:32])
+	}
+
+	d.poolMu.Lock()
+	d.poolTokens[pool] = tokens
+	d.poolMu.Unlock()
+	return tokens, nil
+}
+
+// fillGasCost 计算前后交易的 gas 费；利润代币为 WETH 时计算净利润
+func (d *SandwichDetector) fillGasCost(ctx context.Context, attack *SandwichAttack) {
+	gasCost := new(big.Int)
+	for _, hash := range []common.Hash{attack.FrontRunTx, attack.BackRunTx} {
+		cost, err := txGasCost(ctx, d.client, hash)
+		if err != nil {
+			return
+		}
+		gasCost.Add(gasCost, cost)
+	}
+	attack.GasCostWei = gasCost
+
+	if attack.TokenIn == common.HexToAddress(config.WETHAddress) {
+		attack.NetProfitWei = new(big.Int).Sub(attack.GrossProfit, gasCost)
+	}
+}
+
+// txGasCost 交易实际支付的 gas 费（gasUsed * effectiveGasPrice）
+func txGasCost(ctx context.Context, client *ethclient.Client, hash common.Hash) (*big.Int, error) {
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.EffectiveGasPrice == nil {
+		return nil, fmt.Errorf("receipt of %s has no effective gas price", hash.Hex())
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice), nil
+}
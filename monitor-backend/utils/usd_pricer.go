@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"context"
+	"ethereum-monitor/config"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// latestAnswerSelector Chainlink 聚合器 latestAnswer() 选择器
+var latestAnswerSelector = crypto.Keccak256([]byte("latestAnswer()"))[:4]
+
+// UsdPricer 链上 USD 估值：稳定币按 1 美元，WETH 按 Chainlink ETH/USD 价格，其他代币无法直接估值
+type UsdPricer struct {
+	client *ethclient.Client
+}
+
+// NewUsdPricer 创建 USD 估值器
+func NewUsdPricer(client *ethclient.Client) *UsdPricer {
+	return &UsdPricer{client: client}
+}
+
+// EthPriceUSD 查询指定区块的 ETH/USD 价格，blockNumber 为 nil 时查询最新区块
+func (p *UsdPricer) EthPriceUSD(ctx context.Context, blockNumber *big.Int) (float64, error) {
+	feed := common.HexToAddress(config.ChainlinkEthUsdFeed)
+	data, err := p.client.CallContract(ctx, ethereum.CallMsg{To: &feed, Data: latestAnswerSelector}, blockNumber)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call eth/usd feed: %w", err)
+	}
+	if len(data) < 32 {
+		return 0, fmt.Errorf("unexpected eth/usd feed response")
+	}
+	answer := new(big.Int).SetBytes(data[:32])
+	price, _ := new(big.Float).Quo(new(big.Float).SetInt(answer), big.NewFloat(1e8)).Float64()
+	return price, nil
+}
+
+// TokenValueUSD 估算代币数量的 USD 价值，无法估值时 ok 为 false
+func (p *UsdPricer) TokenValueUSD(ctx context.Context, token common.Address, amount *big.Int, blockNumber *big.Int) (value float64, ok bool) {
+	if amount == nil {
+		return 0, false
+	}
+	addr := strings.ToLower(token.Hex())
+
+	if decimals, isStable := config.KnownStablecoins[addr]; isStable {
+		return scaleAmount(amount, decimals), true
+	}
+
+	if addr == strings.ToLower(config.WETHAddress) {
+		price, err := p.EthPriceUSD(ctx, blockNumber)
+		if err != nil {
+			return 0, false
+		}
+		return scaleAmount(amount, 18) * price, true
+	}
+	return 0, false
+}
+
+// SandwichVictimLossUSD 估算三明治受害者损失的 USD 价值
+// 损失以少换到的 TokenOut 计；TokenOut 无法估值时按受害交易自身成交价折算为 TokenIn 再估值
+func (p *UsdPricer) SandwichVictimLossUSD(ctx context.Context, attack *SandwichAttack, victim SandwichVictim) (float64, bool) {
+	if victim.Loss == nil || victim.Loss.Sign() <= 0 {
+		return 0, false
+	}
+	blockNumber := new(big.Int).SetUint64(attack.BlockNumber)
+
+	if value, ok := p.TokenValueUSD(ctx, attack.TokenOut, victim.Loss, blockNumber); ok {
+		return value, true
+	}
+
+	if victim.AmountIn == nil || victim.AmountOut == nil || victim.AmountOut.Sign() == 0 {
+		return 0, false
+	}
+	lossIn := new(big.Int).Mul(victim.Loss, victim.AmountIn)
+	lossIn.Quo(lossIn, victim.AmountOut)
+	return p.TokenValueUSD(ctx, attack.TokenIn, lossIn, blockNumber)
+}
+
+// scaleAmount 按精度把最小单位换算为浮点数
+func scaleAmount(amount *big.Int, decimals int) float64 {
+	divisor := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	value, _ := new(big.Float).Quo(new(big.Float).SetInt(amount), divisor).Float64()
+	return value
+}
@@ -124,7 +124,7 @@ func (w *WechatNotifier) SendETHAlert(direction, from, to, amount, txHash string
 	return w.SendMarkdown(content)
 }
 
-// SendMEVDetection 发送 MEV 检测通知（仅记录，不告警）
+// SendMEVDetection 发送 MEV 检测通知，amount 为已格式化的金额（含单位）
 func (w *WechatNotifier) SendMEVDetection(mevType, from, to, amount, txHash string, confidence float64, evidence []string) error {
 	evidenceStr := ""
 	for i, e := range evidence {
@@ -136,7 +136,7 @@ func (w *WechatNotifier) SendMEVDetection(mevType, from, to, amount, txHash stri
 > **置信度**: %.0f%%
 > **发送方**: %s
 > **接收方**: %s
-> **金额**: %s USDT
+> **金额**: %s
 > **交易**: [查看详情](https://etherscan.io/tx/%s)
 > **证据**: %s
 > **时间**: %s`,
@@ -337,7 +337,7 @@ func (p *PushPlusNotifier) SendUSDTAlert(direction, from, to, amount, txHash str
 	return p.Send(title, content)
 }
 
-// SendMEVDetection 发送 MEV 检测通知
+// SendMEVDetection 发送 MEV 检测通知，amount 为已格式化的金额（含单位）
 func (p *PushPlusNotifier) SendMEVDetection(mevType, from, to, amount, txHash string, confidence float64, evidence []string) error {
 	evidenceStr := ""
 	for i, e := range evidence {
@@ -351,7 +351,7 @@ func (p *PushPlusNotifier) SendMEVDetection(mevType, from, to, amount, txHash st
 **置信度**: %.0f%%  
 **发送方**: %s  
 **接收方**: %s  
-**金额**: %s USDT  
+**金额**: %s  
 **交易**: [查看详情](https://etherscan.io/tx/%s)  
 **证据**: %s  
 **时间**: %s`,
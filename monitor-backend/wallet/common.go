@@ -1,6 +1,7 @@
 package wallet
 
 import (
+	"encoding/json"
 	"ethereum-monitor/database"
 	"ethereum-monitor/logger"
 	"ethereum-monitor/model"
@@ -54,20 +55,53 @@ func NewAddressManager(addresses map[string]string) *AddressManager {
 	}
 }
 
-// IsMonitored 检查地址是否被监控
+// IsMonitored 检查地址是否被监控：配置的监控地址，或整体监控的实体的成员地址
 func (am *AddressManager) IsMonitored(address common.Address) bool {
-	_, ok := am.addressSet[address]
-	return ok
+	if _, ok := am.addressSet[address]; ok {
+		return true
+	}
+	return database.GetAddressClusterRegistry().IsWatched(address.Hex())
 }
 
-// GetLabel 获取地址标签
+// GetLabel 获取地址标签，实体成员地址显示为 "实体名 (地址名称)"
 func (am *AddressManager) GetLabel(address common.Address) string {
 	if label, ok := am.addressLabels[address]; ok && label != "" {
 		return label
 	}
+	if cluster, ok := database.GetAddressClusterRegistry().Lookup(address.Hex()); ok {
+		name := database.GetAddressLabelRegistry().Name(address.Hex())
+		if name == "" {
+			name = address.Hex()
+		}
+		return fmt.Sprintf("%s (%s)", cluster.Name, name)
+	}
 	return address.Hex()
 }
 
+// DescribeCounterparty 对手方名称：优先使用监控地址标签，其次查询地址标签注册表与地址实体，未知时返回空
+func (am *AddressManager) DescribeCounterparty(address common.Address) string {
+	if label, ok := am.addressLabels[address]; ok && label != "" {
+		return label
+	}
+	if label, ok := database.GetAddressLabelRegistry().Lookup(address.Hex()); ok {
+		return fmt.Sprintf("%s [%s]", label.Name, label.Category)
+	}
+	if cluster, ok := database.GetAddressClusterRegistry().Lookup(address.Hex()); ok {
+		return fmt.Sprintf("%s [%s]", cluster.Name, cluster.Category)
+	}
+	return ""
+}
+
+// ClusterContext 监控地址所属实体，以及对手方是否属于同一实体（内部调拨 / 充值归集）
+func (am *AddressManager) ClusterContext(monitored, counterparty common.Address) (clusterID uint, clusterName string, intra bool) {
+	registry := database.GetAddressClusterRegistry()
+	cluster, ok := registry.Lookup(monitored.Hex())
+	if !ok {
+		return 0, "", false
+	}
+	return cluster.ID, cluster.Name, registry.SameCluster(monitored.Hex(), counterparty.Hex())
+}
+
 // GetLabelList 获取所有地址标签列表
 func (am *AddressManager) GetLabelList() []string {
 	labels := make([]string, 0, len(am.addressLabels))
@@ -106,15 +140,24 @@ func NewNotificationService() *NotificationService {
 // TransferNotification 转账通知信息
 // 包含转账交易的所有相关信息，用于发送通知和记录到数据库
 type TransferNotification struct {
-	Direction   string // 转账方向："转入" 或 "转出"（相对于监控地址）
-	Label       string // 监控地址的标签（如 "OKX钱包"）
-	From        string // 发送方地址（十六进制字符串）
-	To          string // 接收方地址（十六进制字符串）
-	Amount      string // 转账金额（已格式化的字符串，如 "100.50"）
-	Currency    string // 币种（如 "ETH", "USDT", "USDC"）
-	TxHash      string // 交易哈希（十六进制字符串）
-	BlockNum    int    // 区块号
-	ShouldAlert bool   // 是否需要发送告警通知（true: 大额交易，false: 只记录不通知）
+	Direction   string    // 转账方向："转入" 或 "转出"（相对于监控地址）
+	Label       string    // 监控地址的标签（如 "OKX钱包"）
+	From        string    // 发送方地址（十六进制字符串）
+	To          string    // 接收方地址（十六进制字符串）
+	Amount      string    // 转账金额（已格式化的字符串，如 "100.50"）
+	Currency    string    // 币种（如 "ETH", "USDT", "USDC"）
+	TxHash      string    // 交易哈希（十六进制字符串）
+	BlockNum    int       // 区块号
+	BlockTime   time.Time // 出块时间，未能获取时为零值
+	ShouldAlert bool      // 是否需要发送告警通知（true: 大额交易，false: 只记录不通知）
+
+	Counterparty string // 对手方名称（来自地址标签注册表，如 "Binance 14 [exchange]"），未知时为空
+
+	ClusterID    uint   // 监控地址所属实体，不属于任何实体时为 0
+	ClusterName  string // 实体名称
+	IntraCluster bool   // 对手方属于同一实体：只落库不通知，不计入实体净流量
+
+	Mev *utils.MevDetectionResult // MEV 检测结论（未检测时为 nil）；判定为 MEV 时只落库不通知
 }
 
 // SendTransferNotification 发送转账通知
@@ -122,14 +165,61 @@ func (ns *NotificationService) SendTransferNotification(notif *TransferNotificat
 	notifStatus := "success"
 	var errorMsg string
 
+	// MEV 交易不通知，但仍带着检测结论写入流水与通知记录
+	isMev := notif.Mev != nil && notif.Mev.IsMev
+	suppressed := isMev
+	var mevType string
+	var mevConfidence float64
+	var mevEvidence string
+	var mempoolCovered, privateOrderflow bool
+	var coinbaseTipWei string
+	if notif.Mev != nil {
+		mevType = string(notif.Mev.MevType)
+		mevConfidence = notif.Mev.Confidence
+		if evidence, err := json.Marshal(notif.Mev.Evidence); err == nil {
+			mevEvidence = string(evidence)
+		}
+		mempoolCovered = notif.Mev.MempoolCovered
+		privateOrderflow = notif.Mev.PrivateOrderflow
+		if notif.Mev.CoinbaseTipWei != nil {
+			coinbaseTipWei = notif.Mev.CoinbaseTipWei.String()
+		}
+	}
+	if isMev {
+		notifStatus = "mev_suppressed"
+	}
+	// 实体内部调拨不是真实的资金进出，同样只落库
+	if notif.IntraCluster && !isMev {
+		suppressed = true
+		notifStatus = "intra_cluster"
+	}
+
 	// 发送 PushPlus 通知
-	if ns.pushPlus != nil && notif.ShouldAlert {
+	if ns.pushPlus != nil && notif.ShouldAlert && !suppressed {
 		emoji := "📥"
 		if notif.Direction == "转出" {
 			emoji = "📤"
 		}
 
+		// 对手方名称附在对应地址后面
+		fromDisplay, toDisplay := notif.From, notif.To
+		if notif.Counterparty != "" {
+			if notif.Direction == "转出" {
+				toDisplay = fmt.Sprintf("%s (%s)", notif.To, notif.Counterparty)
+			} else {
+				fromDisplay = fmt.Sprintf("%s (%s)", notif.From, notif.Counterparty)
+			}
+		}
+
+		// 实体成员的转账按实体告警
 		title := fmt.Sprintf("%s %s %s", emoji, notif.Currency, notif.Direction)
+		labelDisplay := notif.Label
+		if notif.ClusterName != "" {
+			title = fmt.Sprintf("%s %s %s %s", emoji, notif.ClusterName, notif.Currency, notif.Direction)
+			if !strings.HasPrefix(notif.Label, notif.ClusterName) {
+				labelDisplay = fmt.Sprintf("%s [实体: %s]", notif.Label, notif.ClusterName)
+			}
+		}
 		content := fmt.Sprintf(`## 交易详情
 
 **监控地址**: %s  
@@ -141,13 +231,13 @@ func (ns *NotificationService) SendTransferNotification(notif *TransferNotificat
 **区块**: %d  
 **交易**: [查看详情](https://etherscan.io/tx/%s)  
 **时间**: %s`,
-			notif.Label,
+			labelDisplay,
 			notif.Currency,
 			notif.Amount,
 			notif.Currency,
 			notif.Direction,
-			notif.From,
-			notif.To,
+			fromDisplay,
+			toDisplay,
 			notif.BlockNum,
 			notif.TxHash,
 			time.Now().Format("2006-01-02 15:04:05"))
@@ -163,16 +253,31 @@ func (ns *NotificationService) SendTransferNotification(notif *TransferNotificat
 	// 1. 写入交易流水表（只要通知的数据都落库）
 	if ns.transferRepo != nil {
 		record := &model.TransferRecord{
-			MonitorLabel: notif.Label,
-			Direction:    notif.Direction,
-			FromAddress:  strings.ToLower(notif.From),
-			ToAddress:    strings.ToLower(notif.To),
-			Amount:       notif.Amount,
-			Currency:     notif.Currency,
-			TxHash:       strings.ToLower(notif.TxHash),
-			BlockNumber:  notif.BlockNum,
-			Notified:     true,
-			NotifyStatus: notifStatus,
+			MonitorLabel:   notif.Label,
+			Direction:      notif.Direction,
+			FromAddress:    strings.ToLower(notif.From),
+			ToAddress:      strings.ToLower(notif.To),
+			Amount:         notif.Amount,
+			Currency:       notif.Currency,
+			TxHash:         strings.ToLower(notif.TxHash),
+			BlockNumber:    notif.BlockNum,
+			BlockTime:      notif.BlockTime,
+			Counterparty:   notif.Counterparty,
+			ClusterID:      notif.ClusterID,
+			ClusterName:    notif.ClusterName,
+			IntraCluster:   notif.IntraCluster,
+			AboveThreshold: notif.ShouldAlert,
+			Notified:       !suppressed,
+			NotifyStatus:   notifStatus,
+
+			IsMev:         isMev,
+			MevType:       mevType,
+			MevConfidence: mevConfidence,
+			MevEvidence:   mevEvidence,
+
+			MempoolCovered:   mempoolCovered,
+			PrivateOrderflow: privateOrderflow,
+			CoinbaseTipWei:   coinbaseTipWei,
 		}
 		if err := ns.transferRepo.Create(record); err != nil {
 			logger.Error("保存交易流水失败", zap.Error(err))
@@ -187,6 +292,11 @@ func (ns *NotificationService) SendTransferNotification(notif *TransferNotificat
 			emoji = "📤"
 		}
 
+		content := fmt.Sprintf("%s %s %s: %s %s (%s)", emoji, notif.Currency, notif.Direction, notif.Amount, notif.Currency, notif.Label)
+		if notif.Counterparty != "" {
+			content += " 对手方: " + notif.Counterparty
+		}
+
 		notifLog := &model.WechatAlter{
 			Type:         fmt.Sprintf("%s_TRANSFER", notif.Currency),
 			Direction:    notif.Direction,
@@ -196,7 +306,9 @@ func (ns *NotificationService) SendTransferNotification(notif *TransferNotificat
 			Currency:     notif.Currency,
 			TxHash:       strings.ToLower(notif.TxHash),
 			BlockNum:     notif.BlockNum,
-			Content:      fmt.Sprintf("%s %s %s: %s %s (%s)", emoji, notif.Currency, notif.Direction, notif.Amount, notif.Currency, notif.Label),
+			MevType:      mevType,
+			Confidence:   mevConfidence,
+			Content:      content,
 			Status:       notifStatus,
 			ErrorMsg:     errorMsg,
 			PublishType:  "pushplus",
@@ -212,12 +324,13 @@ func (ns *NotificationService) SendTransferNotification(notif *TransferNotificat
 	return nil
 }
 
-// IsProcessed 检查交易是否已处理
+// IsProcessed 检查交易是否已处理（已写入交易流水）
+// wechat_alters 中同一交易还可能有 MEV 告警，不能作为转账是否处理过的依据
 func (ns *NotificationService) IsProcessed(txHash string) bool {
-	if ns.wechatRepo == nil {
+	if ns.transferRepo == nil {
 		return false
 	}
-	return ns.wechatRepo.ExistsByTxHash(txHash)
+	return ns.transferRepo.ExistsByTxHash(strings.ToLower(txHash))
 }
 
 // MevFilter MEV 过滤器
@@ -228,7 +341,7 @@ type MevFilter struct {
 
 // NewMevFilter 创建 MEV 过滤器
 func NewMevFilter(rpcURL string) (*MevFilter, error) {
-	detector, err := utils.NewMevDetector(rpcURL)
+	detector, err := utils.NewMevDetector(rpcURL, database.GetAddressLabelRegistry())
 	if err != nil {
 		return nil, fmt.Errorf("创建 MEV 检测器失败: %w", err)
 	}
@@ -238,27 +351,27 @@ func NewMevFilter(rpcURL string) (*MevFilter, error) {
 	}, nil
 }
 
-// IsMevTransaction 检查是否是 MEV 交易
-func (mf *MevFilter) IsMevTransaction(txHash string) bool {
-	if mf.detector == nil {
-		return false
+// Check 检测交易的 MEV 结论，检测失败时返回 nil（按普通交易处理）
+// 综合置信度达到阈值（MEV_CONFIDENCE_THRESHOLD）时 IsMev 为 true，调用方应只落库不通知
+func (mf *MevFilter) Check(txHash string) *utils.MevDetectionResult {
+	if mf == nil || mf.detector == nil {
+		return nil
 	}
 
 	result, err := mf.detector.DetectMev(txHash)
 	if err != nil {
 		logger.Debug("MEV 检测失败", zap.String("txHash", txHash), zap.Error(err))
-		return false
+		return nil
 	}
 
 	if result.IsMev {
-		logger.Info("检测到 MEV 交易，跳过通知",
+		logger.Info("检测到 MEV 交易，仅记录不通知",
 			zap.String("type", string(result.MevType)),
 			zap.Float64("confidence", result.Confidence),
 			zap.String("txHash", txHash))
-		return true
 	}
 
-	return false
+	return result
 }
 
 // Close 关闭 MEV 过滤器
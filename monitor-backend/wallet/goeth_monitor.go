@@ -169,10 +169,12 @@ func (m *GoEthMonitor) startPollingMonitor(ctx context.Context) error {
 
 // checkBlockTransactions 检查区块中的交易
 func (m *GoEthMonitor) checkBlockTransactions(ctx context.Context, block *types.Block) {
+	blockTime := time.Unix(int64(block.Time()), 0)
+
 	// 检查 ETH 交易
 	for _, tx := range block.Transactions() {
 		if m.isRelatedTransaction(tx) {
-			m.handleETHTransaction(ctx, tx, block.Number().Uint64())
+			m.handleETHTransaction(ctx, tx, block.Number().Uint64(), blockTime)
 		}
 	}
 
@@ -197,7 +199,7 @@ func (m *GoEthMonitor) isRelatedTransaction(tx *types.Transaction) bool {
 }
 
 // handleETHTransaction 处理 ETH 交易
-func (m *GoEthMonitor) handleETHTransaction(ctx context.Context, tx *types.Transaction, blockNum uint64) {
+func (m *GoEthMonitor) handleETHTransaction(ctx context.Context, tx *types.Transaction, blockNum uint64, blockTime time.Time) {
 	txHash := tx.Hash().Hex()
 
 	// 检查是否已处理
@@ -219,12 +221,20 @@ func (m *GoEthMonitor) handleETHTransaction(ctx context.Context, tx *types.Trans
 
 	direction := "转入"
 	targetLabel := ""
+	counterparty := m.addressMgr.DescribeCounterparty(from)
+	monitoredAddr, counterpartyAddr := to, from
 	if fromMonitored {
 		direction = "转出"
 		targetLabel = m.addressMgr.GetLabel(from)
+		counterparty = ""
+		if tx.To() != nil {
+			counterparty = m.addressMgr.DescribeCounterparty(to)
+		}
+		monitoredAddr, counterpartyAddr = from, to
 	} else if toMonitored {
 		targetLabel = m.addressMgr.GetLabel(to)
 	}
+	clusterID, clusterName, intraCluster := m.addressMgr.ClusterContext(monitoredAddr, counterpartyAddr)
 
 	// 计算金额
 	amountStr := WeiToEth(tx.Value())
@@ -235,12 +245,11 @@ func (m *GoEthMonitor) handleETHTransaction(ctx context.Context, tx *types.Trans
 		zap.String("to", toHex),
 		zap.String("amount", amountStr+" ETH"),
 		zap.String("tx", txHash),
-		zap.String("label", targetLabel))
+		zap.String("label", targetLabel),
+		zap.String("counterparty", counterparty))
 
-	// MEV 检测
-	if m.mevFilter != nil && m.mevFilter.IsMevTransaction(txHash) {
-		return
-	}
+	// MEV 检测：判定为 MEV 的交易仍写入流水，只是不发送通知
+	mevResult := m.mevFilter.Check(txHash)
 
 	// 检查是否超过阈值
 	shouldAlert := m.ethThreshold != nil && tx.Value().Cmp(m.ethThreshold) > 0
@@ -255,7 +264,15 @@ func (m *GoEthMonitor) handleETHTransaction(ctx context.Context, tx *types.Trans
 		Currency:    "ETH",
 		TxHash:      txHash,
 		BlockNum:    int(blockNum),
+		BlockTime:   blockTime,
 		ShouldAlert: shouldAlert,
+
+		Counterparty: counterparty,
+		Mev:          mevResult,
+
+		ClusterID:    clusterID,
+		ClusterName:  clusterName,
+		IntraCluster: intraCluster,
 	}
 
 	if err := m.notifSvc.SendTransferNotification(notif); err != nil {
@@ -287,12 +304,12 @@ func (m *GoEthMonitor) checkERC20Transfers(ctx context.Context, block *types.Blo
 	}
 
 	for _, vLog := range logs {
-		m.handleERC20Transfer(vLog, int(block.Number().Uint64()))
+		m.handleERC20Transfer(vLog, int(block.Number().Uint64()), time.Unix(int64(block.Time()), 0))
 	}
 }
 
 // handleERC20Transfer 处理 ERC20 Transfer 事件
-func (m *GoEthMonitor) handleERC20Transfer(vLog types.Log, blockNum int) {
+func (m *GoEthMonitor) handleERC20Transfer(vLog types.Log, blockNum int, blockTime time.Time) {
 	if len(vLog.Topics) < 3 {
 		return
 	}
@@ -325,12 +342,16 @@ func (m *GoEthMonitor) handleERC20Transfer(vLog types.Log, blockNum int) {
 
 	direction := "转入"
 	targetLabel := ""
+	monitoredAddr, counterpartyAddr := to, from
 	if m.addressMgr.IsMonitored(from) {
 		direction = "转出"
 		targetLabel = m.addressMgr.GetLabel(from)
+		monitoredAddr, counterpartyAddr = from, to
 	} else {
 		targetLabel = m.addressMgr.GetLabel(to)
 	}
+	counterparty := m.addressMgr.DescribeCounterparty(counterpartyAddr)
+	clusterID, clusterName, intraCluster := m.addressMgr.ClusterContext(monitoredAddr, counterpartyAddr)
 
 	logger.Info("🔔 检测到代币交易",
 		zap.String("token", tokenConfig.Symbol),
@@ -339,7 +360,11 @@ func (m *GoEthMonitor) handleERC20Transfer(vLog types.Log, blockNum int) {
 		zap.String("to", to.Hex()),
 		zap.String("amount", amountStr+" "+tokenConfig.Symbol),
 		zap.String("tx", txHash),
-		zap.String("label", targetLabel))
+		zap.String("label", targetLabel),
+		zap.String("counterparty", counterparty))
+
+	// MEV 与私有订单流检测：判定为 MEV 的交易仍写入流水，只是不发送通知
+	mevResult := m.mevFilter.Check(txHash)
 
 	// 检查是否超过阈值
 	shouldAlert := m.tokenThreshold != nil && amount.Cmp(m.tokenThreshold) > 0
@@ -354,7 +379,15 @@ func (m *GoEthMonitor) handleERC20Transfer(vLog types.Log, blockNum int) {
 		Currency:    tokenConfig.Symbol,
 		TxHash:      txHash,
 		BlockNum:    blockNum,
+		BlockTime:   blockTime,
 		ShouldAlert: shouldAlert,
+
+		Counterparty: counterparty,
+		Mev:          mevResult,
+
+		ClusterID:    clusterID,
+		ClusterName:  clusterName,
+		IntraCluster: intraCluster,
 	}
 
 	if err := m.notifSvc.SendTransferNotification(notif); err != nil {
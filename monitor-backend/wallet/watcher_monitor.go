@@ -2,13 +2,18 @@ package wallet
 
 import (
 	"context"
+	"ethereum-monitor/database"
 	"ethereum-monitor/logger"
+	"ethereum-monitor/utils"
 	"math/big"
 	"strings"
+	"sync"
+	"time"
 
 	ethereum "github.com/HydroProtocol/ethereum-watcher"
 	"github.com/HydroProtocol/ethereum-watcher/structs"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
 	"go.uber.org/zap"
 )
 
@@ -23,6 +28,12 @@ type WatcherMonitor struct {
 
 	ethThreshold   *big.Int // ETH 转账阈值（Wei 单位），只有超过此金额的交易才会触发通知
 	tokenThreshold *big.Int // ERC20 代币转账阈值（最小单位），只有超过此金额的交易才会触发通知
+
+	// client 查询出块时间（ethereum-watcher 分发的交易与日志不带区块时间），连接失败时为 nil
+	client        *ethclient.Client
+	blockTimeMu   sync.Mutex
+	blockTimeNum  uint64
+	blockTimeLast time.Time
 }
 
 // NewWatcherMonitor 创建 ethereum-watcher 监控器
@@ -42,6 +53,11 @@ func NewWatcherMonitor(rpcURL string, config *MonitorConfig) (*WatcherMonitor, e
 	// 创建代币处理器
 	tokenHandler := NewTokenHandler(config.Tokens)
 
+	client, err := utils.DialRateLimitedEthClient(rpcURL)
+	if err != nil {
+		logger.Warn("连接以太坊节点失败，交易流水不记录出块时间", zap.Error(err))
+	}
+
 	return &WatcherMonitor{
 		addressMgr:     addressMgr,
 		notifSvc:       notifSvc,
@@ -49,14 +65,38 @@ func NewWatcherMonitor(rpcURL string, config *MonitorConfig) (*WatcherMonitor, e
 		tokenHandler:   tokenHandler,
 		ethThreshold:   config.ETHThreshold,
 		tokenThreshold: config.TokenThreshold,
+		client:         client,
 	}, nil
 }
 
+// blockTime 查询出块时间，同一区块的多笔转账只查询一次；失败时返回零值
+func (m *WatcherMonitor) blockTime(number uint64) time.Time {
+	if m.client == nil {
+		return time.Time{}
+	}
+
+	m.blockTimeMu.Lock()
+	defer m.blockTimeMu.Unlock()
+	if number == m.blockTimeNum && !m.blockTimeLast.IsZero() {
+		return m.blockTimeLast
+	}
+
+	header, err := m.client.HeaderByNumber(context.Background(), new(big.Int).SetUint64(number))
+	if err != nil {
+		logger.Warn("获取出块时间失败", zap.Uint64("block", number), zap.Error(err))
+		return time.Time{}
+	}
+	m.blockTimeNum = number
+	m.blockTimeLast = time.Unix(int64(header.Time), 0)
+	return m.blockTimeLast
+}
+
 // Start 启动监控
 func (m *WatcherMonitor) Start(ctx context.Context, rpcURL string, pollInterval int) error {
 	logger.Info("🚀 启动 ethereum-watcher 地址监控",
 		zap.Int("address_count", len(m.addressMgr.addressSet)),
 		zap.Strings("addresses", m.addressMgr.GetLabelList()),
+		zap.Int("entity_members", database.GetAddressClusterRegistry().WatchedMemberCount()),
 		zap.Int("pollInterval", pollInterval))
 
 	// 创建 Watcher
@@ -99,6 +139,9 @@ func (m *WatcherMonitor) Start(ctx context.Context, rpcURL string, pollInterval
 
 // Close 关闭监控器
 func (m *WatcherMonitor) Close() {
+	if m.client != nil {
+		m.client.Close()
+	}
 	if m.mevFilter != nil {
 		m.mevFilter.Close()
 	}
@@ -144,12 +187,16 @@ func (p *ethTransactionPlugin) AcceptTx(tx structs.RemovableTx) {
 	// 判断方向
 	direction := "转入"
 	targetLabel := ""
+	monitoredAddr, counterpartyAddr := toAddr, fromAddr
 	if p.monitor.addressMgr.IsMonitored(fromAddr) {
 		direction = "转出"
 		targetLabel = p.monitor.addressMgr.GetLabel(fromAddr)
+		monitoredAddr, counterpartyAddr = fromAddr, toAddr
 	} else {
 		targetLabel = p.monitor.addressMgr.GetLabel(toAddr)
 	}
+	counterparty := p.monitor.addressMgr.DescribeCounterparty(counterpartyAddr)
+	clusterID, clusterName, intraCluster := p.monitor.addressMgr.ClusterContext(monitoredAddr, counterpartyAddr)
 
 	amountStr := WeiToEth(&value)
 
@@ -159,12 +206,11 @@ func (p *ethTransactionPlugin) AcceptTx(tx structs.RemovableTx) {
 		zap.String("to", tx.GetTo()),
 		zap.String("amount", amountStr+" ETH"),
 		zap.String("tx", txHash),
-		zap.String("label", targetLabel))
+		zap.String("label", targetLabel),
+		zap.String("counterparty", counterparty))
 
-	// MEV 检测
-	if p.monitor.mevFilter != nil && p.monitor.mevFilter.IsMevTransaction(txHash) {
-		return
-	}
+	// MEV 检测：判定为 MEV 的交易仍写入流水，只是不发送通知
+	mevResult := p.monitor.mevFilter.Check(txHash)
 
 	// 发送通知
 	notif := &TransferNotification{
@@ -176,7 +222,15 @@ func (p *ethTransactionPlugin) AcceptTx(tx structs.RemovableTx) {
 		Currency:    "ETH",
 		TxHash:      txHash,
 		BlockNum:    int(tx.GetBlockNumber()),
+		BlockTime:   p.monitor.blockTime(tx.GetBlockNumber()),
 		ShouldAlert: true, // 已经过阈值检查
+
+		Counterparty: counterparty,
+		Mev:          mevResult,
+
+		ClusterID:    clusterID,
+		ClusterName:  clusterName,
+		IntraCluster: intraCluster,
 	}
 
 	if err := p.monitor.notifSvc.SendTransferNotification(notif); err != nil {
@@ -240,12 +294,16 @@ func (p *erc20TransferPlugin) Accept(log *structs.RemovableReceiptLog) {
 	// 判断方向
 	direction := "转入"
 	targetLabel := ""
+	monitoredAddr, counterpartyAddr := toAddr, fromAddr
 	if p.monitor.addressMgr.IsMonitored(fromAddr) {
 		direction = "转出"
 		targetLabel = p.monitor.addressMgr.GetLabel(fromAddr)
+		monitoredAddr, counterpartyAddr = fromAddr, toAddr
 	} else {
 		targetLabel = p.monitor.addressMgr.GetLabel(toAddr)
 	}
+	counterparty := p.monitor.addressMgr.DescribeCounterparty(counterpartyAddr)
+	clusterID, clusterName, intraCluster := p.monitor.addressMgr.ClusterContext(monitoredAddr, counterpartyAddr)
 
 	logger.Info("🔔 检测到代币交易",
 		zap.String("token", tokenConfig.Symbol),
@@ -254,7 +312,11 @@ func (p *erc20TransferPlugin) Accept(log *structs.RemovableReceiptLog) {
 		zap.String("to", to),
 		zap.String("amount", amountStr+" "+tokenConfig.Symbol),
 		zap.String("tx", txHash),
-		zap.String("label", targetLabel))
+		zap.String("label", targetLabel),
+		zap.String("counterparty", counterparty))
+
+	// MEV 与私有订单流检测：判定为 MEV 的交易仍写入流水，只是不发送通知
+	mevResult := p.monitor.mevFilter.Check(txHash)
 
 	// 发送通知
 	notif := &TransferNotification{
@@ -266,7 +328,15 @@ func (p *erc20TransferPlugin) Accept(log *structs.RemovableReceiptLog) {
 		Currency:    tokenConfig.Symbol,
 		TxHash:      txHash,
 		BlockNum:    log.GetBlockNum(),
+		BlockTime:   p.monitor.blockTime(uint64(log.GetBlockNum())),
 		ShouldAlert: true, // 已经过阈值检查
+
+		Counterparty: counterparty,
+		Mev:          mevResult,
+
+		ClusterID:    clusterID,
+		ClusterName:  clusterName,
+		IntraCluster: intraCluster,
 	}
 
 	if err := p.monitor.notifSvc.SendTransferNotification(notif); err != nil {